@@ -20,6 +20,8 @@ import (
 	middlewares "github.com/rajasatyajit/SupplyChain/internal/middleware"
 	"github.com/rajasatyajit/SupplyChain/internal/pipeline"
 	"github.com/rajasatyajit/SupplyChain/internal/store"
+	"github.com/rajasatyajit/SupplyChain/internal/tracing"
+	"github.com/rajasatyajit/SupplyChain/internal/webhook"
 )
 
 // Version information (set by build)
@@ -47,13 +49,24 @@ func main() {
 
 	// Initialize metrics
 	if cfg.Metrics.Enabled {
-		metrics.Init()
+		if err := metrics.Init(metrics.BuildInfo{Version: Version, Commit: GitCommit, BuildTime: BuildTime}); err != nil {
+			logger.Fatal("Failed to initialize metrics", "error", err)
+		}
 		logger.Info("Metrics enabled", "port", cfg.Metrics.Port)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, Version)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", "error", err)
+	}
+	if cfg.Tracing.Enabled {
+		logger.Info("Tracing enabled")
+	}
+
 	// Initialize database
 	db, err := database.New(ctx, cfg.Database)
 	if err != nil {
@@ -61,15 +74,33 @@ func main() {
 	}
 	defer db.Close(ctx)
 
+	if err := database.Migrate(ctx, db); err != nil {
+		logger.Fatal("Failed to migrate database", "error", err)
+	}
+
 	// Initialize store
-	alertStore := store.New(db)
+	// The cache wraps the store for the pipeline too, so upserts invalidate it
+	alertStore := store.NewCachedStore(
+		store.New(db, store.WithMaxAlerts(cfg.Database.MemoryMaxAlerts), store.WithMaxAge(cfg.Database.MemoryMaxAge)),
+		cfg.Server.AlertCacheSize, cfg.Server.AlertCacheTTL,
+	)
+	webhookStore := store.NewWebhookStore(db)
 
 	// Initialize AI components
-	alertClassifier := classifier.New()
-	geo := geocoder.New()
+	alertClassifier, err := newClassifier(cfg.Classifier)
+	if err != nil {
+		logger.Fatal("Failed to initialize classifier", "error", err)
+	}
+	geo := newGeocoder(cfg.Geocoder)
+
+	// Push new alerts to outbound webhooks
+	dispatcher := webhook.NewDispatcher(webhookStore)
+	go dispatcher.Run(ctx)
 
 	// Initialize pipeline
-	alertPipeline := pipeline.New(alertStore, alertClassifier, geo, cfg.Pipeline)
+	alertPipeline := pipeline.New(alertStore, alertClassifier, geo, cfg.Pipeline,
+		pipeline.WithNotifier(dispatcher),
+	)
 
 	// Start pipeline in background
 	go func() {
@@ -83,15 +114,32 @@ func main() {
 
 	// Global middleware
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(middlewares.Logging)
+	r.Use(middlewares.RealIP(cfg.Server.TrustedProxies...))
+	r.Use(middlewares.SampledLogging(logger.EveryN(cfg.Logging.SampleRate)))
 	r.Use(middlewares.Metrics)
+	if cfg.Server.RateLimitRPM > 0 {
+		r.Use(middlewares.RateLimit(cfg.Server.RateLimitRPM, cfg.Server.TrustedProxies...))
+	}
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(cfg.Server.ReadTimeout))
 	r.Use(middlewares.Security)
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		r.Use(middlewares.CORS(cfg.CORS.AllowedOrigins,
+			middlewares.WithRoutes(r),
+			middlewares.WithAllowedHeaders(cfg.CORS.AllowedHeaders...),
+		))
+	}
 
 	// Initialize API handlers
-	apiHandler := api.NewHandler(alertStore, Version, BuildTime, GitCommit)
+	apiHandler := api.NewHandler(alertStore, Version, BuildTime, GitCommit,
+		api.WithPipelineStatus(alertPipeline.IsRunning, false),
+		api.WithAdmin(cfg.Server.AdminSecret, alertPipeline),
+		api.WithWebhooks(webhookStore),
+		api.WithSources(alertPipeline),
+		api.WithMaxBodyBytes(cfg.Server.MaxBodyBytes),
+		api.WithMaxQueryWindow(cfg.Server.MaxQueryWindow),
+		api.WithTimeouts(cfg.Server.ReadRouteTimeout, cfg.Server.RequestTimeout),
+		api.WithQueryTimeout(cfg.Server.QueryTimeout),
+	)
 	apiHandler.RegisterRoutes(r)
 
 	// Metrics endpoint
@@ -128,13 +176,48 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.GracefulShutdownTimeout)
 	defer shutdownCancel()
 
+	if err := alertPipeline.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Pipeline forced to shutdown", "error", err)
+	}
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
 
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Failed to flush traces", "error", err)
+	}
+
 	logger.Info("Server exited")
 }
 
+// newClassifier builds the classifier, loading keyword sets when a path is configured
+func newClassifier(cfg config.ClassifierConfig) (*classifier.Classifier, error) {
+	if cfg.KeywordsPath == "" {
+		return classifier.New(), nil
+	}
+
+	sets, err := classifier.LoadKeywordSets(cfg.KeywordsPath)
+	if err != nil {
+		return nil, fmt.Errorf("load classifier keywords: %w", err)
+	}
+	logger.Info("Classifier keywords loaded", "path", cfg.KeywordsPath)
+
+	return classifier.NewWithConfig(sets), nil
+}
+
+// newGeocoder builds the geocoder, resolving coordinates when a provider is configured
+func newGeocoder(cfg config.GeocoderConfig) *geocoder.Geocoder {
+	switch cfg.Provider {
+	case "nominatim":
+		logger.Info("Geocoding enabled", "provider", cfg.Provider, "base_url", cfg.BaseURL)
+		provider := geocoder.NewNominatimProvider(cfg.BaseURL, cfg.Timeout)
+		return geocoder.NewWithProvider(geocoder.NewCachedProvider(provider, cfg.CacheSize))
+	default:
+		return geocoder.New()
+	}
+}
+
 func startMetricsServer(port int, path string) {
 	mux := http.NewServeMux()
 	mux.Handle(path, metrics.Handler())
@@ -1,19 +1,26 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Pipeline PipelineConfig
-	Logging  LoggingConfig
-	Metrics  MetricsConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Pipeline   PipelineConfig
+	Logging    LoggingConfig
+	Metrics    MetricsConfig
+	Geocoder   GeocoderConfig
+	Classifier ClassifierConfig
+	CORS       CORSConfig
+	Tracing    TracingConfig
 }
 
 type ServerConfig struct {
@@ -23,27 +30,170 @@ type ServerConfig struct {
 	WriteTimeout            time.Duration
 	IdleTimeout             time.Duration
 	GracefulShutdownTimeout time.Duration
+	AdminSecret             string        // bearer token for /v1/admin endpoints; empty disables them
+	MaxBodyBytes            int64         // cap on JSON request bodies
+	MaxQueryWindow          time.Duration // widest since/until range a query may ask for; 0 is unlimited
+	AlertCacheSize          int           // alerts cached for GET /v1/alerts/{id}; 0 disables the cache
+	AlertCacheTTL           time.Duration
+	ReadRouteTimeout        time.Duration  // cap on alert and info reads; 0 is unlimited
+	RequestTimeout          time.Duration  // cap on other routes except manual pipeline runs; 0 is unlimited
+	QueryTimeout            time.Duration  // cap on each alert listing query; 0 is unlimited
+	RateLimitRPM            int            // requests per minute allowed per client; 0 disables rate limiting
+	TrustedProxies          []netip.Prefix // peers whose X-Forwarded-For and X-Real-IP headers are honored
 }
 
 type DatabaseConfig struct {
 	URL             string
+	ReadURL         string
 	MaxConns        int
 	MinConns        int
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+	RetryAttempts   int
+	MemoryMaxAlerts int           // alerts kept without DATABASE_URL; 0 is unbounded
+	MemoryMaxAge    time.Duration // age at which alerts are dropped without DATABASE_URL; 0 is unbounded
 }
 
 type PipelineConfig struct {
-	RateLimit     float64
-	WorkerCount   int
-	BatchSize     int
-	RetryAttempts int
-	RetryDelay    time.Duration
+	RateLimit             float64
+	WorkerCount           int
+	BatchSize             int
+	RetryAttempts         int
+	RetryDelay            time.Duration
+	RetentionDays         int // alerts older than this are archived daily; 0 disables
+	BreakerThreshold      int // consecutive failures before a source's polls are paused; 0 disables
+	BreakerCooldown       time.Duration
+	DedupKey              string  // fields hashed into alert IDs; one of the Dedup* constants
+	GeocodeFailurePenalty float64 // multiplies the confidence of alerts that fail to geocode, from 0 to 1; 0 is unset and uses 0.8, 1 disables
+	Impact                ImpactWeights
+	Sources               []SourceConfig
+}
+
+// ImpactWeights score how much an alert matters to supply-chain planners.
+// An alert's impact is its severity (high 1, medium 2/3, low 1/3) times
+// the weight of its disruption and of its region, each between 0 and 1.
+// Disruptions and regions missing from a table weigh 0.
+type ImpactWeights struct {
+	Disruptions map[string]float64 `json:"disruptions"`
+	Regions     map[string]float64 `json:"regions"`
+}
+
+// DefaultImpactWeights returns the weights used when none are configured.
+// Regions are the continents the geocoder assigns.
+func DefaultImpactWeights() ImpactWeights {
+	return ImpactWeights{
+		Disruptions: map[string]float64{
+			"port_status":  1.0,
+			"labor_strike": 0.9,
+			"weather":      0.8,
+			"cyber":        0.8,
+			"air":          0.7,
+			"rail":         0.7,
+			"customs":      0.6,
+			"road":         0.6,
+			"regulatory":   0.5,
+			"general":      0.4,
+		},
+		Regions: map[string]float64{
+			"Asia":          1.0,
+			"Europe":        0.9,
+			"North America": 0.9,
+			"Africa":        0.6,
+			"South America": 0.6,
+			"Oceania":       0.5,
+			"Antarctica":    0.1,
+		},
+	}
+}
+
+// Alert ID derivations for PipelineConfig.DedupKey. Including the published
+// date makes re-published items with a new pubDate distinct alerts.
+const (
+	DedupURL             = "url"
+	DedupURLTitle        = "url+title"
+	DedupURLTitlePubDate = "url+title+pubdate"
+)
+
+// SourceConfig describes a single ingestion source.
+// A zero Interval or RateLimit uses the source type's default or the
+// pipeline-wide rate limit respectively; a zero MaxBodyBytes uses the
+// default feed size cap. Headers are sent with every fetch and override
+// the default User-Agent and Accept headers.
+type SourceConfig struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	URLs         []string          `json:"urls"`
+	Interval     time.Duration     `json:"interval"`
+	RateLimit    float64           `json:"rate_limit"`
+	MaxBodyBytes int64             `json:"max_body_bytes"`
+	Headers      map[string]string `json:"headers"`
+}
+
+// sourceTypes are the SourceConfig types the pipeline can construct
+var sourceTypes = map[string]bool{"rss": true, "atom": true, "jsonfeed": true}
+
+// UnmarshalJSON accepts the interval as a duration string such as "15m"
+func (s *SourceConfig) UnmarshalJSON(data []byte) error {
+	type alias SourceConfig
+	aux := struct {
+		*alias
+		Interval string `json:"interval"`
+	}{alias: (*alias)(s)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Interval != "" {
+		interval, err := time.ParseDuration(aux.Interval)
+		if err != nil {
+			return fmt.Errorf("source %q: invalid interval %q: %w", s.Name, aux.Interval, err)
+		}
+		s.Interval = interval
+	}
+
+	return nil
+}
+
+// DefaultSources returns the sources used when none are configured
+func DefaultSources() []SourceConfig {
+	return []SourceConfig{
+		{
+			Name: "Global Shipping News",
+			Type: "rss",
+			URLs: []string{"https://news.un.org/feed/subscribe/en/news/region/africa/feed/rss.xml"},
+		},
+	}
+}
+
+type GeocoderConfig struct {
+	Provider  string // empty for regex-only extraction, or "nominatim"
+	BaseURL   string
+	Timeout   time.Duration
+	CacheSize int
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string // origins allowed to call the API from a browser; "*" allows any
+	AllowedHeaders []string // request headers allowed beyond Content-Type and Authorization
+}
+
+// TracingConfig enables OpenTelemetry tracing. The exporter is configured by
+// the standard OTEL_EXPORTER_OTLP_* and OTEL_SERVICE_NAME variables.
+type TracingConfig struct {
+	Enabled bool
+}
+
+type ClassifierConfig struct {
+	KeywordsPath string // JSON keyword sets overriding the built-in defaults
 }
 
 type LoggingConfig struct {
 	Level  string
 	Format string // json or text
+	// SampleRate logs one in every SampleRate successful HTTP requests;
+	// failed requests are always logged
+	SampleRate int
 }
 
 type MetricsConfig struct {
@@ -62,32 +212,85 @@ func Load() (*Config, error) {
 			WriteTimeout:            getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
 			IdleTimeout:             getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
 			GracefulShutdownTimeout: getEnvDuration("SERVER_GRACEFUL_SHUTDOWN_TIMEOUT", 30*time.Second),
+			AdminSecret:             getEnv("ADMIN_SECRET", ""),
+			MaxBodyBytes:            int64(getEnvInt("SERVER_MAX_BODY_BYTES", 1<<20)),
+			MaxQueryWindow:          getEnvDuration("SERVER_MAX_QUERY_WINDOW", 0),
+			AlertCacheSize:          getEnvInt("SERVER_ALERT_CACHE_SIZE", 0),
+			AlertCacheTTL:           getEnvDuration("SERVER_ALERT_CACHE_TTL", time.Minute),
+			ReadRouteTimeout:        getEnvDuration("SERVER_READ_ROUTE_TIMEOUT", 10*time.Second),
+			RequestTimeout:          getEnvDuration("SERVER_REQUEST_TIMEOUT", 30*time.Second),
+			QueryTimeout:            getEnvDuration("SERVER_QUERY_TIMEOUT", 5*time.Second),
+			RateLimitRPM:            getEnvInt("SERVER_RATE_LIMIT_RPM", 0),
 		},
 		Database: DatabaseConfig{
 			URL:             getEnv("DATABASE_URL", ""),
+			ReadURL:         getEnv("DATABASE_READ_URL", ""),
 			MaxConns:        getEnvInt("DB_MAX_CONNS", 25),
 			MinConns:        getEnvInt("DB_MIN_CONNS", 5),
 			MaxConnLifetime: getEnvDuration("DB_MAX_CONN_LIFETIME", 1*time.Hour),
 			MaxConnIdleTime: getEnvDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute),
+			RetryAttempts:   getEnvInt("DB_RETRY_ATTEMPTS", 2),
+			MemoryMaxAlerts: getEnvInt("MEMORY_STORE_MAX_ALERTS", 0),
+			MemoryMaxAge:    getEnvDuration("MEMORY_STORE_MAX_AGE", 0),
 		},
 		Pipeline: PipelineConfig{
-			RateLimit:     getEnvFloat("PIPELINE_RATE_LIMIT", 5.0),
-			WorkerCount:   getEnvInt("PIPELINE_WORKER_COUNT", 4),
-			BatchSize:     getEnvInt("PIPELINE_BATCH_SIZE", 100),
-			RetryAttempts: getEnvInt("PIPELINE_RETRY_ATTEMPTS", 3),
-			RetryDelay:    getEnvDuration("PIPELINE_RETRY_DELAY", 5*time.Second),
+			RateLimit:             getEnvFloat("PIPELINE_RATE_LIMIT", 5.0),
+			WorkerCount:           getEnvInt("PIPELINE_WORKER_COUNT", 4),
+			BatchSize:             getEnvInt("PIPELINE_BATCH_SIZE", 100),
+			RetryAttempts:         getEnvInt("PIPELINE_RETRY_ATTEMPTS", 3),
+			RetryDelay:            getEnvDuration("PIPELINE_RETRY_DELAY", 5*time.Second),
+			RetentionDays:         getEnvInt("PIPELINE_RETENTION_DAYS", 0),
+			BreakerThreshold:      getEnvInt("PIPELINE_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:       getEnvDuration("PIPELINE_BREAKER_COOLDOWN", time.Minute),
+			DedupKey:              getEnv("PIPELINE_DEDUP_KEY", DedupURLTitle),
+			GeocodeFailurePenalty: getEnvFloat("PIPELINE_GEOCODE_FAILURE_PENALTY", 0.8),
 		},
 		Logging: LoggingConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:      getEnv("LOG_LEVEL", "info"),
+			Format:     getEnv("LOG_FORMAT", "json"),
+			SampleRate: getEnvInt("LOG_SAMPLE_RATE", 1),
 		},
 		Metrics: MetricsConfig{
 			Enabled: getEnvBool("METRICS_ENABLED", true),
 			Port:    getEnvInt("METRICS_PORT", 9090),
 			Path:    getEnv("METRICS_PATH", "/metrics"),
 		},
+		Geocoder: GeocoderConfig{
+			Provider:  getEnv("GEOCODER_PROVIDER", ""),
+			BaseURL:   getEnv("GEOCODER_BASE_URL", "https://nominatim.openstreetmap.org"),
+			Timeout:   getEnvDuration("GEOCODER_TIMEOUT", 10*time.Second),
+			CacheSize: getEnvInt("GEOCODER_CACHE_SIZE", 1000),
+		},
+		Classifier: ClassifierConfig{
+			KeywordsPath: getEnv("CLASSIFIER_KEYWORDS_PATH", ""),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS"),
+			AllowedHeaders: getEnvList("CORS_ALLOWED_HEADERS"),
+		},
+		Tracing: TracingConfig{
+			Enabled: getEnvBool("OTEL_ENABLED", false),
+		},
 	}
 
+	sources, err := loadSources()
+	if err != nil {
+		return nil, fmt.Errorf("load pipeline sources: %w", err)
+	}
+	cfg.Pipeline.Sources = sources
+
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		return nil, fmt.Errorf("load trusted proxies: %w", err)
+	}
+	cfg.Server.TrustedProxies = proxies
+
+	impact, err := loadImpactWeights()
+	if err != nil {
+		return nil, fmt.Errorf("load impact weights: %w", err)
+	}
+	cfg.Pipeline.Impact = impact
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -100,15 +303,182 @@ func (c *Config) Validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
+	if c.Server.MaxBodyBytes < 0 {
+		return fmt.Errorf("server max body bytes must not be negative")
+	}
+	if c.Server.MaxQueryWindow < 0 {
+		return fmt.Errorf("server max query window must not be negative")
+	}
+	if c.Server.AlertCacheSize < 0 {
+		return fmt.Errorf("server alert cache size must not be negative")
+	}
+	if c.Server.AlertCacheSize > 0 && c.Server.AlertCacheTTL <= 0 {
+		return fmt.Errorf("server alert cache TTL must be positive")
+	}
+	if c.Server.ReadRouteTimeout < 0 || c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server route timeouts must not be negative")
+	}
+	if c.Server.QueryTimeout < 0 {
+		return fmt.Errorf("server query timeout must not be negative")
+	}
+	if c.Server.RateLimitRPM < 0 {
+		return fmt.Errorf("server rate limit must not be negative")
+	}
 	if c.Database.MaxConns < 1 {
 		return fmt.Errorf("database max connections must be at least 1")
 	}
+	if c.Database.MemoryMaxAlerts < 0 || c.Database.MemoryMaxAge < 0 {
+		return fmt.Errorf("memory store limits must not be negative")
+	}
+	if c.Database.ReadURL != "" && c.Database.URL == "" {
+		return fmt.Errorf("DATABASE_READ_URL requires DATABASE_URL")
+	}
+	if c.Database.RetryAttempts < 0 {
+		return fmt.Errorf("database retry attempts must not be negative")
+	}
+	if c.Logging.SampleRate < 0 {
+		return fmt.Errorf("log sample rate must not be negative")
+	}
 	if c.Pipeline.WorkerCount < 1 {
 		return fmt.Errorf("pipeline worker count must be at least 1")
 	}
+	switch c.Geocoder.Provider {
+	case "", "nominatim":
+	default:
+		return fmt.Errorf("unsupported geocoder provider: %s", c.Geocoder.Provider)
+	}
+	if c.Pipeline.RetentionDays < 0 {
+		return fmt.Errorf("pipeline retention days must not be negative")
+	}
+	if c.Pipeline.BreakerThreshold < 0 {
+		return fmt.Errorf("pipeline breaker threshold must not be negative")
+	}
+	if c.Pipeline.BreakerThreshold > 0 && c.Pipeline.BreakerCooldown <= 0 {
+		return fmt.Errorf("pipeline breaker cooldown must be positive")
+	}
+	switch c.Pipeline.DedupKey {
+	case "", DedupURL, DedupURLTitle, DedupURLTitlePubDate:
+	default:
+		return fmt.Errorf("unsupported pipeline dedup key: %s", c.Pipeline.DedupKey)
+	}
+	if c.Pipeline.GeocodeFailurePenalty < 0 || c.Pipeline.GeocodeFailurePenalty > 1 {
+		return fmt.Errorf("pipeline geocode failure penalty must be between 0 and 1")
+	}
+	for table, weights := range map[string]map[string]float64{"disruption": c.Pipeline.Impact.Disruptions, "region": c.Pipeline.Impact.Regions} {
+		for key, weight := range weights {
+			if weight < 0 || weight > 1 {
+				return fmt.Errorf("pipeline impact %s weight for %q must be between 0 and 1", table, key)
+			}
+		}
+	}
+	names := make(map[string]bool, len(c.Pipeline.Sources))
+	for i, src := range c.Pipeline.Sources {
+		if src.Name == "" {
+			return fmt.Errorf("pipeline source %d: name is required", i)
+		}
+		if names[src.Name] {
+			return fmt.Errorf("pipeline source %q: name is used by another source", src.Name)
+		}
+		names[src.Name] = true
+		if src.Type == "" {
+			return fmt.Errorf("pipeline source %q: type is required", src.Name)
+		}
+		if !sourceTypes[src.Type] {
+			return fmt.Errorf("pipeline source %q: unsupported type %q", src.Name, src.Type)
+		}
+		if len(src.URLs) == 0 {
+			return fmt.Errorf("pipeline source %q: at least one URL is required", src.Name)
+		}
+		if src.RateLimit < 0 {
+			return fmt.Errorf("pipeline source %q: rate limit must not be negative", src.Name)
+		}
+		if src.MaxBodyBytes < 0 {
+			return fmt.Errorf("pipeline source %q: max body bytes must not be negative", src.Name)
+		}
+		for name, value := range src.Headers {
+			if name == "" || strings.ContainsAny(name, " :\r\n") || strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("pipeline source %q: invalid header %q", src.Name, name)
+			}
+		}
+	}
 	return nil
 }
 
+// loadSources reads pipeline sources as JSON from PIPELINE_SOURCES, or from the
+// file named by PIPELINE_SOURCES_FILE, falling back to DefaultSources
+func loadSources() ([]SourceConfig, error) {
+	data := []byte(os.Getenv("PIPELINE_SOURCES"))
+	if len(data) == 0 {
+		if path := os.Getenv("PIPELINE_SOURCES_FILE"); path != "" {
+			fileData, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", path, err)
+			}
+			data = fileData
+		}
+	}
+
+	if len(data) == 0 {
+		return DefaultSources(), nil
+	}
+
+	var sources []SourceConfig
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("parse sources: %w", err)
+	}
+	if len(sources) == 0 {
+		return DefaultSources(), nil
+	}
+
+	return sources, nil
+}
+
+// loadImpactWeights reads impact weights as JSON from PIPELINE_IMPACT_WEIGHTS.
+// A table left out of the JSON keeps its DefaultImpactWeights value.
+func loadImpactWeights() (ImpactWeights, error) {
+	defaults := DefaultImpactWeights()
+	data := os.Getenv("PIPELINE_IMPACT_WEIGHTS")
+	if data == "" {
+		return defaults, nil
+	}
+
+	var weights ImpactWeights
+	if err := json.Unmarshal([]byte(data), &weights); err != nil {
+		return ImpactWeights{}, fmt.Errorf("parse impact weights: %w", err)
+	}
+	if weights.Disruptions == nil {
+		weights.Disruptions = defaults.Disruptions
+	}
+	if weights.Regions == nil {
+		weights.Regions = defaults.Regions
+	}
+
+	return weights, nil
+}
+
+// loadTrustedProxies reads SERVER_TRUSTED_PROXIES, a comma-separated list of
+// CIDRs or single addresses
+func loadTrustedProxies() ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range getEnvList("SERVER_TRUSTED_PROXIES") {
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("parse trusted proxy %q: %w", item, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", item, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
 // Helper functions for environment variable parsing
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -144,6 +514,17 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvList splits a comma-separated variable, dropping empty entries
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := time.ParseDuration(value); err == nil {
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"net/netip"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -84,6 +87,185 @@ func TestLoad(t *testing.T) {
 	})
 }
 
+func TestLoadSources(t *testing.T) {
+	t.Run("Default sources", func(t *testing.T) {
+		t.Setenv("PIPELINE_SOURCES", "")
+		t.Setenv("PIPELINE_SOURCES_FILE", "")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(cfg.Pipeline.Sources) != 1 || cfg.Pipeline.Sources[0].Name != "Global Shipping News" {
+			t.Errorf("Expected default UN feed source, got %+v", cfg.Pipeline.Sources)
+		}
+	})
+
+	t.Run("Sources from env", func(t *testing.T) {
+		t.Setenv("PIPELINE_SOURCES", `[{"name":"Ports","type":"rss","urls":["http://a","http://b"],"interval":"2m","rate_limit":0.5,"headers":{"Authorization":"Bearer t"}}]`)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(cfg.Pipeline.Sources) != 1 {
+			t.Fatalf("Expected 1 source, got %d", len(cfg.Pipeline.Sources))
+		}
+		src := cfg.Pipeline.Sources[0]
+		if src.Name != "Ports" || src.Type != "rss" || len(src.URLs) != 2 || src.Interval != 2*time.Minute || src.RateLimit != 0.5 || src.Headers["Authorization"] != "Bearer t" {
+			t.Errorf("Unexpected source: %+v", src)
+		}
+	})
+
+	t.Run("Sources from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sources.json")
+		if err := os.WriteFile(path, []byte(`[{"name":"File Feed","type":"rss","urls":["http://c"]}]`), 0o600); err != nil {
+			t.Fatalf("Failed to write sources file: %v", err)
+		}
+		t.Setenv("PIPELINE_SOURCES", "")
+		t.Setenv("PIPELINE_SOURCES_FILE", path)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(cfg.Pipeline.Sources) != 1 || cfg.Pipeline.Sources[0].Name != "File Feed" {
+			t.Errorf("Expected source from file, got %+v", cfg.Pipeline.Sources)
+		}
+	})
+
+	t.Run("Invalid sources", func(t *testing.T) {
+		for _, value := range []string{
+			`not json`,
+			`[{"name":"Bad","type":"rss","urls":["http://a"],"interval":"soon"}]`,
+			`[{"name":"No URLs","type":"rss"}]`,
+			`[{"name":"Unknown type","type":"csv","urls":["http://a"]}]`,
+			`[{"name":"Twice","type":"rss","urls":["http://a"]},{"name":"Twice","type":"jsonfeed","urls":["http://b"]}]`,
+			`[{"name":"Negative","type":"rss","urls":["http://a"],"rate_limit":-1}]`,
+			`[{"name":"Negative size","type":"rss","urls":["http://a"],"max_body_bytes":-1}]`,
+			`[{"name":"Bad header","type":"rss","urls":["http://a"],"headers":{"X-Token":"a\r\nHost: evil"}}]`,
+			`[{"name":"Bad header name","type":"rss","urls":["http://a"],"headers":{"X Token":"a"}}]`,
+		} {
+			t.Setenv("PIPELINE_SOURCES", value)
+			if _, err := Load(); err == nil {
+				t.Errorf("Expected error for %s", value)
+			}
+		}
+	})
+}
+
+func TestLoadImpactWeights(t *testing.T) {
+	t.Run("Default weights", func(t *testing.T) {
+		t.Setenv("PIPELINE_IMPACT_WEIGHTS", "")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(cfg.Pipeline.Impact, DefaultImpactWeights()) {
+			t.Errorf("Expected default impact weights, got %+v", cfg.Pipeline.Impact)
+		}
+	})
+
+	t.Run("Weights from env", func(t *testing.T) {
+		t.Setenv("PIPELINE_IMPACT_WEIGHTS", `{"regions":{"Europe":1,"Asia":0.5}}`)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if want := map[string]float64{"Europe": 1, "Asia": 0.5}; !reflect.DeepEqual(cfg.Pipeline.Impact.Regions, want) {
+			t.Errorf("Expected regions %v, got %v", want, cfg.Pipeline.Impact.Regions)
+		}
+		if !reflect.DeepEqual(cfg.Pipeline.Impact.Disruptions, DefaultImpactWeights().Disruptions) {
+			t.Errorf("Expected omitted disruptions to keep defaults, got %v", cfg.Pipeline.Impact.Disruptions)
+		}
+	})
+
+	t.Run("Invalid weights", func(t *testing.T) {
+		for _, value := range []string{
+			`not json`,
+			`{"disruptions":{"weather":1.5}}`,
+			`{"regions":{"Asia":-0.1}}`,
+		} {
+			t.Setenv("PIPELINE_IMPACT_WEIGHTS", value)
+			if _, err := Load(); err == nil {
+				t.Errorf("Expected error for %s", value)
+			}
+		}
+	})
+}
+
+func TestLoadGeocoder(t *testing.T) {
+	t.Setenv("GEOCODER_PROVIDER", "nominatim")
+	t.Setenv("GEOCODER_TIMEOUT", "3s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Geocoder.Provider != "nominatim" || cfg.Geocoder.Timeout != 3*time.Second || cfg.Geocoder.CacheSize != 1000 {
+		t.Errorf("Unexpected geocoder config: %+v", cfg.Geocoder)
+	}
+
+	t.Setenv("GEOCODER_PROVIDER", "carrier-pigeon")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for unsupported geocoder provider")
+	}
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	t.Setenv("SERVER_TRUSTED_PROXIES", "10.1.2.3/8, 192.168.0.1, ::ffff:172.16.0.1,2001:db8::/32")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.0.1/32"),
+		netip.MustParsePrefix("172.16.0.1/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+	if !reflect.DeepEqual(cfg.Server.TrustedProxies, want) {
+		t.Errorf("Expected trusted proxies %v, got %v", want, cfg.Server.TrustedProxies)
+	}
+
+	for _, value := range []string{"10.0.0.0/33", "proxy.internal"} {
+		t.Setenv("SERVER_TRUSTED_PROXIES", value)
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for %s", value)
+		}
+	}
+}
+
+func TestLoadCORS(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 0 {
+		t.Errorf("Expected no allowed origins by default, got %v", cfg.CORS.AllowedOrigins)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://app.example.com, ,https://admin.example.com ")
+	t.Setenv("CORS_ALLOWED_HEADERS", "X-Client-Type")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []string{"https://app.example.com", "https://admin.example.com"}
+	if !reflect.DeepEqual(cfg.CORS.AllowedOrigins, want) {
+		t.Errorf("Expected allowed origins %v, got %v", want, cfg.CORS.AllowedOrigins)
+	}
+	if !reflect.DeepEqual(cfg.CORS.AllowedHeaders, []string{"X-Client-Type"}) {
+		t.Errorf("Expected allowed headers [X-Client-Type], got %v", cfg.CORS.AllowedHeaders)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -135,6 +317,56 @@ func TestValidate(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Read replica without primary",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+					ReadURL:  "postgres://replica/supplychain",
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative database retry attempts",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns:      10,
+					RetryAttempts: -1,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative log sample rate",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Logging: LoggingConfig{
+					SampleRate: -1,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
 		{
 			name: "Invalid worker count",
 			config: Config{
@@ -150,6 +382,198 @@ func TestValidate(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Breaker without cooldown",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount:      4,
+					BreakerThreshold: 3,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Unsupported dedup key",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+					DedupKey:    "guid",
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative max query window",
+			config: Config{
+				Server: ServerConfig{
+					Port:           8080,
+					MaxQueryWindow: -time.Hour,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative alert cache size",
+			config: Config{
+				Server: ServerConfig{
+					Port:           8080,
+					AlertCacheSize: -1,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Alert cache without a TTL",
+			config: Config{
+				Server: ServerConfig{
+					Port:           8080,
+					AlertCacheSize: 100,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative memory store max alerts",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns:        10,
+					MemoryMaxAlerts: -1,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative request timeout",
+			config: Config{
+				Server: ServerConfig{
+					Port:           8080,
+					RequestTimeout: -time.Second,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative rate limit",
+			config: Config{
+				Server: ServerConfig{
+					Port:         8080,
+					RateLimitRPM: -1,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative query timeout",
+			config: Config{
+				Server: ServerConfig{
+					Port:         8080,
+					QueryTimeout: -time.Second,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount: 4,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Geocode failure penalty unset",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount:           4,
+					GeocodeFailurePenalty: 0,
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Negative geocode failure penalty",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount:           4,
+					GeocodeFailurePenalty: -0.1,
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Geocode failure penalty above one",
+			config: Config{
+				Server: ServerConfig{
+					Port: 8080,
+				},
+				Database: DatabaseConfig{
+					MaxConns: 10,
+				},
+				Pipeline: PipelineConfig{
+					WorkerCount:           4,
+					GeocodeFailurePenalty: 1.5,
+				},
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
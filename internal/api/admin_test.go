@@ -0,0 +1,324 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/pipeline"
+)
+
+// mockRunner implements PipelineRunner for testing
+type mockRunner struct {
+	counts map[string]int
+	err    error
+	ran    []string
+	stats  []pipeline.SourceStatus
+}
+
+func (m *mockRunner) Stats() []pipeline.SourceStatus {
+	return m.stats
+}
+
+func (m *mockRunner) RunSourceNow(ctx context.Context, name string) (int, error) {
+	m.ran = append(m.ran, name)
+	if m.err != nil {
+		return 0, m.err
+	}
+	count, ok := m.counts[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", pipeline.ErrSourceNotFound, name)
+	}
+	return count, nil
+}
+
+func TestHandler_RunPipeline(t *testing.T) {
+	logger.Init("error", "text")
+
+	tests := []struct {
+		name           string
+		auth           string
+		body           string
+		runErr         error
+		expectedStatus int
+		expectedCode   apperrors.Code
+	}{
+		{"Runs source", "Bearer secret", `{"source":"Ports"}`, nil, http.StatusOK, ""},
+		{"Missing token", "", `{"source":"Ports"}`, nil, http.StatusUnauthorized, apperrors.CodeUnauthorized},
+		{"Wrong token", "Bearer wrong", `{"source":"Ports"}`, nil, http.StatusUnauthorized, apperrors.CodeUnauthorized},
+		{"Unknown source", "Bearer secret", `{"source":"Nope"}`, nil, http.StatusNotFound, apperrors.CodeSourceNotFound},
+		{"Missing source", "Bearer secret", `{}`, nil, http.StatusBadRequest, apperrors.CodeInvalidInput},
+		{"Invalid body", "Bearer secret", `{`, nil, http.StatusBadRequest, apperrors.CodeInvalidInput},
+		{"Run failure", "Bearer secret", `{"source":"Ports"}`, errors.New("fetch failed"), http.StatusInternalServerError, apperrors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockRunner{counts: map[string]int{"Ports": 3}, err: tt.runErr}
+			handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+				WithAdmin("secret", runner),
+			)
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest("POST", "/v1/admin/pipeline/run", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedCode != "" {
+				var response ErrorResponse
+				json.NewDecoder(w.Body).Decode(&response)
+				if response.Code != tt.expectedCode {
+					t.Errorf("Expected code %s, got %s", tt.expectedCode, response.Code)
+				}
+				return
+			}
+
+			var response struct {
+				Source    string `json:"source"`
+				Processed int    `json:"processed"`
+			}
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Source != "Ports" || response.Processed != 3 {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestHandler_PipelineStatus(t *testing.T) {
+	lastRun := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	runner := &mockRunner{stats: []pipeline.SourceStatus{
+		{Name: "Ports", LastRunAt: &lastRun, LastCount: 4},
+		{Name: "Customs", LastRunAt: &lastRun, LastError: "HTTP 503", ConsecutiveFailures: 3},
+	}}
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", runner),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/admin/pipeline/status", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without token, got %d", w.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Data  []pipeline.SourceStatus `json:"data"`
+		Count int                     `json:"count"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Count != 2 || response.Data[1].Name != "Customs" || response.Data[1].ConsecutiveFailures != 3 || response.Data[1].LastError != "HTTP 503" {
+		t.Errorf("Unexpected status response: %+v", response)
+	}
+}
+
+func TestHandler_AdminDisabledWithoutSecret(t *testing.T) {
+	runner := &mockRunner{counts: map[string]int{"Ports": 3}}
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("", runner),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/v1/admin/pipeline/run", strings.NewReader(`{"source":"Ports"}`))
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound || len(runner.ran) != 0 {
+		t.Errorf("Expected admin route to be unmounted, got status %d and runs %v", w.Code, runner.ran)
+	}
+}
+
+func TestHandler_ValidateSource(t *testing.T) {
+	logger.Init("error", "text")
+
+	var items strings.Builder
+	for i := 0; i < 7; i++ {
+		fmt.Fprintf(&items, `<item><title>Port closure %d</title><link>https://example.com/%d</link><description>Strike</description></item>`, i, i)
+	}
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title>%s</channel></rss>`, items.String())
+	}))
+	defer feed.Close()
+
+	tests := []struct {
+		name           string
+		auth           string
+		body           string
+		expectedStatus int
+		expectedCode   apperrors.Code
+		wantCount      int
+		wantSample     int
+	}{
+		{"Parses feed", "Bearer secret", `{"type":"rss","urls":["` + feed.URL + `"]}`, http.StatusOK, "", 7, validateSampleSize},
+		{"Wrong parser", "Bearer secret", `{"type":"jsonfeed","urls":["` + feed.URL + `"]}`, http.StatusUnprocessableEntity, apperrors.CodeInvalidInput, 0, 0},
+		{"Missing token", "", `{"type":"rss","urls":["` + feed.URL + `"]}`, http.StatusUnauthorized, apperrors.CodeUnauthorized, 0, 0},
+		{"Unsupported type", "Bearer secret", `{"type":"csv","urls":["` + feed.URL + `"]}`, http.StatusBadRequest, apperrors.CodeInvalidInput, 0, 0},
+		{"Missing urls", "Bearer secret", `{"type":"rss"}`, http.StatusBadRequest, apperrors.CodeInvalidInput, 0, 0},
+		{"Relative url", "Bearer secret", `{"type":"rss","urls":["/feed"]}`, http.StatusBadRequest, apperrors.CodeInvalidInput, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMockStore()
+			handler := NewHandler(store, "test-version", "test-build-time", "test-commit",
+				WithAdmin("secret", nil),
+			)
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest("POST", "/v1/admin/sources/validate", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != "" {
+				var errResp ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+					t.Fatalf("Failed to decode error response: %v", err)
+				}
+				if errResp.Code != tt.expectedCode {
+					t.Errorf("Expected code %q, got %q", tt.expectedCode, errResp.Code)
+				}
+				return
+			}
+
+			var response struct {
+				Count  int               `json:"count"`
+				Sample []json.RawMessage `json:"sample"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			if response.Count != tt.wantCount || len(response.Sample) != tt.wantSample {
+				t.Errorf("Expected %d alerts and %d samples, got %d and %d", tt.wantCount, tt.wantSample, response.Count, len(response.Sample))
+			}
+			if len(store.alerts) != 0 {
+				t.Errorf("Expected nothing stored, got %d alerts", len(store.alerts))
+			}
+		})
+	}
+}
+
+func TestHandler_ValidateSource_URLErrors(t *testing.T) {
+	logger.Init("error", "text")
+
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss version="2.0"><channel><item><title>Port closure</title><link>https://example.com/1</link></item></channel></rss>`)
+	}))
+	defer feed.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	malformed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss version="2.0"><channel><item><title>Cut off`)
+	}))
+	defer malformed.Close()
+
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", nil),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	validate := func(urls ...string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"type": "rss", "urls": urls})
+		req := httptest.NewRequest("POST", "/v1/admin/sources/validate", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	errorCases := []struct {
+		name           string
+		urls           []string
+		expectedStatus int
+		expectedCode   apperrors.Code
+		wantMessage    []string
+	}{
+		{"Broken feed", []string{broken.URL}, http.StatusBadGateway, apperrors.CodeServiceUnavailable, []string{broken.URL, "HTTP 500"}},
+		{"Malformed feed", []string{malformed.URL}, http.StatusUnprocessableEntity, apperrors.CodeInvalidInput, []string{malformed.URL, "parse"}},
+		{"Broken and malformed", []string{broken.URL, malformed.URL}, http.StatusUnprocessableEntity, apperrors.CodeInvalidInput, []string{broken.URL, malformed.URL}},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := validate(tt.urls...)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			var errResp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errResp.Code != tt.expectedCode {
+				t.Errorf("Expected code %q, got %q", tt.expectedCode, errResp.Code)
+			}
+			for _, want := range tt.wantMessage {
+				if !strings.Contains(errResp.Message, want) {
+					t.Errorf("Expected message to mention %q, got %q", want, errResp.Message)
+				}
+			}
+		})
+	}
+
+	t.Run("Partly broken", func(t *testing.T) {
+		w := validate(feed.URL, broken.URL)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Count int                 `json:"count"`
+			URLs  []validateURLResult `json:"urls"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode JSON response: %v", err)
+		}
+		if response.Count != 1 || len(response.URLs) != 2 {
+			t.Fatalf("Expected 1 alert and 2 URL results, got %+v", response)
+		}
+		if got := response.URLs[0]; got.URL != feed.URL || got.Count != 1 || got.Error != "" {
+			t.Errorf("Expected the healthy feed to parse, got %+v", got)
+		}
+		if got := response.URLs[1]; got.URL != broken.URL || !strings.Contains(got.Error, "HTTP 500") {
+			t.Errorf("Expected the broken feed's error, got %+v", got)
+		}
+	})
+}
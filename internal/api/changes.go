@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// alertChangesHandler handles GET /alerts/changes, returning alerts updated
+// since the since_version token, archived ones included, so a client can
+// keep a mirror current. Each response carries the token for the next call.
+func (h *Handler) alertChangesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("since_version")
+
+	includeRaw := false
+	if rawStr := r.URL.Query().Get("include_raw"); rawStr != "" {
+		var err error
+		if includeRaw, err = strconv.ParseBool(rawStr); err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, fmt.Sprintf("invalid include_raw: %s", rawStr))
+			return
+		}
+	}
+
+	alerts, next, err := h.store.ChangesSince(ctx, token)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidChangeToken) {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidCursor, "invalid since_version")
+			return
+		}
+		logger.WithContext(ctx).Error("Failed to get alert changes", "error", err)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+	if !includeRaw {
+		omitRaw(alerts)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"data":         alerts,
+		"count":        len(alerts),
+		"next_version": next,
+		"timestamp":    time.Now().UTC(),
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestHandler_AlertChanges(t *testing.T) {
+	logger.Init("error", "text")
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMockStore()
+	for _, alert := range []models.Alert{
+		{ID: "alert-1", UpdatedAt: at, Raw: "<item/>"},
+		{ID: "alert-2", UpdatedAt: at.Add(time.Hour), ArchivedAt: &at},
+		{ID: "alert-3", UpdatedAt: at.Add(2 * time.Hour)},
+	} {
+		store.alerts[alert.ID] = alert
+	}
+	version := models.ChangeToken{UpdatedAt: at, ID: "alert-1"}.Encode()
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedCode   apperrors.Code
+		wantIDs        []string
+	}{
+		{"From the beginning", "/v1/alerts/changes", http.StatusOK, "", []string{"alert-1", "alert-2", "alert-3"}},
+		{"Since a version", "/v1/alerts/changes?since_version=" + version, http.StatusOK, "", []string{"alert-2", "alert-3"}},
+		{"Invalid version", "/v1/alerts/changes?since_version=!!!", http.StatusBadRequest, apperrors.CodeInvalidCursor, nil},
+		{"Invalid include_raw", "/v1/alerts/changes?include_raw=maybe", http.StatusBadRequest, apperrors.CodeInvalidInput, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != "" {
+				var errResp ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+					t.Fatalf("Failed to decode error response: %v", err)
+				}
+				if errResp.Code != tt.expectedCode {
+					t.Errorf("Expected code %q, got %q", tt.expectedCode, errResp.Code)
+				}
+				return
+			}
+
+			var response struct {
+				Data        []models.Alert `json:"data"`
+				Count       int            `json:"count"`
+				NextVersion string         `json:"next_version"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var ids []string
+			for _, alert := range response.Data {
+				ids = append(ids, alert.ID)
+				if alert.Raw != "" {
+					t.Errorf("Expected %s to omit raw", alert.ID)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || response.Count != len(tt.wantIDs) {
+				t.Errorf("Expected %v, got %v (count %d)", tt.wantIDs, ids, response.Count)
+			}
+
+			next, err := models.DecodeChangeToken(response.NextVersion)
+			if err != nil || next.ID != "alert-3" {
+				t.Errorf("Expected next_version at alert-3, got %q (err %v)", response.NextVersion, err)
+			}
+		})
+	}
+}
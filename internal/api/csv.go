@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// alertCSVHeader returns the CSV column names for models.Alert, taken from
+// the JSON names of its exported fields so the CSV matches the JSON output
+func alertCSVHeader() []string {
+	t := reflect.TypeOf(models.Alert{})
+	header := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+		header = append(header, name)
+	}
+	return header
+}
+
+// alertCSVRecord renders an alert as a CSV record in alertCSVHeader order
+func alertCSVRecord(alert models.Alert) []string {
+	v := reflect.ValueOf(alert)
+	record := make([]string, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		switch f := v.Field(i).Interface().(type) {
+		case string:
+			record = append(record, f)
+		case float64:
+			record = append(record, strconv.FormatFloat(f, 'f', -1, 64))
+		case bool:
+			record = append(record, strconv.FormatBool(f))
+		case time.Time:
+			if f.IsZero() {
+				record = append(record, "")
+			} else {
+				record = append(record, f.UTC().Format(time.RFC3339))
+			}
+		case *time.Time:
+			if f == nil {
+				record = append(record, "")
+			} else {
+				record = append(record, f.UTC().Format(time.RFC3339))
+			}
+		default:
+			record = append(record, "")
+		}
+	}
+	return record
+}
+
+// writeAlertsCSV streams alerts as CSV, header first, to w
+func writeAlertsCSV(w io.Writer, alerts []models.Alert) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(alertCSVHeader()); err != nil {
+		return err
+	}
+	return writeAlertCSVRows(cw, alerts)
+}
+
+// writeAlertCSVRows writes alerts as CSV records and flushes them through
+// to the underlying writer
+func writeAlertCSVRows(cw *csv.Writer, alerts []models.Alert) error {
+	for _, alert := range alerts {
+		if err := cw.Write(alertCSVRecord(alert)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestWriteAlertsCSV(t *testing.T) {
+	alerts := []models.Alert{
+		{
+			ID:         "alert-1",
+			Title:      "Port strike, \"major\"",
+			DetectedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("EST", -5*3600)),
+			Latitude:   33.7361,
+			Confidence: 0.9,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeAlertsCSV(&buf, alerts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and 1 row, got %d records", len(records))
+	}
+
+	row := map[string]string{}
+	for i, name := range records[0] {
+		row[name] = records[1][i]
+	}
+
+	expected := map[string]string{
+		"id":           "alert-1",
+		"title":        "Port strike, \"major\"",
+		"detected_at":  "2024-01-15T15:30:00Z",
+		"published_at": "",
+		"latitude":     "33.7361",
+		"confidence":   "0.9",
+	}
+	for name, want := range expected {
+		if row[name] != want {
+			t.Errorf("Expected %s=%q, got %q", name, want, row[name])
+		}
+	}
+}
+
+func TestAlertCSVHeader_CoversAllFields(t *testing.T) {
+	header := alertCSVHeader()
+	record := alertCSVRecord(models.Alert{})
+	if len(header) != len(record) {
+		t.Errorf("Header has %d columns but record has %d", len(header), len(record))
+	}
+	if header[0] != "id" || header[len(header)-1] != "archived_at" {
+		t.Errorf("Unexpected header: %v", header)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+	"github.com/rajasatyajit/SupplyChain/pkg/utils"
+)
+
+// feedbackSeverities are the severities an operator may assign
+var feedbackSeverities = []string{"low", "medium", "high"}
+
+// alertFeedbackRequest is the body of POST /v1/alerts/{id}/feedback.
+// SubmittedBy is required because the admin token is shared, so it does
+// not identify who made the correction.
+type alertFeedbackRequest struct {
+	Severity    string `json:"severity"`
+	Disruption  string `json:"disruption"`
+	SubmittedBy string `json:"submitted_by"`
+}
+
+// alertFeedbackHandler records a correction to an alert's severity or
+// disruption category and returns the corrected alert
+func (h *Handler) alertFeedbackHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	alertID := chi.URLParam(r, "id")
+
+	var req alertFeedbackRequest
+	if !h.decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	req.SubmittedBy = strings.TrimSpace(req.SubmittedBy)
+	switch {
+	case req.Severity == "" && req.Disruption == "":
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "severity or disruption is required")
+		return
+	case req.Severity != "" && !slices.Contains(feedbackSeverities, req.Severity):
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "severity must be one of: "+strings.Join(feedbackSeverities, ", "))
+		return
+	case req.Disruption != "" && !slices.Contains(utils.DisruptionCategories(), req.Disruption):
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "unknown disruption category: "+req.Disruption)
+		return
+	case req.SubmittedBy == "":
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "submitted_by is required")
+		return
+	}
+
+	alert, err := h.store.OverrideAlert(ctx, models.AlertOverride{
+		AlertID:     alertID,
+		Severity:    req.Severity,
+		Disruption:  req.Disruption,
+		SubmittedBy: req.SubmittedBy,
+		UpdatedAt:   time.Now().UTC(),
+	})
+	if errors.Is(err, store.ErrAlertNotFound) {
+		h.writeErrorResponse(w, r, http.StatusNotFound, apperrors.CodeAlertNotFound, "Alert not found")
+		return
+	}
+	if err != nil {
+		logger.WithContext(ctx).Error("Failed to override alert", "error", err, "alert_id", alertID)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+
+	logger.WithContext(ctx).Info("Alert classification overridden",
+		"alert_id", alertID,
+		"severity", req.Severity,
+		"disruption", req.Disruption,
+		"submitted_by", req.SubmittedBy,
+	)
+	h.writeJSONResponse(w, http.StatusOK, alert)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestHandler_AlertFeedback(t *testing.T) {
+	logger.Init("error", "text")
+
+	tests := []struct {
+		name           string
+		id             string
+		auth           string
+		body           string
+		expectedStatus int
+		expectedCode   apperrors.Code
+		wantSeverity   string
+		wantDisruption string
+	}{
+		{"Corrects severity", "alert-1", "Bearer secret", `{"severity":"high","submitted_by":"ops@example.com"}`, http.StatusOK, "", "high", "weather"},
+		{"Corrects disruption", "alert-1", "Bearer secret", `{"disruption":"port_status","submitted_by":"ops@example.com"}`, http.StatusOK, "", "low", "port_status"},
+		{"Missing token", "alert-1", "", `{"severity":"high","submitted_by":"ops"}`, http.StatusUnauthorized, apperrors.CodeUnauthorized, "", ""},
+		{"Unknown alert", "missing", "Bearer secret", `{"severity":"high","submitted_by":"ops"}`, http.StatusNotFound, apperrors.CodeAlertNotFound, "", ""},
+		{"No fields", "alert-1", "Bearer secret", `{"submitted_by":"ops"}`, http.StatusBadRequest, apperrors.CodeInvalidInput, "", ""},
+		{"Invalid severity", "alert-1", "Bearer secret", `{"severity":"critical","submitted_by":"ops"}`, http.StatusBadRequest, apperrors.CodeInvalidInput, "", ""},
+		{"Invalid disruption", "alert-1", "Bearer secret", `{"disruption":"aliens","submitted_by":"ops"}`, http.StatusBadRequest, apperrors.CodeInvalidInput, "", ""},
+		{"Missing submitter", "alert-1", "Bearer secret", `{"severity":"high"}`, http.StatusBadRequest, apperrors.CodeInvalidInput, "", ""},
+		{"Invalid body", "alert-1", "Bearer secret", `{`, http.StatusBadRequest, apperrors.CodeInvalidInput, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMockStore()
+			store.alerts["alert-1"] = models.Alert{ID: "alert-1", Severity: "low", Disruption: "weather", DetectedAt: time.Now().UTC()}
+			handler := NewHandler(store, "test-version", "test-build-time", "test-commit",
+				WithAdmin("secret", nil),
+			)
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest("POST", "/v1/alerts/"+tt.id+"/feedback", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if tt.expectedCode != "" {
+				var response ErrorResponse
+				json.NewDecoder(w.Body).Decode(&response)
+				if response.Code != tt.expectedCode {
+					t.Errorf("Expected code %s, got %s", tt.expectedCode, response.Code)
+				}
+				return
+			}
+
+			var alert models.Alert
+			json.NewDecoder(w.Body).Decode(&alert)
+			if alert.Severity != tt.wantSeverity || alert.Disruption != tt.wantDisruption {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.wantSeverity, tt.wantDisruption, alert.Severity, alert.Disruption)
+			}
+
+			// The correction is what later reads return
+			req = httptest.NewRequest("GET", "/v1/alerts/alert-1", nil)
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			json.NewDecoder(w.Body).Decode(&alert)
+			if alert.Severity != tt.wantSeverity {
+				t.Errorf("Expected GET to return severity %s, got %s", tt.wantSeverity, alert.Severity)
+			}
+		})
+	}
+}
+
+func TestHandler_AlertFeedback_DisabledWithoutSecret(t *testing.T) {
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/v1/alerts/alert-1/feedback", strings.NewReader(`{"severity":"high","submitted_by":"ops"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code == http.StatusOK {
+		t.Errorf("Expected feedback to be unavailable without an admin secret, got %d", w.Code)
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// FeatureCollection represents a GeoJSON FeatureCollection of alerts
+type FeatureCollection struct {
+	Type       string                 `json:"type"`
+	Features   []Feature              `json:"features"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Feature represents a GeoJSON Feature for a single alert
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry represents a GeoJSON Point geometry
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// toFeatureCollection converts alerts into a GeoJSON FeatureCollection.
+// Alerts without coordinates are omitted and counted in the "skipped" property.
+func toFeatureCollection(alerts []models.Alert) FeatureCollection {
+	fc := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{},
+	}
+
+	skipped := 0
+	for _, alert := range alerts {
+		if !alert.HasCoordinates() {
+			skipped++
+			continue
+		}
+
+		fc.Features = append(fc.Features, Feature{
+			Type: "Feature",
+			ID:   alert.ID,
+			Geometry: Geometry{
+				Type:        "Point",
+				Coordinates: []float64{alert.Longitude, alert.Latitude}, // GeoJSON order is lon, lat
+			},
+			Properties: map[string]interface{}{
+				"title":      alert.Title,
+				"severity":   alert.Severity,
+				"disruption": alert.Disruption,
+			},
+		})
+	}
+
+	fc.Properties = map[string]interface{}{
+		"skipped": skipped,
+	}
+
+	return fc
+}
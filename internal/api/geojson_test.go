@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestToFeatureCollection(t *testing.T) {
+	alerts := []models.Alert{
+		{
+			ID:         "alert-1",
+			Title:      "Port Strike",
+			Severity:   "high",
+			Disruption: "port_status",
+			Latitude:   33.7361,
+			Longitude:  -118.2922,
+		},
+		{
+			ID:    "alert-2",
+			Title: "No coordinates",
+		},
+	}
+
+	fc := toFeatureCollection(alerts)
+
+	if fc.Type != "FeatureCollection" {
+		t.Errorf("Expected type FeatureCollection, got %s", fc.Type)
+	}
+	if len(fc.Features) != 1 {
+		t.Fatalf("Expected 1 feature, got %d", len(fc.Features))
+	}
+	if fc.Properties["skipped"] != 1 {
+		t.Errorf("Expected skipped 1, got %v", fc.Properties["skipped"])
+	}
+
+	f := fc.Features[0]
+	if f.ID != "alert-1" || f.Geometry.Type != "Point" {
+		t.Errorf("Unexpected feature: %+v", f)
+	}
+	if f.Geometry.Coordinates[0] != -118.2922 || f.Geometry.Coordinates[1] != 33.7361 {
+		t.Errorf("Expected [lon, lat] coordinates, got %v", f.Geometry.Coordinates)
+	}
+	if f.Properties["severity"] != "high" || f.Properties["disruption"] != "port_status" || f.Properties["title"] != "Port Strike" {
+		t.Errorf("Unexpected properties: %v", f.Properties)
+	}
+}
+
+func TestToFeatureCollection_Empty(t *testing.T) {
+	fc := toFeatureCollection(nil)
+	if fc.Features == nil || len(fc.Features) != 0 {
+		t.Errorf("Expected empty non-nil features, got %v", fc.Features)
+	}
+	if fc.Properties["skipped"] != 0 {
+		t.Errorf("Expected skipped 0, got %v", fc.Properties["skipped"])
+	}
+}
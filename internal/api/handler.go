@@ -1,17 +1,24 @@
 package api
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"fmt"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 	"github.com/rajasatyajit/SupplyChain/internal/store"
+	"github.com/rajasatyajit/SupplyChain/pkg/utils"
 )
 
 // Handler handles HTTP requests for the API
@@ -21,37 +28,109 @@ type Handler struct {
 	buildTime string
 	gitCommit string
 	startTime time.Time
+	checks    []readinessCheck
+
+	adminSecret    string
+	runner         PipelineRunner
+	sources        SourceLister
+	webhooks       store.WebhookStore
+	maxBodyBytes   int64
+	maxWindow      time.Duration
+	readTimeout    time.Duration
+	requestTimeout time.Duration
+	queryTimeout   time.Duration
 }
 
+// DefaultMaxBodyBytes caps JSON request bodies unless WithMaxBodyBytes is used
+const DefaultMaxBodyBytes = 1 << 20
+
 // NewHandler creates a new API handler
-func NewHandler(store store.Store, version, buildTime, gitCommit string) *Handler {
-	return &Handler{
-		store:     store,
-		version:   version,
-		buildTime: buildTime,
-		gitCommit: gitCommit,
-		startTime: time.Now(),
+func NewHandler(store store.Store, version, buildTime, gitCommit string, opts ...Option) *Handler {
+	h := &Handler{
+		store:        store,
+		version:      version,
+		buildTime:    buildTime,
+		gitCommit:    gitCommit,
+		startTime:    time.Now(),
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
+
+	return h
 }
 
 // RegisterRoutes registers all API routes
 func (h *Handler) RegisterRoutes(r *chi.Mux) {
-	r.Route("/v1", func(r chi.Router) {
-		// Health check endpoints
-		r.Get("/health", h.healthHandler)
-		r.Get("/health/ready", h.readinessHandler)
-		r.Get("/health/live", h.livenessHandler)
+	r.MethodNotAllowed(h.methodNotAllowedHandler(r))
 
-		// API endpoints
-		r.Get("/alerts", h.getAlertsHandler)
-		r.Get("/alerts/{id}", h.getAlertHandler)
+	r.Route("/v1", func(r chi.Router) {
+		// HEAD is answered by the GET handler; the server drops the body
+		r.Use(middleware.GetHead)
+		r.Use(middleware.Compress(compressionLevel, compressibleTypes...))
+
+		// Reads share the shorter timeout
+		r.Group(func(r chi.Router) {
+			r.Use(h.timeout(h.readTimeout))
+
+			// Health check endpoints
+			r.Get("/health", h.healthHandler)
+			r.Get("/health/ready", h.readinessHandler)
+			r.Get("/health/live", h.livenessHandler)
+
+			// API endpoints
+			r.Get("/alerts", h.getAlertsHandler)
+			r.Get("/alerts.csv", h.getAlertsCSVHandler)
+			r.Get("/alerts/count", h.countAlertsHandler)
+			r.Get("/alerts/stats", h.alertStatsHandler)
+			r.Get("/alerts/changes", h.alertChangesHandler)
+			r.Get("/alerts/{id}", h.getAlertHandler)
+			r.Get("/alerts/{id}/related", h.relatedAlertsHandler)
+			r.Get("/alerts/{id}/history", h.alertHistoryHandler)
+			r.Get("/disruptions", h.disruptionsHandler)
+			r.Get("/sources", h.sourcesHandler)
+
+			// System info
+			r.Get("/version", h.versionHandler)
+			r.Get("/openapi.json", h.openAPIHandler)
+		})
+
+		r.With(h.timeout(h.requestTimeout)).Post("/alerts/batch", h.batchAlertsHandler)
+		if h.adminSecret != "" {
+			r.With(h.timeout(h.requestTimeout), h.requireAdmin).Post("/alerts/{id}/feedback", h.alertFeedbackHandler)
+		}
 
-		// System info
-		r.Get("/version", h.versionHandler)
+		// Admin endpoints are only mounted when a secret is configured
+		if h.adminSecret != "" {
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.requireAdmin)
+
+				// A manual run fetches a whole source, so it is exempt
+				if h.runner != nil {
+					r.Post("/pipeline/run", h.runPipelineHandler)
+				}
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.timeout(h.requestTimeout))
+					r.Post("/sources/validate", h.validateSourceHandler)
+					if h.runner != nil {
+						r.Get("/pipeline/status", h.pipelineStatusHandler)
+					}
+					if h.webhooks != nil {
+						r.Post("/webhooks", h.createWebhookHandler)
+						r.Get("/webhooks", h.listWebhooksHandler)
+						r.Delete("/webhooks/{id}", h.deleteWebhookHandler)
+					}
+				})
+			})
+		}
 	})
 
 	// Root health check
-	r.Get("/health", h.healthHandler)
+	r.With(h.timeout(h.readTimeout)).Get("/health", h.healthHandler)
+	r.With(h.timeout(h.readTimeout)).Head("/health", h.healthHandler)
 }
 
 // healthHandler provides basic health check
@@ -69,22 +148,37 @@ func (h *Handler) healthHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) readinessHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	checks := map[string]string{
-		"store": "ok",
-	}
+	checks := make(map[string]string, len(h.checks)+1)
+	dependencies := make(map[string]dependencyStatus, len(h.checks)+1)
 
+	status := "ready"
 	statusCode := http.StatusOK
 
-	// Check store health
-	if err := h.store.Health(ctx); err != nil {
-		checks["store"] = "error: " + err.Error()
-		statusCode = http.StatusServiceUnavailable
+	// The store is always critical; only critical optional dependencies
+	// affect readiness
+	all := append([]readinessCheck{{name: "store", critical: true, check: h.store.Health}}, h.checks...)
+	for _, c := range all {
+		dep := runReadinessCheck(ctx, c)
+		dependencies[c.name] = dep
+		if dep.Error == "" {
+			checks[c.name] = "ok"
+			continue
+		}
+
+		checks[c.name] = "error: " + dep.Error
+		if c.critical {
+			status = "not_ready"
+			statusCode = http.StatusServiceUnavailable
+		} else if status == "ready" {
+			status = "degraded"
+		}
 	}
 
 	response := map[string]interface{}{
-		"status":    "ready",
-		"timestamp": time.Now().UTC(),
-		"checks":    checks,
+		"status":       status,
+		"timestamp":    time.Now().UTC(),
+		"checks":       checks,
+		"dependencies": dependencies,
 	}
 
 	h.writeJSONResponse(w, statusCode, response)
@@ -112,26 +206,230 @@ func (h *Handler) versionHandler(w http.ResponseWriter, r *http.Request) {
 	h.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// disruptionsHandler lists the disruption category codes alerts can carry
+func (h *Handler) disruptionsHandler(w http.ResponseWriter, r *http.Request) {
+	categories := utils.DisruptionCategories()
+
+	response := map[string]interface{}{
+		"data":  categories,
+		"count": len(categories),
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
+
 // getAlertsHandler handles GET /alerts
 func (h *Handler) getAlertsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "geojson":
+	case "csv":
+		h.getAlertsCSVHandler(w, r)
+		return
+	default:
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeUnsupportedFormat, fmt.Sprintf("unsupported format: %s", format))
+		return
+	}
+
 	q, err := h.parseAlertQuery(r)
 	if err != nil {
-		h.writeErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
 		return
 	}
 
-	alerts, err := h.store.QueryAlerts(ctx, q)
+	includeTotal := false
+	if totalStr := r.URL.Query().Get("include_total"); totalStr != "" {
+		if includeTotal, err = strconv.ParseBool(totalStr); err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, fmt.Sprintf("invalid include_total: %s", totalStr))
+			return
+		}
+	}
+
+	includeRaw := false
+	if rawStr := r.URL.Query().Get("include_raw"); rawStr != "" {
+		if includeRaw, err = strconv.ParseBool(rawStr); err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, fmt.Sprintf("invalid include_raw: %s", rawStr))
+			return
+		}
+	}
+
+	// Fetch one alert past the page to learn whether another page follows
+	page := q
+	if q.Limit > 0 {
+		page.Limit = q.Limit + 1
+	}
+	qctx, cancel := h.queryContext(ctx)
+	alerts, err := h.store.QueryAlerts(qctx, page)
+	cancel()
 	if err != nil {
-		logger.WithContext(ctx).Error("Failed to query alerts", "error", err)
-		h.writeErrorResponse(w, r, http.StatusInternalServerError, "Internal server error")
+		h.writeQueryError(w, r, qctx, err, "Failed to query alerts")
+		return
+	}
+	hasMore := q.Limit > 0 && len(alerts) > q.Limit
+	if hasMore {
+		alerts = alerts[:q.Limit]
+	}
+	if !includeRaw {
+		omitRaw(alerts)
+	}
+
+	if format == "geojson" {
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		h.writeResponse(w, http.StatusOK, "application/geo+json", toFeatureCollection(alerts))
 		return
 	}
 
 	response := map[string]interface{}{
 		"data":      alerts,
 		"count":     len(alerts),
+		"has_more":  hasMore,
+		"timestamp": time.Now().UTC(),
+	}
+
+	// Cursors follow the default order only
+	if hasMore && q.IsDefaultSort() {
+		response["next_cursor"] = models.NewCursor(alerts[len(alerts)-1]).Encode()
+	}
+
+	// The total counts every match, not just those after the cursor
+	if includeTotal {
+		tq := q
+		tq.Cursor = nil
+		qctx, cancel := h.queryContext(ctx)
+		total, err := h.store.CountAlerts(qctx, tq)
+		cancel()
+		if err != nil {
+			h.writeQueryError(w, r, qctx, err, "Failed to count alerts")
+			return
+		}
+		response["total"] = total
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// csvPageSize caps how many alerts GET /alerts.csv reads per query
+const csvPageSize = 1000
+
+// getAlertsCSVHandler handles GET /alerts.csv and GET /alerts?format=csv
+func (h *Handler) getAlertsCSVHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := h.parseAlertQuery(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
+		return
+	}
+
+	// Read at most a page per query. Without a limit, the default order
+	// pages through every match with the keyset cursor; other orders can't
+	// resume from a cursor, so they stop after one page
+	page := q
+	if page.Limit == 0 {
+		page.Limit = csvPageSize
+	}
+	paged := q.Limit == 0 && q.IsDefaultSort()
+
+	var cw *csv.Writer
+	for {
+		qctx, cancel := h.queryContext(ctx)
+		alerts, err := h.store.QueryAlerts(qctx, page)
+		cancel()
+		if err != nil && cw == nil {
+			h.writeQueryError(w, r, qctx, err, "Failed to query alerts")
+			return
+		}
+		if err != nil {
+			// The status is already sent, so the export ends early
+			logger.WithContext(ctx).Error("Failed to query alerts CSV page", "error", err)
+			return
+		}
+
+		if cw == nil {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="alerts.csv"`)
+			w.Header().Set("Cache-Control", "public, max-age=60")
+			w.WriteHeader(http.StatusOK)
+			cw = csv.NewWriter(w)
+			if err := cw.Write(alertCSVHeader()); err != nil {
+				logger.WithContext(ctx).Error("Failed to write alerts CSV", "error", err)
+				return
+			}
+		}
+		if err := writeAlertCSVRows(cw, alerts); err != nil {
+			logger.WithContext(ctx).Error("Failed to write alerts CSV", "error", err)
+			return
+		}
+		// Send each page on rather than buffering the export
+		_ = http.NewResponseController(w).Flush()
+
+		if !paged || len(alerts) < page.Limit {
+			return
+		}
+		page.Offset, page.Cursor = 0, models.NewCursor(alerts[len(alerts)-1])
+	}
+}
+
+// countAlertsHandler handles GET /alerts/count
+func (h *Handler) countAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := h.parseAlertQuery(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
+		return
+	}
+
+	qctx, cancel := h.queryContext(ctx)
+	count, err := h.store.CountAlerts(qctx, q)
+	cancel()
+	if err != nil {
+		h.writeQueryError(w, r, qctx, err, "Failed to count alerts")
+		return
+	}
+
+	response := map[string]interface{}{
+		"count": count,
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// alertStatsHandler handles GET /alerts/stats, counting matching alerts
+// grouped by the group_by field
+func (h *Handler) alertStatsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	groupBy := r.URL.Query().Get("group_by")
+	if !models.ValidGroupBy(groupBy) {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidGroupBy,
+			fmt.Sprintf("invalid group_by %q: must be one of severity, disruption, region, country", groupBy))
+		return
+	}
+
+	q, err := h.parseAlertQuery(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
+		return
+	}
+
+	qctx, cancel := h.queryContext(ctx)
+	counts, err := h.store.AggregateAlerts(qctx, q, groupBy)
+	cancel()
+	if err != nil {
+		h.writeQueryError(w, r, qctx, err, "Failed to aggregate alerts")
+		return
+	}
+
+	response := map[string]interface{}{
+		"data":      counts,
+		"count":     len(counts),
+		"group_by":  groupBy,
 		"timestamp": time.Now().UTC(),
 	}
 
@@ -145,26 +443,139 @@ func (h *Handler) getAlertHandler(w http.ResponseWriter, r *http.Request) {
 	alertID := chi.URLParam(r, "id")
 
 	if alertID == "" {
-		h.writeErrorResponse(w, r, http.StatusBadRequest, "alert ID is required")
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "alert ID is required")
+		return
+	}
+
+	includeArchived, err := parseIncludeArchived(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
 		return
 	}
 
 	alert, err := h.store.GetAlert(ctx, alertID)
 	if err != nil {
 		logger.WithContext(ctx).Error("Failed to get alert", "error", err, "alert_id", alertID)
-		h.writeErrorResponse(w, r, http.StatusInternalServerError, "Internal server error")
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
 		return
 	}
 
-	if alert == nil {
-		h.writeErrorResponse(w, r, http.StatusNotFound, "Alert not found")
+	if alert == nil || (alert.ArchivedAt != nil && !includeArchived) {
+		h.writeErrorResponse(w, r, http.StatusNotFound, apperrors.CodeAlertNotFound, "Alert not found")
 		return
 	}
 
+	etag := alertETag(alert)
+	w.Header().Set("ETag", etag)
 	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	h.writeJSONResponse(w, http.StatusOK, alert)
 }
 
+// omitRaw clears the raw source items, which list responses leave out
+// unless asked for since they are often larger than the rest of the alert
+func omitRaw(alerts []models.Alert) {
+	for i := range alerts {
+		alerts[i].Raw = ""
+		alerts[i].RawFormat = ""
+	}
+}
+
+// maxBatchIDs caps how many alerts POST /alerts/batch fetches at once
+const maxBatchIDs = 200
+
+// batchAlertsRequest is the body of POST /alerts/batch
+type batchAlertsRequest struct {
+	IDs             []string `json:"ids"`
+	IncludeArchived bool     `json:"include_archived"`
+	IncludeRaw      bool     `json:"include_raw"`
+}
+
+// batchAlertsHandler fetches alerts by ID, returning them in request order
+// and listing the IDs that were not found
+func (h *Handler) batchAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req batchAlertsRequest
+	if !h.decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	ids := make([]string, 0, len(req.IDs))
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "ids must not be empty")
+		return
+	}
+	if len(ids) > maxBatchIDs {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, fmt.Sprintf("at most %d ids may be requested, got %d", maxBatchIDs, len(ids)))
+		return
+	}
+
+	alerts, err := h.store.QueryAlerts(ctx, models.AlertQuery{IDs: ids, Limit: len(ids), IncludeArchived: req.IncludeArchived})
+	if err != nil {
+		logger.WithContext(ctx).Error("Failed to query alerts", "error", err)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+
+	if !req.IncludeRaw {
+		omitRaw(alerts)
+	}
+
+	byID := make(map[string]models.Alert, len(alerts))
+	for _, alert := range alerts {
+		byID[alert.ID] = alert
+	}
+
+	data := make([]models.Alert, 0, len(alerts))
+	missing := []string{}
+	for _, id := range ids {
+		if alert, ok := byID[id]; ok {
+			data = append(data, alert)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"data":      data,
+		"count":     len(data),
+		"missing":   missing,
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// alertETag returns a strong ETag that changes whenever the alert is updated
+func alertETag(alert *models.Alert) string {
+	return `"` + utils.HashString(alert.ID+"|"+alert.UpdatedAt.UTC().Format(time.RFC3339Nano)) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // parseAlertQuery parses query parameters into AlertQuery
 func (h *Handler) parseAlertQuery(r *http.Request) (models.AlertQuery, error) {
 	q := models.AlertQuery{}
@@ -173,10 +584,10 @@ func (h *Handler) parseAlertQuery(r *http.Request) (models.AlertQuery, error) {
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		limit, err := strconv.Atoi(limitStr)
 		if err != nil {
-			return q, fmt.Errorf("invalid limit: %s", limitStr)
+			return q, apperrors.Newf(apperrors.CodeInvalidLimit, "invalid limit: %s", limitStr)
 		}
 		if limit < 0 || limit > 1000 {
-			return q, fmt.Errorf("limit must be between 0 and 1000")
+			return q, apperrors.Newf(apperrors.CodeInvalidLimit, "limit must be between 0 and 1000")
 		}
 		q.Limit = limit
 	}
@@ -185,19 +596,49 @@ func (h *Handler) parseAlertQuery(r *http.Request) (models.AlertQuery, error) {
 	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
 		offset, err := strconv.Atoi(offsetStr)
 		if err != nil {
-			return q, fmt.Errorf("invalid offset: %s", offsetStr)
+			return q, apperrors.Newf(apperrors.CodeInvalidOffset, "invalid offset: %s", offsetStr)
 		}
 		if offset < 0 {
-			return q, fmt.Errorf("offset must be non-negative")
+			return q, apperrors.Newf(apperrors.CodeInvalidOffset, "offset must be non-negative")
 		}
 		q.Offset = offset
 	}
 
+	// Parse sorting
+	if sortStr := r.URL.Query().Get("sort"); sortStr != "" {
+		if !models.ValidSortField(sortStr) {
+			return q, apperrors.Newf(apperrors.CodeInvalidSort, "invalid sort field: %s", sortStr)
+		}
+		q.SortBy = sortStr
+	}
+
+	if orderStr := r.URL.Query().Get("order"); orderStr != "" {
+		if orderStr != models.SortAsc && orderStr != models.SortDesc {
+			return q, apperrors.Newf(apperrors.CodeInvalidSort, "invalid order: %s", orderStr)
+		}
+		q.SortOrder = orderStr
+	}
+
+	// Parse cursor
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		if q.Offset > 0 {
+			return q, apperrors.Newf(apperrors.CodeInvalidCursor, "cursor cannot be combined with offset")
+		}
+		if !q.IsDefaultSort() {
+			return q, apperrors.Newf(apperrors.CodeInvalidCursor, "cursor requires the default sort order")
+		}
+		cursor, err := models.DecodeCursor(cursorStr)
+		if err != nil {
+			return q, apperrors.Newf(apperrors.CodeInvalidCursor, "invalid cursor: %s", cursorStr)
+		}
+		q.Cursor = cursor
+	}
+
 	// Parse time filters
 	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
 		since, err := time.Parse(time.RFC3339, sinceStr)
 		if err != nil {
-			return q, fmt.Errorf("invalid since format: %s", sinceStr)
+			return q, apperrors.Newf(apperrors.CodeInvalidTime, "invalid since format: %s", sinceStr)
 		}
 		q.Since = since
 	}
@@ -205,32 +646,222 @@ func (h *Handler) parseAlertQuery(r *http.Request) (models.AlertQuery, error) {
 	if untilStr := r.URL.Query().Get("until"); untilStr != "" {
 		until, err := time.Parse(time.RFC3339, untilStr)
 		if err != nil {
-			return q, fmt.Errorf("invalid until format: %s", untilStr)
+			return q, apperrors.Newf(apperrors.CodeInvalidTime, "invalid until format: %s", untilStr)
 		}
 		q.Until = until
 	}
 
+	if err := h.checkTimeRange(q.Since, q.Until, time.Now()); err != nil {
+		return q, err
+	}
+
 	// Parse array filters
 	q.Sources = r.URL.Query()["source"]
 	q.Severities = r.URL.Query()["severity"]
 	q.Disruptions = r.URL.Query()["disruption"]
 	q.Regions = r.URL.Query()["region"]
 	q.Countries = r.URL.Query()["country"]
+	q.Locations = r.URL.Query()["location"]
+
+	// Parse archival visibility
+	includeArchived, err := parseIncludeArchived(r)
+	if err != nil {
+		return q, err
+	}
+	q.IncludeArchived = includeArchived
+
+	// Parse escalation filter
+	if escalatedStr := r.URL.Query().Get("escalated"); escalatedStr != "" {
+		escalated, err := strconv.ParseBool(escalatedStr)
+		if err != nil {
+			return q, apperrors.Newf(apperrors.CodeInvalidInput, "invalid escalated: %s", escalatedStr)
+		}
+		q.Escalated = &escalated
+	}
+
+	// Parse coordinate presence filter
+	if coordsStr := r.URL.Query().Get("has_coordinates"); coordsStr != "" {
+		hasCoords, err := strconv.ParseBool(coordsStr)
+		if err != nil {
+			return q, apperrors.Newf(apperrors.CodeInvalidInput, "invalid has_coordinates: %s", coordsStr)
+		}
+		q.HasCoordinates = &hasCoords
+	}
+
+	// Parse full-text search
+	q.Text = strings.TrimSpace(r.URL.Query().Get("q"))
+
+	// Parse bounding box
+	if bboxStr := r.URL.Query().Get("bbox"); bboxStr != "" {
+		bbox, err := parseBBox(bboxStr)
+		if err != nil {
+			return q, err
+		}
+		q.BBox = bbox
+	}
+
+	// Parse severity floor
+	if minSeverity := r.URL.Query().Get("min_severity"); minSeverity != "" {
+		if models.SeverityRank(minSeverity) == 0 {
+			return q, apperrors.Newf(apperrors.CodeInvalidInput, "invalid min_severity: must be low, medium or high")
+		}
+		q.MinSeverity = minSeverity
+	}
+
+	// Parse confidence floor
+	if minStr := r.URL.Query().Get("min_confidence"); minStr != "" {
+		minConfidence, err := strconv.ParseFloat(minStr, 64)
+		if err != nil || minConfidence < 0 || minConfidence > 1 {
+			return q, apperrors.Newf(apperrors.CodeInvalidInput, "invalid min_confidence: must be between 0 and 1")
+		}
+		q.MinConfidence = minConfidence
+	}
+
+	// Parse impact floor
+	if minStr := r.URL.Query().Get("min_impact"); minStr != "" {
+		minImpact, err := strconv.ParseFloat(minStr, 64)
+		if err != nil || minImpact < 0 || minImpact > 1 {
+			return q, apperrors.Newf(apperrors.CodeInvalidInput, "invalid min_impact: must be between 0 and 1")
+		}
+		q.MinImpact = minImpact
+	}
 
 	return q, nil
 }
 
+// maxSinceSkew is how far past now since may be, allowing for clock skew
+// between clients and the server
+const maxSinceSkew = 24 * time.Hour
+
+// checkTimeRange rejects since/until ranges that cannot match any alert or
+// exceed the configured window. Zero times are unset.
+func (h *Handler) checkTimeRange(since, until, now time.Time) error {
+	if since.IsZero() {
+		return nil
+	}
+	if !until.IsZero() && since.After(until) {
+		return apperrors.Newf(apperrors.CodeInvalidTimeRange, "since must not be after until")
+	}
+	if since.After(now.Add(maxSinceSkew)) {
+		return apperrors.Newf(apperrors.CodeInvalidTimeRange, "since must not be in the future")
+	}
+
+	end := until
+	if end.IsZero() || end.After(now) {
+		end = now
+	}
+	if h.maxWindow > 0 && end.Sub(since) > h.maxWindow {
+		return apperrors.Newf(apperrors.CodeInvalidTimeRange, "time range must not exceed %s", h.maxWindow)
+	}
+	return nil
+}
+
+// parseIncludeArchived parses the include_archived parameter, false when
+// absent
+func parseIncludeArchived(r *http.Request) (bool, error) {
+	archivedStr := r.URL.Query().Get("include_archived")
+	if archivedStr == "" {
+		return false, nil
+	}
+	includeArchived, err := strconv.ParseBool(archivedStr)
+	if err != nil {
+		return false, apperrors.Newf(apperrors.CodeInvalidInput, "invalid include_archived: %s", archivedStr)
+	}
+	return includeArchived, nil
+}
+
+// parseBBox parses "minLon,minLat,maxLon,maxLat" into a bounding box
+func parseBBox(s string) (*models.BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return nil, apperrors.Newf(apperrors.CodeInvalidBBox, "invalid bbox: expected minLon,minLat,maxLon,maxLat")
+	}
+
+	var coords [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, apperrors.Newf(apperrors.CodeInvalidBBox, "invalid bbox coordinate: %s", part)
+		}
+		coords[i] = v
+	}
+
+	bbox := &models.BBox{MinLon: coords[0], MinLat: coords[1], MaxLon: coords[2], MaxLat: coords[3]}
+	if bbox.MinLon < -180 || bbox.MaxLon > 180 || bbox.MinLat < -90 || bbox.MaxLat > 90 {
+		return nil, apperrors.Newf(apperrors.CodeInvalidBBox, "invalid bbox: coordinates out of range")
+	}
+	if bbox.MinLon > bbox.MaxLon || bbox.MinLat > bbox.MaxLat {
+		return nil, apperrors.Newf(apperrors.CodeInvalidBBox, "invalid bbox: min must not exceed max")
+	}
+
+	return bbox, nil
+}
+
 // writeJSONResponse writes a JSON response
 func (h *Handler) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	h.writeResponse(w, statusCode, "application/json", data)
+}
+
+// writeResponse writes data JSON-encoded with the given content type
+func (h *Handler) writeResponse(w http.ResponseWriter, statusCode int, contentType string, data interface{}) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(data)
 }
 
+// WithMaxBodyBytes caps the size of JSON request bodies; larger bodies are
+// rejected with 413. A non-positive n keeps DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+// WithMaxQueryWindow rejects alert queries whose since/until range is wider
+// than d; a query with only since is measured up to now. Zero is unlimited.
+func WithMaxQueryWindow(d time.Duration) Option {
+	return func(h *Handler) {
+		h.maxWindow = d
+	}
+}
+
+// decodeJSONBody decodes a JSON request body into v, rejecting bodies over
+// the size limit with 413 and unknown fields or malformed JSON with 400. It
+// writes the error response and returns false on failure.
+func (h *Handler) decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil && dec.More() {
+		err = fmt.Errorf("unexpected data after JSON body")
+	}
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.writeErrorResponse(w, r, http.StatusRequestEntityTooLarge, apperrors.CodePayloadTooLarge,
+			fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return false
+	}
+	h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "Invalid request body: "+err.Error())
+	return false
+}
+
 // writeErrorResponse writes a standardized error response
-func (h *Handler) writeErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+func (h *Handler) writeErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, code apperrors.Code, message string) {
+	// A failure caused by the route timeout is the server's slowness, not a bug
+	if statusCode >= http.StatusInternalServerError && errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		statusCode, code, message = http.StatusGatewayTimeout, apperrors.CodeTimeout, "Request timed out"
+	}
+
 	response := ErrorResponse{
 		Error:     http.StatusText(statusCode),
+		Code:      code,
 		Message:   message,
 		Timestamp: time.Now().UTC(),
 		RequestID: r.Header.Get("X-Request-ID"),
@@ -239,10 +870,45 @@ func (h *Handler) writeErrorResponse(w http.ResponseWriter, r *http.Request, sta
 	h.writeJSONResponse(w, statusCode, response)
 }
 
+// compressionLevel is the gzip level for API responses, trading a little
+// CPU for much smaller alert lists
+const compressionLevel = 5
+
+// compressibleTypes are the response types gzipped for clients that accept
+// it. Streaming types such as text/event-stream are left out so each event
+// is flushed as it is written.
+var compressibleTypes = []string{"application/json", "application/geo+json", "text/csv"}
+
+// allowMethods are the methods a 405 response may list in its Allow header
+var allowMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// methodNotAllowedHandler answers requests to a known path with an
+// unsupported method, listing the methods routes accepts for it
+func (h *Handler) methodNotAllowedHandler(routes chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range allowMethods {
+			// HEAD is served by GET routes through middleware.GetHead
+			match := method
+			if method == http.MethodHead {
+				match = http.MethodGet
+			}
+			if routes.Match(chi.NewRouteContext(), match, r.URL.Path) {
+				allowed = append(allowed, method)
+			}
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		h.writeErrorResponse(w, r, http.StatusMethodNotAllowed, apperrors.CodeMethodNotAllowed,
+			fmt.Sprintf("method %s is not allowed for %s", r.Method, r.URL.Path))
+	}
+}
+
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
-	Error     string    `json:"error"`
-	Message   string    `json:"message,omitempty"`
-	Timestamp time.Time `json:"timestamp"`
-	RequestID string    `json:"request_id,omitempty"`
+	Error     string         `json:"error"`
+	Code      apperrors.Code `json:"code"`
+	Message   string         `json:"message,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	RequestID string         `json:"request_id,omitempty"`
 }
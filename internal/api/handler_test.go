@@ -1,34 +1,47 @@
 package api
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
 )
 
 // MockStore implements the store interface for testing
 type MockStore struct {
-	alerts map[string]models.Alert
-	health error
+	alerts  map[string]models.Alert
+	history map[string][]models.Alert
+	health  error
 }
 
 func NewMockStore() *MockStore {
 	return &MockStore{
-		alerts: make(map[string]models.Alert),
-		health: nil,
+		alerts:  make(map[string]models.Alert),
+		history: make(map[string][]models.Alert),
+		health:  nil,
 	}
 }
 
 func (m *MockStore) UpsertAlerts(ctx context.Context, alerts []models.Alert) error {
 	for _, alert := range alerts {
+		if old, exists := m.alerts[alert.ID]; exists {
+			m.history[alert.ID] = append(m.history[alert.ID], old)
+		}
 		m.alerts[alert.ID] = alert
 	}
 	return nil
@@ -50,6 +63,44 @@ func (m *MockStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([]mod
 	return results, nil
 }
 
+func (m *MockStore) CountAlerts(ctx context.Context, q models.AlertQuery) (int, error) {
+	count := 0
+	for _, alert := range m.alerts {
+		if q.Matches(alert) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockStore) AggregateAlerts(ctx context.Context, q models.AlertQuery, groupBy string) ([]models.GroupCount, error) {
+	totals := make(map[string]int)
+	for _, alert := range m.alerts {
+		if q.Matches(alert) {
+			totals[alert.GroupKey(groupBy)]++
+		}
+	}
+	counts := []models.GroupCount{}
+	for key, count := range totals {
+		counts = append(counts, models.GroupCount{Key: key, Count: count})
+	}
+	models.SortGroupCounts(counts)
+	return counts, nil
+}
+
+func (m *MockStore) DistinctSources(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	sources := []string{}
+	for _, alert := range m.alerts {
+		if !seen[alert.Source] {
+			seen[alert.Source] = true
+			sources = append(sources, alert.Source)
+		}
+	}
+	sort.Strings(sources)
+	return sources, nil
+}
+
 func (m *MockStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
 	if alert, exists := m.alerts[id]; exists {
 		return &alert, nil
@@ -57,6 +108,60 @@ func (m *MockStore) GetAlert(ctx context.Context, id string) (*models.Alert, err
 	return nil, nil
 }
 
+func (m *MockStore) AlertHistory(ctx context.Context, id string) ([]models.Alert, error) {
+	return m.history[id], nil
+}
+
+func (m *MockStore) ChangesSince(ctx context.Context, token string) ([]models.Alert, string, error) {
+	var since *models.ChangeToken
+	if token != "" {
+		t, err := models.DecodeChangeToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+		since = t
+	}
+	var changed []models.Alert
+	for _, alert := range m.alerts {
+		if since == nil || since.Before(alert) {
+			changed = append(changed, alert)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		if !changed[i].UpdatedAt.Equal(changed[j].UpdatedAt) {
+			return changed[i].UpdatedAt.Before(changed[j].UpdatedAt)
+		}
+		return changed[i].ID < changed[j].ID
+	})
+	if len(changed) == 0 {
+		return changed, token, nil
+	}
+	return changed, models.NewChangeToken(changed[len(changed)-1]).Encode(), nil
+}
+
+func (m *MockStore) OverrideAlert(ctx context.Context, o models.AlertOverride) (*models.Alert, error) {
+	alert, exists := m.alerts[o.AlertID]
+	if !exists {
+		return nil, store.ErrAlertNotFound
+	}
+	alert = o.Apply(alert)
+	m.alerts[o.AlertID] = alert
+	return &alert, nil
+}
+
+func (m *MockStore) ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	now := time.Now().UTC()
+	count := 0
+	for id, alert := range m.alerts {
+		if alert.ArchivedAt == nil && alert.DetectedAt.Before(cutoff) {
+			alert.ArchivedAt = &now
+			m.alerts[id] = alert
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (m *MockStore) Health(ctx context.Context) error {
 	return m.health
 }
@@ -65,6 +170,26 @@ func (m *MockStore) SetHealthError(err error) {
 	m.health = err
 }
 
+// routeRegistrar is what main needs from a Handler
+type routeRegistrar interface {
+	RegisterRoutes(r *chi.Mux)
+}
+
+var _ routeRegistrar = (*Handler)(nil)
+
+func TestNewHandler(t *testing.T) {
+	var h routeRegistrar = NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit")
+	if h == (*Handler)(nil) {
+		t.Fatal("Expected NewHandler to return a handler")
+	}
+
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+	if len(r.Routes()) == 0 {
+		t.Error("Expected RegisterRoutes to mount routes")
+	}
+}
+
 func TestHandler_HealthEndpoints(t *testing.T) {
 	store := NewMockStore()
 	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
@@ -160,6 +285,127 @@ func TestHandler_ReadinessCheck_Unhealthy(t *testing.T) {
 	}
 }
 
+func TestHandler_ReadinessCheck_Dependencies(t *testing.T) {
+	redisDown := func(ctx context.Context) error { return errors.New("dial tcp: connection refused") }
+	pipelineStopped := func() bool { return false }
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		expectedStatus int
+		expectedState  string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "Redis down is advisory",
+			opts:           []Option{WithReadinessCheck("redis", false, redisDown)},
+			expectedStatus: http.StatusOK,
+			expectedState:  "degraded",
+			expectedChecks: map[string]string{"store": "ok", "redis": "error: dial tcp: connection refused"},
+		},
+		{
+			name:           "Redis down is critical when configured",
+			opts:           []Option{WithReadinessCheck("redis", true, redisDown)},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedState:  "not_ready",
+			expectedChecks: map[string]string{"store": "ok", "redis": "error: dial tcp: connection refused"},
+		},
+		{
+			name:           "Pipeline stopped is advisory",
+			opts:           []Option{WithPipelineStatus(pipelineStopped, false)},
+			expectedStatus: http.StatusOK,
+			expectedState:  "degraded",
+			expectedChecks: map[string]string{"store": "ok", "pipeline": "error: pipeline not running"},
+		},
+		{
+			name:           "Pipeline stopped is critical when configured",
+			opts:           []Option{WithPipelineStatus(pipelineStopped, true)},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedState:  "not_ready",
+			expectedChecks: map[string]string{"store": "ok", "pipeline": "error: pipeline not running"},
+		},
+		{
+			name:           "All dependencies healthy",
+			opts:           []Option{WithPipelineStatus(func() bool { return true }, false)},
+			expectedStatus: http.StatusOK,
+			expectedState:  "ready",
+			expectedChecks: map[string]string{"store": "ok", "pipeline": "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit", tt.opts...)
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest("GET", "/v1/health/ready", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			if response.Status != tt.expectedState {
+				t.Errorf("Expected status %s, got %s", tt.expectedState, response.Status)
+			}
+			for name, expected := range tt.expectedChecks {
+				if response.Checks[name] != expected {
+					t.Errorf("Expected check %s = %q, got %q", name, expected, response.Checks[name])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ReadinessCheck_Latency(t *testing.T) {
+	slowRedis := func(ctx context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithReadinessCheck("redis", false, slowRedis),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/health/ready", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response struct {
+		Dependencies map[string]map[string]interface{} `json:"dependencies"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+
+	for name, critical := range map[string]bool{"store": true, "redis": false} {
+		dep, ok := response.Dependencies[name]
+		if !ok {
+			t.Fatalf("Expected dependency %s in response", name)
+		}
+		latency, ok := dep["latency_ms"].(float64)
+		if !ok || latency < 0 {
+			t.Errorf("Expected numeric latency_ms for %s, got %v", name, dep["latency_ms"])
+		}
+		if dep["status"] != "ok" || dep["critical"] != critical {
+			t.Errorf("Expected %s to be ok with critical=%v, got %v", name, critical, dep)
+		}
+	}
+	if latency := response.Dependencies["redis"]["latency_ms"].(float64); latency < 5 {
+		t.Errorf("Expected redis latency of at least 5ms, got %v", latency)
+	}
+}
+
 func TestHandler_GetAlerts(t *testing.T) {
 	store := NewMockStore()
 
@@ -273,17 +519,60 @@ func TestHandler_GetAlerts(t *testing.T) {
 	}
 }
 
-func TestHandler_GetAlert(t *testing.T) {
+func TestHandler_GetAlerts_NextCursor(t *testing.T) {
 	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", DetectedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{ID: "alert-2", DetectedAt: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
 
-	testAlert := models.Alert{
-		ID:      "test-alert-1",
-		Source:  "test-source",
-		Title:   "Test Alert",
-		Summary: "Test summary",
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	get := func(query string) map[string]interface{} {
+		req := httptest.NewRequest("GET", "/v1/alerts"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var response map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode JSON response: %v", err)
+		}
+		return response
 	}
 
-	err := store.UpsertAlerts(context.Background(), []models.Alert{testAlert})
+	full := get("?limit=1")
+	cursor, ok := full["next_cursor"].(string)
+	if !ok || cursor == "" {
+		t.Fatalf("Expected next_cursor on a full page, got %v", full["next_cursor"])
+	}
+
+	partial := get("?limit=5")
+	if _, exists := partial["next_cursor"]; exists {
+		t.Error("Expected no next_cursor on a partial page")
+	}
+
+	req := httptest.NewRequest("GET", "/v1/alerts?cursor=%25%25%25", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for malformed cursor, got %d", w.Code)
+	}
+}
+
+func TestHandler_GetAlerts_Pagination(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", DetectedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{ID: "alert-2", DetectedAt: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{ID: "alert-3", DetectedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)},
+	})
 	if err != nil {
 		t.Fatalf("Failed to setup test data: %v", err)
 	}
@@ -293,64 +582,979 @@ func TestHandler_GetAlert(t *testing.T) {
 	handler.RegisterRoutes(r)
 
 	tests := []struct {
-		name           string
-		alertID        string
-		expectedStatus int
+		name          string
+		query         string
+		expectedCount int
+		expectedMore  bool
+		expectedTotal float64 // 0 when total should be absent
 	}{
-		{
-			name:           "Get existing alert",
-			alertID:        "test-alert-1",
-			expectedStatus: http.StatusOK,
-		},
-		{
-			name:           "Get non-existent alert",
-			alertID:        "non-existent",
-			expectedStatus: http.StatusNotFound,
-		},
-		{
-			name:           "Empty alert ID",
-			alertID:        "",
-			expectedStatus: http.StatusNotFound, // Chi router behavior
-		},
+		{"More rows than the limit", "?limit=2", 2, true, 0},
+		{"Exactly the limit", "?limit=3", 3, false, 0},
+		{"No limit", "", 3, false, 0},
+		{"With total", "?limit=1&include_total=true", 1, true, 3},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			endpoint := "/v1/alerts/" + tt.alertID
-			req := httptest.NewRequest("GET", endpoint, nil)
+			req := httptest.NewRequest("GET", "/v1/alerts"+tt.query, nil)
 			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			if count := response["count"].(float64); int(count) != tt.expectedCount {
+				t.Errorf("Expected count %d, got %v", tt.expectedCount, count)
+			}
+			if hasMore := response["has_more"]; hasMore != tt.expectedMore {
+				t.Errorf("Expected has_more %v, got %v", tt.expectedMore, hasMore)
+			}
+			if _, ok := response["next_cursor"]; ok != tt.expectedMore {
+				t.Errorf("Expected next_cursor present %v, got %v", tt.expectedMore, response["next_cursor"])
+			}
+			total, ok := response["total"]
+			if tt.expectedTotal == 0 && ok {
+				t.Errorf("Expected no total, got %v", total)
+			}
+			if tt.expectedTotal != 0 && total != tt.expectedTotal {
+				t.Errorf("Expected total %v, got %v", tt.expectedTotal, total)
+			}
+		})
+	}
 
+	req := httptest.NewRequest("GET", "/v1/alerts?include_total=maybe", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid include_total, got %d", w.Code)
+	}
+}
+
+func TestHandler_Compression(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Title: strings.Repeat("Port congestion ", 50), DetectedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		expectGzip     bool
+	}{
+		{"JSON with gzip", "/v1/alerts/alert-1", "gzip", true},
+		{"CSV with gzip", "/v1/alerts.csv", "gzip, deflate", true},
+		{"Without gzip", "/v1/alerts/alert-1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := httptest.NewRecorder()
+			r.ServeHTTP(plain, httptest.NewRequest("GET", tt.path, nil))
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
 			r.ServeHTTP(w, req)
 
-			if w.Code != tt.expectedStatus {
-				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Encoding") == "gzip"; got != tt.expectGzip {
+				t.Fatalf("Expected gzip %v, got Content-Encoding %q", tt.expectGzip, w.Header().Get("Content-Encoding"))
+			}
+			if !tt.expectGzip {
+				return
 			}
 
-			if tt.expectedStatus == http.StatusOK {
-				var alert models.Alert
-				err := json.NewDecoder(w.Body).Decode(&alert)
-				if err != nil {
-					t.Errorf("Failed to decode JSON response: %v", err)
-				}
+			if w.Header().Get("Content-Length") != "" {
+				t.Errorf("Expected no Content-Length on a compressed response, got %s", w.Header().Get("Content-Length"))
+			}
+			if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+				t.Errorf("Expected Vary: Accept-Encoding, got %q", w.Header().Get("Vary"))
+			}
+			if w.Header().Get("Cache-Control") != plain.Header().Get("Cache-Control") {
+				t.Errorf("Expected Cache-Control %q, got %q", plain.Header().Get("Cache-Control"), w.Header().Get("Cache-Control"))
+			}
 
-				if alert.ID != tt.alertID {
-					t.Errorf("Expected alert ID %s, got %s", tt.alertID, alert.ID)
-				}
+			gz, err := gzip.NewReader(w.Body)
+			if err != nil {
+				t.Fatalf("Expected gzip body: %v", err)
+			}
+			body, err := io.ReadAll(gz)
+			if err != nil {
+				t.Fatalf("Failed to decompress body: %v", err)
+			}
+			if string(body) != plain.Body.String() {
+				t.Errorf("Decompressed body differs:\n%s\nwant:\n%s", body, plain.Body.String())
+			}
+		})
+	}
+}
 
-				// Check cache header
-				cacheControl := w.Header().Get("Cache-Control")
-				if cacheControl != "public, max-age=300" {
-					t.Errorf("Expected Cache-Control header, got %s", cacheControl)
-				}
+func TestHandler_GetAlerts_GeoJSON(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Title: "Located", Latitude: 51.9, Longitude: 4.4},
+		{ID: "alert-2", Title: "Unlocated"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/alerts?format=geojson", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/geo+json" {
+		t.Errorf("Expected Content-Type application/geo+json, got %s", ct)
+	}
+
+	var fc FeatureCollection
+	if err := json.NewDecoder(w.Body).Decode(&fc); err != nil {
+		t.Fatalf("Failed to decode GeoJSON response: %v", err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+		t.Errorf("Expected 1 feature in a FeatureCollection, got %+v", fc)
+	}
+	if fc.Properties["skipped"] != float64(1) {
+		t.Errorf("Expected skipped 1, got %v", fc.Properties["skipped"])
+	}
+
+	req = httptest.NewRequest("GET", "/v1/alerts?format=xml", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unsupported format, got %d", w.Code)
+	}
+}
+
+func TestHandler_GetAlertsCSV(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Severity: "high"},
+		{ID: "alert-2", Severity: "low"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	for _, endpoint := range []string{"/v1/alerts.csv?severity=high", "/v1/alerts?format=csv&severity=high"} {
+		t.Run(endpoint, func(t *testing.T) {
+			req := httptest.NewRequest("GET", endpoint, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/csv" {
+				t.Errorf("Expected Content-Type text/csv, got %s", ct)
+			}
+			if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="alerts.csv"` {
+				t.Errorf("Unexpected Content-Disposition: %s", cd)
+			}
+
+			records, err := csv.NewReader(w.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to parse CSV: %v", err)
+			}
+			if len(records) != 2 || records[1][0] != "alert-1" {
+				t.Errorf("Expected header and alert-1 row, got %v", records)
 			}
 		})
 	}
 }
 
-func TestHandler_ParseAlertQuery(t *testing.T) {
-	handler := NewHandler(NewMockStore(), "test", "test", "test")
+// countingStore counts the alert queries made against a store
+type countingStore struct {
+	store.Store
+	queries int
+}
 
-	tests := []struct {
+func (s *countingStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error) {
+	s.queries++
+	return s.Store.QueryAlerts(ctx, q)
+}
+
+func TestHandler_GetAlertsCSV_Pages(t *testing.T) {
+	memory := store.NewInMemoryStore()
+	detectedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alerts := make([]models.Alert, 2*csvPageSize+1)
+	for i := range alerts {
+		alerts[i] = models.Alert{ID: fmt.Sprintf("alert-%04d", i), Severity: "high", DetectedAt: detectedAt.Add(time.Duration(i%7) * time.Minute)}
+	}
+	if err := memory.UpsertAlerts(context.Background(), alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		endpoint    string
+		wantRows    int
+		wantQueries int
+	}{
+		{"Default order reads every page", "/v1/alerts.csv", len(alerts), 3},
+		{"Limit reads one page", "/v1/alerts.csv?limit=10", 10, 1},
+		{"Other orders are capped at a page", "/v1/alerts.csv?sort=severity", csvPageSize, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counting := &countingStore{Store: memory}
+			handler := NewHandler(counting, "test-version", "test-build-time", "test-commit")
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest("GET", tt.endpoint, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			records, err := csv.NewReader(w.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to parse CSV: %v", err)
+			}
+			if len(records) != tt.wantRows+1 {
+				t.Fatalf("Expected header and %d rows, got %d records", tt.wantRows, len(records))
+			}
+			seen := make(map[string]bool)
+			for _, record := range records[1:] {
+				if seen[record[0]] {
+					t.Fatalf("Expected each alert once, got %s twice", record[0])
+				}
+				seen[record[0]] = true
+			}
+			if counting.queries != tt.wantQueries {
+				t.Errorf("Expected %d queries, got %d", tt.wantQueries, counting.queries)
+			}
+		})
+	}
+}
+
+func TestHandler_CountAlerts(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Severity: "high"},
+		{ID: "alert-2", Severity: "high"},
+		{ID: "alert-3", Severity: "low"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/alerts/count?severity=high", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response["count"] != float64(2) {
+		t.Errorf("Expected count 2, got %v", response["count"])
+	}
+	if _, exists := response["data"]; exists {
+		t.Error("Expected no alert rows in count response")
+	}
+}
+
+func TestHandler_AlertStats(t *testing.T) {
+	store := NewMockStore()
+	now := time.Now().UTC()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Severity: "high", DetectedAt: now},
+		{ID: "alert-2", Severity: "high", DetectedAt: now},
+		{ID: "alert-3", Severity: "low", DetectedAt: now},
+		{ID: "alert-4", Severity: "low", DetectedAt: now.Add(-72 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCode   string
+		wantData   []models.GroupCount
+	}{
+		{
+			name:       "group by severity",
+			query:      "group_by=severity",
+			wantStatus: http.StatusOK,
+			wantData:   []models.GroupCount{{Key: "high", Count: 2}, {Key: "low", Count: 2}},
+		},
+		{
+			name:       "group by severity since",
+			query:      "group_by=severity&since=" + now.Add(-time.Hour).Format(time.RFC3339),
+			wantStatus: http.StatusOK,
+			wantData:   []models.GroupCount{{Key: "high", Count: 2}, {Key: "low", Count: 1}},
+		},
+		{
+			name:       "missing group_by",
+			query:      "",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_group_by",
+		},
+		{
+			name:       "invalid group_by",
+			query:      "group_by=title",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_group_by",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/alerts/stats?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			if tt.wantCode != "" {
+				var errResp struct {
+					Code string `json:"code"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+					t.Fatalf("Failed to decode JSON response: %v", err)
+				}
+				if errResp.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, errResp.Code)
+				}
+				return
+			}
+
+			var response struct {
+				Data    []models.GroupCount `json:"data"`
+				Count   int                 `json:"count"`
+				GroupBy string              `json:"group_by"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			if !reflect.DeepEqual(response.Data, tt.wantData) {
+				t.Errorf("Expected %v, got %v", tt.wantData, response.Data)
+			}
+			if response.Count != len(tt.wantData) {
+				t.Errorf("Expected count %d, got %d", len(tt.wantData), response.Count)
+			}
+			if response.GroupBy != "severity" {
+				t.Errorf("Expected group_by severity, got %q", response.GroupBy)
+			}
+		})
+	}
+}
+
+func TestHandler_BatchAlerts(t *testing.T) {
+	store := NewMockStore()
+	archivedAt := time.Now()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1"},
+		{ID: "alert-2"},
+		{ID: "alert-3", ArchivedAt: &archivedAt},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tooMany := make([]string, maxBatchIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("alert-%d", i)
+	}
+	tooManyBody, _ := json.Marshal(map[string]interface{}{"ids": tooMany})
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantIDs     []string
+		wantMissing []string
+	}{
+		{
+			name:        "partial match keeps request order",
+			body:        `{"ids": ["alert-2", "nope", "alert-1", "alert-2"]}`,
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"alert-2", "alert-1"},
+			wantMissing: []string{"nope"},
+		},
+		{
+			name:        "archived alerts are missing by default",
+			body:        `{"ids": ["alert-1", "alert-3"]}`,
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"alert-1"},
+			wantMissing: []string{"alert-3"},
+		},
+		{
+			name:        "include archived",
+			body:        `{"ids": ["alert-1", "alert-3"], "include_archived": true}`,
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"alert-1", "alert-3"},
+			wantMissing: []string{},
+		},
+		{
+			name:       "empty ids",
+			body:       `{"ids": []}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too many ids",
+			body:       string(tooManyBody),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/alerts/batch", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data    []models.Alert `json:"data"`
+				Count   int            `json:"count"`
+				Missing []string       `json:"missing"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+
+			var gotIDs []string
+			for _, alert := range response.Data {
+				gotIDs = append(gotIDs, alert.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("Expected alerts %v, got %v", tt.wantIDs, gotIDs)
+			}
+			if response.Count != len(tt.wantIDs) {
+				t.Errorf("Expected count %d, got %d", len(tt.wantIDs), response.Count)
+			}
+			if !reflect.DeepEqual(response.Missing, tt.wantMissing) {
+				t.Errorf("Expected missing %v, got %v", tt.wantMissing, response.Missing)
+			}
+		})
+	}
+}
+
+func TestHandler_Disruptions(t *testing.T) {
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/disruptions", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Data  []string `json:"data"`
+		Count int      `json:"count"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response.Count != len(response.Data) || response.Count == 0 {
+		t.Errorf("Expected count to match data, got %d for %v", response.Count, response.Data)
+	}
+
+	found := map[string]bool{}
+	for _, code := range response.Data {
+		found[code] = true
+	}
+	for _, code := range []string{"port_status", "customs", "labor_strike", "general"} {
+		if !found[code] {
+			t.Errorf("Expected %s in %v", code, response.Data)
+		}
+	}
+}
+
+func TestHandler_GetAlert(t *testing.T) {
+	store := NewMockStore()
+
+	testAlert := models.Alert{
+		ID:      "test-alert-1",
+		Source:  "test-source",
+		Title:   "Test Alert",
+		Summary: "Test summary",
+	}
+
+	err := store.UpsertAlerts(context.Background(), []models.Alert{testAlert})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		alertID        string
+		expectedStatus int
+	}{
+		{
+			name:           "Get existing alert",
+			alertID:        "test-alert-1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Get non-existent alert",
+			alertID:        "non-existent",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Empty alert ID",
+			alertID:        "",
+			expectedStatus: http.StatusNotFound, // Chi router behavior
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := "/v1/alerts/" + tt.alertID
+			req := httptest.NewRequest("GET", endpoint, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var alert models.Alert
+				err := json.NewDecoder(w.Body).Decode(&alert)
+				if err != nil {
+					t.Errorf("Failed to decode JSON response: %v", err)
+				}
+
+				if alert.ID != tt.alertID {
+					t.Errorf("Expected alert ID %s, got %s", tt.alertID, alert.ID)
+				}
+
+				// Check cache header
+				cacheControl := w.Header().Get("Cache-Control")
+				if cacheControl != "public, max-age=300" {
+					t.Errorf("Expected Cache-Control header, got %s", cacheControl)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_GetAlert_ETag(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{{
+		ID:        "test-alert-1",
+		Title:     "Test Alert",
+		UpdatedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+	}})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/alerts/test-alert-1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header")
+	}
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{"Matching ETag", etag, http.StatusNotModified},
+		{"Matching weak ETag in list", `"other", W/` + etag, http.StatusNotModified},
+		{"Non-matching ETag", `"stale"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/alerts/test-alert-1", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Header().Get("ETag") != etag {
+				t.Errorf("Expected ETag %s, got %s", etag, w.Header().Get("ETag"))
+			}
+			if tt.expectedStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("Expected empty body on 304, got %q", w.Body.String())
+			}
+		})
+	}
+
+	// Updating the alert changes its ETag
+	store.alerts["test-alert-1"] = models.Alert{ID: "test-alert-1", UpdatedAt: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)}
+	req = httptest.NewRequest("GET", "/v1/alerts/test-alert-1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 after update, got %d", w.Code)
+	}
+}
+
+func TestHandler_HeadAlert(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{{
+		ID:        "test-alert-1",
+		Title:     "Test Alert",
+		UpdatedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+	}})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	get, err := http.Get(srv.URL + "/v1/alerts/test-alert-1")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	get.Body.Close()
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"Existing alert", "/v1/alerts/test-alert-1", http.StatusOK},
+		{"Missing alert", "/v1/alerts/missing", http.StatusNotFound},
+		{"Alert list", "/v1/alerts", http.StatusOK},
+		{"Root health", "/health", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Head(srv.URL + tt.path)
+			if err != nil {
+				t.Fatalf("HEAD failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if body, _ := io.ReadAll(resp.Body); len(body) != 0 {
+				t.Errorf("Expected empty body, got %q", body)
+			}
+			if resp.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("Expected JSON content type, got %s", resp.Header.Get("Content-Type"))
+			}
+			if tt.path == "/v1/alerts/test-alert-1" && resp.Header.Get("ETag") != get.Header.Get("ETag") {
+				t.Errorf("Expected ETag %s, got %s", get.Header.Get("ETag"), resp.Header.Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", nil),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		method        string
+		path          string
+		expectedAllow string
+	}{
+		{"DELETE", "/v1/alerts", "GET, HEAD"},
+		{"PUT", "/v1/alerts/batch", "GET, HEAD, POST"},
+		{"PUT", "/v1/alerts/alert-1", "GET, HEAD"},
+		{"GET", "/v1/alerts/alert-1/feedback", "POST"},
+		{"POST", "/health", "GET, HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("Expected status 405, got %d", w.Code)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.expectedAllow {
+				t.Errorf("Expected Allow %q, got %q", tt.expectedAllow, allow)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Expected JSON error body: %v", err)
+			}
+			if response.Code != apperrors.CodeMethodNotAllowed || response.Error != "Method Not Allowed" {
+				t.Errorf("Unexpected error response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestHandler_ArchivedAlerts(t *testing.T) {
+	store := NewMockStore()
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "active"},
+		{ID: "archived", ArchivedAt: &archivedAt},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"List hides archived", "/v1/alerts", http.StatusOK, 1},
+		{"List includes archived", "/v1/alerts?include_archived=true", http.StatusOK, 2},
+		{"Invalid include_archived", "/v1/alerts?include_archived=maybe", http.StatusBadRequest, 0},
+		{"Get archived hidden", "/v1/alerts/archived", http.StatusNotFound, 0},
+		{"Get archived included", "/v1/alerts/archived?include_archived=true", http.StatusOK, 0},
+		{"Get invalid include_archived", "/v1/alerts/archived?include_archived=maybe", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.endpoint, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedCount > 0 {
+				var response map[string]interface{}
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode JSON response: %v", err)
+				}
+				if response["count"] != float64(tt.expectedCount) {
+					t.Errorf("Expected count %d, got %v", tt.expectedCount, response["count"])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_RawPayload(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Raw: `{"title":"Port closed"}`, RawFormat: models.RawFormatJSON},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		wantRaw bool
+	}{
+		{"list omits raw", "GET", "/v1/alerts", "", false},
+		{"list includes raw", "GET", "/v1/alerts?include_raw=true", "", true},
+		{"batch omits raw", "POST", "/v1/alerts/batch", `{"ids": ["alert-1"]}`, false},
+		{"batch includes raw", "POST", "/v1/alerts/batch", `{"ids": ["alert-1"], "include_raw": true}`, true},
+		{"get includes raw", "GET", "/v1/alerts/alert-1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			body := w.Body.String()
+			if got := strings.Contains(body, `"raw_format":"json"`); got != tt.wantRaw {
+				t.Errorf("Expected raw_format present %v, got %v: %s", tt.wantRaw, got, body)
+			}
+			if got := strings.Contains(body, `"raw":`); got != tt.wantRaw {
+				t.Errorf("Expected raw present %v, got %v: %s", tt.wantRaw, got, body)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/v1/alerts?include_raw=maybe", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid include_raw, got %d", w.Code)
+	}
+}
+
+func TestHandler_ErrorCodes(t *testing.T) {
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"Bad limit", "/v1/alerts?limit=abc", http.StatusBadRequest, "invalid_limit"},
+		{"Limit out of range", "/v1/alerts/count?limit=5000", http.StatusBadRequest, "invalid_limit"},
+		{"Bad cursor", "/v1/alerts?cursor=not-a-cursor", http.StatusBadRequest, "invalid_cursor"},
+		{"Bad sort", "/v1/alerts.csv?sort=title", http.StatusBadRequest, "invalid_sort"},
+		{"Bad bbox", "/v1/alerts?bbox=1,2", http.StatusBadRequest, "invalid_bbox"},
+		{"Inverted time range", "/v1/alerts?since=2024-02-01T00:00:00Z&until=2024-01-01T00:00:00Z", http.StatusBadRequest, "invalid_time_range"},
+		{"Unsupported format", "/v1/alerts?format=xml", http.StatusBadRequest, "unsupported_format"},
+		{"Missing alert", "/v1/alerts/missing", http.StatusNotFound, "alert_not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.endpoint, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if string(response.Code) != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, response.Code)
+			}
+			if response.Message == "" {
+				t.Error("Expected a human-readable message")
+			}
+		})
+	}
+}
+
+func TestHandler_CheckTimeRange(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		maxWindow time.Duration
+		since     time.Time
+		until     time.Time
+		wantErr   bool
+	}{
+		{"Unset", 0, time.Time{}, time.Time{}, false},
+		{"Only until", 0, time.Time{}, now.AddDate(-10, 0, 0), false},
+		{"Same instant", 0, now.Add(-time.Hour), now.Add(-time.Hour), false},
+		{"Inverted", 0, now.Add(-time.Hour), now.Add(-2 * time.Hour), true},
+		{"Future until", 0, now.Add(-time.Hour), now.AddDate(1, 0, 0), false},
+		{"Since within skew", 0, now.Add(time.Hour), time.Time{}, false},
+		{"Since far in the future", 0, now.AddDate(0, 0, 7), time.Time{}, true},
+		{"Wide range without a window", 0, now.AddDate(-20, 0, 0), now, false},
+		{"Within the window", 30 * 24 * time.Hour, now.AddDate(0, 0, -7), now, false},
+		{"Wider than the window", 30 * 24 * time.Hour, now.AddDate(-1, 0, 0), now.AddDate(0, -1, 0), true},
+		{"Open range wider than the window", 30 * 24 * time.Hour, now.AddDate(-1, 0, 0), time.Time{}, true},
+		{"Future until measured from now", 30 * 24 * time.Hour, now.AddDate(0, 0, -7), now.AddDate(1, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit", WithMaxQueryWindow(tt.maxWindow))
+
+			err := handler.checkTimeRange(tt.since, tt.until, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && apperrors.CodeOf(err) != apperrors.CodeInvalidTimeRange {
+				t.Errorf("Expected code %s, got %s", apperrors.CodeInvalidTimeRange, apperrors.CodeOf(err))
+			}
+		})
+	}
+}
+
+func TestHandler_ParseAlertQuery(t *testing.T) {
+	handler := NewHandler(NewMockStore(), "test", "test", "test")
+
+	tests := []struct {
 		name        string
 		queryString string
 		expectError bool
@@ -405,6 +1609,221 @@ func TestHandler_ParseAlertQuery(t *testing.T) {
 			queryString: "since=invalid-time",
 			expectError: true,
 		},
+		{
+			name:        "Valid time range",
+			queryString: "since=2024-01-15T10:00:00Z&until=2024-01-16T10:00:00Z",
+			expectError: false,
+		},
+		{
+			name:        "Inverted time range",
+			queryString: "since=2024-01-16T10:00:00Z&until=2024-01-15T10:00:00Z",
+			expectError: true,
+		},
+		{
+			name:        "Valid cursor",
+			queryString: "cursor=" + models.Cursor{DetectedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), ID: "alert-1"}.Encode(),
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.Cursor == nil || q.Cursor.ID != "alert-1" {
+					return fmt.Errorf("expected cursor for alert-1, got %+v", q.Cursor)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Malformed cursor",
+			queryString: "cursor=not-a-cursor",
+			expectError: true,
+		},
+		{
+			name:        "Cursor with offset",
+			queryString: "offset=10&cursor=" + models.Cursor{DetectedAt: time.Now(), ID: "x"}.Encode(),
+			expectError: true,
+		},
+		{
+			name:        "Valid sort and order",
+			queryString: "sort=severity&order=asc",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.SortBy != "severity" || q.SortOrder != "asc" {
+					return fmt.Errorf("expected severity asc, got %s %s", q.SortBy, q.SortOrder)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Invalid sort field",
+			queryString: "sort=title",
+			expectError: true,
+		},
+		{
+			name:        "Sort field injection",
+			queryString: "sort=detected_at%3BDROP+TABLE+alerts",
+			expectError: true,
+		},
+		{
+			name:        "Invalid order",
+			queryString: "sort=confidence&order=sideways",
+			expectError: true,
+		},
+		{
+			name:        "Cursor with non-default sort",
+			queryString: "sort=severity&cursor=" + models.Cursor{DetectedAt: time.Now(), ID: "x"}.Encode(),
+			expectError: true,
+		},
+		{
+			name:        "Text search",
+			queryString: "q=Suez+canal",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.Text != "Suez canal" {
+					return fmt.Errorf("expected text 'Suez canal', got %q", q.Text)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Valid bbox",
+			queryString: "bbox=-120.5,30,-115,35.25",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				expected := models.BBox{MinLon: -120.5, MinLat: 30, MaxLon: -115, MaxLat: 35.25}
+				if q.BBox == nil || *q.BBox != expected {
+					return fmt.Errorf("expected bbox %+v, got %+v", expected, q.BBox)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Min confidence",
+			queryString: "min_confidence=0.75",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.MinConfidence != 0.75 {
+					return fmt.Errorf("expected min confidence 0.75, got %v", q.MinConfidence)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Min impact",
+			queryString: "min_impact=0.4",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.MinImpact != 0.4 {
+					return fmt.Errorf("expected min impact 0.4, got %v", q.MinImpact)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Sort by impact",
+			queryString: "sort=impact",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.SortBy != models.SortImpact {
+					return fmt.Errorf("expected sort by impact, got %q", q.SortBy)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Min severity",
+			queryString: "min_severity=medium",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.MinSeverity != "medium" {
+					return fmt.Errorf("expected min severity medium, got %q", q.MinSeverity)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Unknown min severity",
+			queryString: "min_severity=critical",
+			expectError: true,
+		},
+		{
+			name:        "Min confidence above one",
+			queryString: "min_confidence=1.5",
+			expectError: true,
+		},
+		{
+			name:        "Min confidence not a number",
+			queryString: "min_confidence=high",
+			expectError: true,
+		},
+		{
+			name:        "Escalated only",
+			queryString: "escalated=true&location=Port%20of%20Oakland",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.Escalated == nil || !*q.Escalated {
+					return fmt.Errorf("expected escalated filter true, got %v", q.Escalated)
+				}
+				if !reflect.DeepEqual(q.Locations, []string{"Port of Oakland"}) {
+					return fmt.Errorf("expected location Port of Oakland, got %v", q.Locations)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Invalid escalated",
+			queryString: "escalated=maybe",
+			expectError: true,
+		},
+		{
+			name:        "Only alerts with coordinates",
+			queryString: "has_coordinates=true",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.HasCoordinates == nil || !*q.HasCoordinates {
+					return fmt.Errorf("expected has_coordinates filter true, got %v", q.HasCoordinates)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Only alerts without coordinates",
+			queryString: "has_coordinates=false",
+			expectError: false,
+			checkFields: func(q models.AlertQuery) error {
+				if q.HasCoordinates == nil || *q.HasCoordinates {
+					return fmt.Errorf("expected has_coordinates filter false, got %v", q.HasCoordinates)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "Invalid has_coordinates",
+			queryString: "has_coordinates=sometimes",
+			expectError: true,
+		},
+		{
+			name:        "Negative min impact",
+			queryString: "min_impact=-0.1",
+			expectError: true,
+		},
+		{
+			name:        "Bbox with too few values",
+			queryString: "bbox=1,2,3",
+			expectError: true,
+		},
+		{
+			name:        "Bbox with non-numeric value",
+			queryString: "bbox=a,2,3,4",
+			expectError: true,
+		},
+		{
+			name:        "Bbox out of range",
+			queryString: "bbox=-200,0,10,10",
+			expectError: true,
+		},
+		{
+			name:        "Bbox min greater than max",
+			queryString: "bbox=10,0,-10,10",
+			expectError: true,
+		},
 		{
 			name:        "Multiple filters",
 			queryString: "source=test&severity=high&limit=10",
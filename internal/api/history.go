@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+)
+
+// alertHistoryHandler handles GET /alerts/{id}/history, returning the
+// versions an alert had before later upserts changed it, oldest first
+func (h *Handler) alertHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	alertID := chi.URLParam(r, "id")
+
+	includeArchived, err := parseIncludeArchived(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
+		return
+	}
+
+	alert, err := h.store.GetAlert(ctx, alertID)
+	if err != nil {
+		logger.WithContext(ctx).Error("Failed to get alert", "error", err, "alert_id", alertID)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+
+	if alert == nil || (alert.ArchivedAt != nil && !includeArchived) {
+		h.writeErrorResponse(w, r, http.StatusNotFound, apperrors.CodeAlertNotFound, "Alert not found")
+		return
+	}
+
+	versions, err := h.store.AlertHistory(ctx, alertID)
+	if err != nil {
+		logger.WithContext(ctx).Error("Failed to get alert history", "error", err, "alert_id", alertID)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+	omitRaw(versions)
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"data":      versions,
+		"count":     len(versions),
+		"timestamp": time.Now().UTC(),
+	})
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestHandler_AlertHistory(t *testing.T) {
+	logger.Init("error", "text")
+
+	archivedAt := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	store := NewMockStore()
+	for _, severity := range []string{"low", "medium", "high"} {
+		alert := models.Alert{ID: "alert-1", Severity: severity, Raw: "<item/>"}
+		if err := store.UpsertAlerts(context.Background(), []models.Alert{alert}); err != nil {
+			t.Fatalf("Failed to setup test data: %v", err)
+		}
+	}
+	store.alerts["archived"] = models.Alert{ID: "archived", ArchivedAt: &archivedAt}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedCode   apperrors.Code
+		wantSeverities []string
+	}{
+		{"Prior versions oldest first", "/v1/alerts/alert-1/history", http.StatusOK, "", []string{"low", "medium"}},
+		{"Archived alert", "/v1/alerts/archived/history", http.StatusNotFound, apperrors.CodeAlertNotFound, nil},
+		{"Archived alert included", "/v1/alerts/archived/history?include_archived=true", http.StatusOK, "", nil},
+		{"Invalid include_archived", "/v1/alerts/archived/history?include_archived=maybe", http.StatusBadRequest, apperrors.CodeInvalidInput, nil},
+		{"Unknown alert", "/v1/alerts/missing/history", http.StatusNotFound, apperrors.CodeAlertNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != "" {
+				var errResp ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+					t.Fatalf("Failed to decode error response: %v", err)
+				}
+				if errResp.Code != tt.expectedCode {
+					t.Errorf("Expected code %q, got %q", tt.expectedCode, errResp.Code)
+				}
+				return
+			}
+
+			var response struct {
+				Data  []models.Alert `json:"data"`
+				Count int            `json:"count"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Count != len(tt.wantSeverities) || len(response.Data) != len(tt.wantSeverities) {
+				t.Fatalf("Expected %d versions, got %d (count %d)", len(tt.wantSeverities), len(response.Data), response.Count)
+			}
+			for i, severity := range tt.wantSeverities {
+				if response.Data[i].Severity != severity {
+					t.Errorf("Expected version %d to have severity %s, got %s", i, severity, response.Data[i].Severity)
+				}
+				if response.Data[i].Raw != "" {
+					t.Errorf("Expected version %d to omit raw", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/pipeline"
+	"github.com/rajasatyajit/SupplyChain/pkg/utils"
+)
+
+// object is a JSON object in the OpenAPI document
+type object = map[string]interface{}
+
+// openAPIHandler serves the OpenAPI 3.0 description of this API
+func (h *Handler) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	h.writeJSONResponse(w, http.StatusOK, openAPISpec(h.version))
+}
+
+// openAPISpec builds the OpenAPI document. Keep it in step with
+// RegisterRoutes and parseAlertQuery.
+func openAPISpec(version string) object {
+	alertFilters := []object{
+		queryParam("source", "Filter by source; repeatable", arrayOf(stringSchema())),
+		queryParam("severity", "Filter by severity; repeatable", arrayOf(enumSchema("low", "medium", "high", "unknown"))),
+		queryParam("disruption", "Filter by disruption category; repeatable", arrayOf(enumSchema(utils.DisruptionCategories()...))),
+		queryParam("region", "Filter by region; repeatable", arrayOf(stringSchema())),
+		queryParam("country", "Filter by country; repeatable", arrayOf(stringSchema())),
+		queryParam("location", "Filter by geocoded location; repeatable", arrayOf(stringSchema())),
+		queryParam("q", "Full-text search over title and summary", stringSchema()),
+		queryParam("bbox", "Bounding box minLon,minLat,maxLon,maxLat", stringSchema()),
+		queryParam("min_severity", "Only alerts of this severity or higher", enumSchema("low", "medium", "high")),
+		queryParam("min_confidence", "Only alerts with at least this confidence", object{"type": "number", "minimum": 0, "maximum": 1}),
+		queryParam("min_impact", "Only alerts with at least this impact score", object{"type": "number", "minimum": 0, "maximum": 1}),
+		queryParam("has_coordinates", "Only alerts that were (true) or were not (false) geocoded to coordinates", object{"type": "boolean"}),
+		queryParam("escalated", "Only alerts that did (true) or did not (false) raise the severity of the previous alert for their location and disruption", object{"type": "boolean"}),
+		queryParam("since", "Only alerts detected at or after this time", object{"type": "string", "format": "date-time"}),
+		queryParam("until", "Only alerts detected at or before this time", object{"type": "string", "format": "date-time"}),
+		queryParam("include_archived", "Include archived alerts", object{"type": "boolean", "default": false}),
+	}
+	paging := []object{
+		queryParam("limit", "Maximum number of alerts", object{"type": "integer", "minimum": 0, "maximum": 1000}),
+		queryParam("offset", "Number of alerts to skip; cannot be combined with cursor", object{"type": "integer", "minimum": 0}),
+		queryParam("cursor", "next_cursor from a previous page", stringSchema()),
+		queryParam("sort", "Sort field", enumSchema(models.SortDetectedAt, models.SortPublishedAt, models.SortSeverity, models.SortConfidence, models.SortImpact)),
+		queryParam("order", "Sort order", enumSchema(models.SortDesc, models.SortAsc)),
+	}
+	listParams := append(append([]object{}, alertFilters...), paging...)
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "SupplyChain API",
+			"version":     version,
+			"description": "Real-time supply chain disruption alerts",
+		},
+		"paths": object{
+			"/v1/health":       object{"get": operation("Health check", nil, jsonResponse("OK", object{"type": "object"}))},
+			"/v1/health/ready": object{"get": operation("Readiness check including dependency health", nil, jsonResponse("Ready", object{"type": "object"}), errorResponse("503", "A critical dependency is unavailable"))},
+			"/v1/health/live":  object{"get": operation("Liveness check", nil, jsonResponse("Alive", object{"type": "object"}))},
+			"/v1/version":      object{"get": operation("Build information", nil, jsonResponse("Version", object{"type": "object"}))},
+			"/v1/alerts": object{"get": operation("List alerts",
+				append(listParams,
+					queryParam("format", "Response format", enumSchema("json", "geojson", "csv")),
+					queryParam("include_raw", "Include each alert's raw source item", object{"type": "boolean", "default": false}),
+					queryParam("include_total", "Also count every matching alert; costs an extra query", object{"type": "boolean", "default": false}),
+				),
+				jsonResponse("Alerts", ref("AlertList")),
+				errorResponse("400", "Invalid query parameter"),
+			)},
+			"/v1/alerts.csv": object{"get": operation("Export alerts as CSV", listParams,
+				object{"200": object{"description": "Alerts as CSV", "content": object{"text/csv": object{"schema": stringSchema()}}}},
+				errorResponse("400", "Invalid query parameter"),
+			)},
+			"/v1/alerts/count": object{"get": operation("Count matching alerts", alertFilters,
+				jsonResponse("Count", object{"type": "object", "properties": object{"count": object{"type": "integer"}}}),
+				errorResponse("400", "Invalid query parameter"),
+			)},
+			"/v1/alerts/stats": object{"get": operation("Count matching alerts grouped by a field",
+				append([]object{{"in": "query", "name": "group_by", "required": true, "description": "Field to group by",
+					"schema": enumSchema(models.GroupBySeverity, models.GroupByDisruption, models.GroupByRegion, models.GroupByCountry)}}, alertFilters...),
+				jsonResponse("Counts per group, largest first", object{"type": "object", "properties": object{
+					"data": arrayOf(object{"type": "object", "properties": object{
+						"key":   stringSchema(),
+						"count": object{"type": "integer"},
+					}}),
+					"count":    object{"type": "integer"},
+					"group_by": stringSchema(),
+				}}),
+				errorResponse("400", "Invalid group_by or query parameter"),
+			)},
+			"/v1/alerts/batch": object{"post": withJSONBody(operation("Fetch up to 200 alerts by ID", nil,
+				jsonResponse("Alerts in request order, plus the IDs that were not found", object{"type": "object", "properties": object{
+					"data":    arrayOf(ref("Alert")),
+					"count":   object{"type": "integer"},
+					"missing": arrayOf(stringSchema()),
+				}}),
+				errorResponse("400", "Empty or oversized ids list"),
+				errorResponse("413", "Request body too large"),
+			), object{"type": "object", "required": []string{"ids"}, "properties": object{
+				"ids":              arrayOf(stringSchema()),
+				"include_archived": object{"type": "boolean", "default": false},
+				"include_raw":      object{"type": "boolean", "default": false},
+			}})},
+			"/v1/alerts/changes": object{"get": operation("List alerts updated since a version token, oldest change first, archived alerts included",
+				[]object{
+					queryParam("since_version", "next_version from the previous call; omit to start from the beginning", stringSchema()),
+					queryParam("include_raw", "Include the raw source item", object{"type": "boolean", "default": false}),
+				},
+				jsonResponse("Changed alerts", object{"type": "object", "properties": object{
+					"data":         arrayOf(ref("Alert")),
+					"count":        object{"type": "integer"},
+					"next_version": stringSchema(),
+				}}),
+				errorResponse("400", "Invalid since_version"),
+			)},
+			"/v1/alerts/{id}": object{"get": operation("Get an alert",
+				[]object{
+					{"in": "path", "name": "id", "required": true, "schema": stringSchema()},
+					queryParam("include_archived", "Return the alert even if archived", object{"type": "boolean", "default": false}),
+				},
+				jsonResponse("Alert", ref("Alert")),
+				object{"304": object{"description": "Not modified since the ETag in If-None-Match"}},
+				errorResponse("404", "Alert not found"),
+			)},
+			"/v1/alerts/{id}/related": object{"get": operation("List alerts with the same disruption and region near the alert in time, closest first",
+				[]object{
+					{"in": "path", "name": "id", "required": true, "schema": stringSchema()},
+					queryParam("window", "How far before and after the alert to look, as a Go duration", object{"type": "string", "default": "24h"}),
+					queryParam("limit", "Maximum related alerts to return", object{"type": "integer", "minimum": 1, "maximum": maxRelatedLimit, "default": defaultRelatedLimit}),
+					queryParam("include_archived", "Allow an archived anchor and return archived related alerts", object{"type": "boolean", "default": false}),
+				},
+				jsonResponse("Related alerts", object{"type": "object", "properties": object{
+					"data":   arrayOf(ref("Alert")),
+					"count":  object{"type": "integer"},
+					"window": stringSchema(),
+				}}),
+				errorResponse("400", "Invalid window or limit"),
+				errorResponse("404", "Alert not found"),
+			)},
+			"/v1/alerts/{id}/history": object{"get": operation("List the alert's prior versions, oldest first",
+				[]object{
+					{"in": "path", "name": "id", "required": true, "schema": stringSchema()},
+					queryParam("include_archived", "Return history even if the alert is archived", object{"type": "boolean", "default": false}),
+				},
+				jsonResponse("Prior versions", object{"type": "object", "properties": object{
+					"data":  arrayOf(ref("Alert")),
+					"count": object{"type": "integer"},
+				}}),
+				errorResponse("404", "Alert not found"),
+			)},
+			"/v1/alerts/{id}/feedback": object{"post": withSecurity(withJSONBody(operation("Correct an alert's severity or disruption category; only available when ADMIN_SECRET is set",
+				[]object{{"in": "path", "name": "id", "required": true, "schema": stringSchema()}},
+				jsonResponse("Corrected alert", ref("Alert")),
+				errorResponse("400", "Invalid severity, disruption or submitted_by"),
+				errorResponse("401", "Missing or invalid admin token"),
+				errorResponse("404", "Alert not found"),
+			), object{"type": "object", "required": []string{"submitted_by"}, "properties": object{
+				"severity":     enumSchema(feedbackSeverities...),
+				"disruption":   enumSchema(utils.DisruptionCategories()...),
+				"submitted_by": stringSchema(),
+			}}))},
+			"/v1/disruptions": object{"get": operation("List disruption categories", nil,
+				jsonResponse("Categories", object{"type": "object", "properties": object{
+					"data":  arrayOf(stringSchema()),
+					"count": object{"type": "integer"},
+				}}),
+			)},
+			"/v1/sources": object{"get": operation("List configured ingestion sources and the sources of stored alerts", nil,
+				jsonResponse("Sources", object{"type": "object", "properties": object{
+					"data": arrayOf(object{"type": "object", "properties": object{
+						"name":     stringSchema(),
+						"type":     enumSchema("rss", "atom", "jsonfeed"),
+						"interval": object{"type": "string", "example": "5m0s"},
+					}}),
+					"count":    object{"type": "integer"},
+					"observed": arrayOf(stringSchema()),
+				}}),
+			)},
+			"/v1/openapi.json": object{"get": operation("This OpenAPI document", nil, jsonResponse("OpenAPI document", object{"type": "object"}))},
+			"/v1/admin/pipeline/run": object{"post": withSecurity(withJSONBody(operation("Run a pipeline source immediately; only available when ADMIN_SECRET is set", nil,
+				jsonResponse("Run result", object{"type": "object", "properties": object{
+					"source":    stringSchema(),
+					"processed": object{"type": "integer"},
+				}}),
+				errorResponse("400", "Missing or invalid source"),
+				errorResponse("401", "Missing or invalid admin token"),
+				errorResponse("404", "Source not found"),
+			), object{"type": "object", "required": []string{"source"}, "properties": object{"source": stringSchema()}}))},
+			"/v1/admin/pipeline/status": object{"get": withSecurity(operation("Latest run status of each pipeline source; only available when ADMIN_SECRET is set", nil,
+				jsonResponse("Source statuses", object{"type": "object", "properties": object{
+					"data": arrayOf(object{"type": "object", "properties": object{
+						"name":                 stringSchema(),
+						"last_run_at":          object{"type": "string", "format": "date-time"},
+						"last_error":           stringSchema(),
+						"last_count":           object{"type": "integer"},
+						"consecutive_failures": object{"type": "integer"},
+						"breaker":              enumSchema(pipeline.BreakerClosed, pipeline.BreakerOpen, pipeline.BreakerHalfOpen),
+						"breaker_open_until":   object{"type": "string", "format": "date-time"},
+					}}),
+					"count": object{"type": "integer"},
+				}}),
+				errorResponse("401", "Missing or invalid admin token"),
+			))},
+			"/v1/admin/sources/validate": object{"post": withSecurity(withJSONBody(operation("Fetch a candidate source once and report what parsed, without storing anything; only available when ADMIN_SECRET is set", nil,
+				jsonResponse("Parse result", object{"type": "object", "properties": object{
+					"type":   stringSchema(),
+					"count":  object{"type": "integer"},
+					"sample": arrayOf(ref("Alert")),
+				}}),
+				errorResponse("400", "Unsupported type or invalid URLs"),
+				errorResponse("401", "Missing or invalid admin token"),
+			), object{"type": "object", "required": []string{"type", "urls"}, "properties": object{
+				"type":           enumSchema("rss", "atom", "jsonfeed"),
+				"urls":           arrayOf(object{"type": "string", "format": "uri"}),
+				"name":           stringSchema(),
+				"max_body_bytes": object{"type": "integer"},
+				"headers":        object{"type": "object", "additionalProperties": stringSchema()},
+			}}))},
+			"/v1/admin/webhooks": object{
+				"post": withSecurity(withJSONBody(operation("Register an outbound webhook; the secret is generated if omitted and only returned here", nil,
+					object{"201": object{
+						"description": "Webhook created",
+						"content":     object{"application/json": object{"schema": ref("Webhook")}},
+					}},
+					errorResponse("400", "Invalid URL or body"),
+					errorResponse("401", "Missing or invalid admin token"),
+				), object{"type": "object", "required": []string{"url"}, "properties": object{
+					"url":    object{"type": "string", "format": "uri"},
+					"secret": stringSchema(),
+					"filter": ref("WebhookFilter"),
+				}})),
+				"get": withSecurity(operation("List outbound webhooks without secrets", nil,
+					jsonResponse("Webhooks", object{"type": "object", "properties": object{
+						"data":  arrayOf(ref("Webhook")),
+						"count": object{"type": "integer"},
+					}}),
+					errorResponse("401", "Missing or invalid admin token"),
+				)),
+			},
+			"/v1/admin/webhooks/{id}": object{"delete": withSecurity(operation("Delete an outbound webhook",
+				[]object{{"in": "path", "name": "id", "required": true, "schema": stringSchema()}},
+				object{"204": object{"description": "Webhook deleted"}},
+				errorResponse("401", "Missing or invalid admin token"),
+				errorResponse("404", "Webhook not found"),
+			))},
+		},
+		"components": object{
+			"schemas": object{
+				"Alert": alertSchema(),
+				"AlertList": object{
+					"type": "object",
+					"properties": object{
+						"data":        arrayOf(ref("Alert")),
+						"count":       object{"type": "integer"},
+						"timestamp":   object{"type": "string", "format": "date-time"},
+						"has_more":    object{"type": "boolean", "description": "Whether another page follows this one"},
+						"total":       object{"type": "integer", "description": "Number of matching alerts across all pages; present with include_total=true"},
+						"next_cursor": object{"type": "string", "description": "Present when has_more is true in the default order; pass back as cursor"},
+					},
+				},
+				"Webhook": object{
+					"type": "object",
+					"properties": object{
+						"id":               stringSchema(),
+						"url":              stringSchema(),
+						"secret":           object{"type": "string", "description": "HMAC-SHA256 signing key; only returned on creation"},
+						"filter":           ref("WebhookFilter"),
+						"failure_count":    object{"type": "integer"},
+						"last_error":       stringSchema(),
+						"last_delivery_at": object{"type": "string", "format": "date-time"},
+						"created_at":       object{"type": "string", "format": "date-time"},
+					},
+				},
+				"WebhookFilter": object{
+					"type": "object",
+					"properties": object{
+						"sources":     arrayOf(stringSchema()),
+						"severities":  arrayOf(enumSchema("low", "medium", "high", "unknown")),
+						"disruptions": arrayOf(enumSchema(utils.DisruptionCategories()...)),
+						"regions":     arrayOf(stringSchema()),
+						"countries":   arrayOf(stringSchema()),
+						"text":        stringSchema(),
+					},
+				},
+				"ErrorResponse": object{
+					"type": "object",
+					"properties": object{
+						"error":      stringSchema(),
+						"code":       object{"type": "string", "description": "Machine-readable error code"},
+						"message":    stringSchema(),
+						"timestamp":  object{"type": "string", "format": "date-time"},
+						"request_id": stringSchema(),
+					},
+				},
+			},
+			"securitySchemes": object{
+				"adminToken": object{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// alertSchema describes models.Alert, taking property names from its JSON tags
+func alertSchema() object {
+	enums := map[string]object{
+		"severity":   enumSchema("low", "medium", "high", "unknown"),
+		"sentiment":  enumSchema("negative", "neutral", "positive"),
+		"disruption": enumSchema(utils.DisruptionCategories()...),
+		"raw_format": enumSchema(models.RawFormatJSON),
+	}
+
+	properties := object{}
+	t := reflect.TypeOf(models.Alert{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		if schema, ok := enums[name]; ok {
+			properties[name] = schema
+			continue
+		}
+
+		switch field.Type {
+		case reflect.TypeOf(time.Time{}), reflect.TypeOf(&time.Time{}):
+			properties[name] = object{"type": "string", "format": "date-time"}
+		case reflect.TypeOf(float64(0)):
+			properties[name] = object{"type": "number", "format": "double"}
+		default:
+			properties[name] = stringSchema()
+		}
+	}
+
+	return object{"type": "object", "properties": properties}
+}
+
+func operation(summary string, params []object, responses ...object) object {
+	merged := object{}
+	for _, r := range responses {
+		for status, resp := range r {
+			merged[status] = resp
+		}
+	}
+	op := object{"summary": summary, "responses": merged}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	return op
+}
+
+func withJSONBody(op, schema object) object {
+	op["requestBody"] = object{
+		"required": true,
+		"content":  object{"application/json": object{"schema": schema}},
+	}
+	return op
+}
+
+func withSecurity(op object) object {
+	op["security"] = []object{{"adminToken": []string{}}}
+	return op
+}
+
+func queryParam(name, description string, schema object) object {
+	return object{"in": "query", "name": name, "description": description, "schema": schema}
+}
+
+func jsonResponse(description string, schema object) object {
+	return object{"200": object{
+		"description": description,
+		"content":     object{"application/json": object{"schema": schema}},
+	}}
+}
+
+func errorResponse(status, description string) object {
+	return object{status: object{
+		"description": description,
+		"content":     object{"application/json": object{"schema": ref("ErrorResponse")}},
+	}}
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+func stringSchema() object {
+	return object{"type": "string"}
+}
+
+func arrayOf(items object) object {
+	return object{"type": "array", "items": items}
+}
+
+func enumSchema(values ...string) object {
+	return object{"type": "string", "enum": values}
+}
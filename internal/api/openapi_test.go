@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+)
+
+func TestHandler_OpenAPI(t *testing.T) {
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", &mockRunner{}),
+		WithWebhooks(store.NewInMemoryWebhookStore()),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("Failed to decode OpenAPI document: %v", err)
+	}
+
+	if doc.OpenAPI != "3.0.3" || doc.Info.Version != "test-version" {
+		t.Errorf("Unexpected document header: %s %s", doc.OpenAPI, doc.Info.Version)
+	}
+	if _, ok := doc.Paths["/v1/alerts"]["get"]; !ok {
+		t.Error("Expected GET /v1/alerts in paths")
+	}
+
+	// Every registered route is described
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route == "/health" {
+			return nil
+		}
+		if _, ok := doc.Paths[route]; !ok {
+			t.Errorf("Route %s %s missing from OpenAPI document", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk routes: %v", err)
+	}
+
+	for _, field := range []string{"id", "detected_at", "severity", "language", "archived_at"} {
+		if _, ok := doc.Components.Schemas["Alert"].Properties[field]; !ok {
+			t.Errorf("Expected Alert schema property %s", field)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// errPipelineStopped is reported when the pipeline status func returns false
+var errPipelineStopped = errors.New("pipeline not running")
+
+// readinessCheck is a dependency reported by the readiness endpoint.
+// Failing critical checks make the service unready; advisory checks are
+// only reported.
+type readinessCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// dependencyStatus is a readiness check's result with how long it took, so
+// a slowing dependency shows up before it fails
+type dependencyStatus struct {
+	Status    string  `json:"status"` // "ok" or "error"
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// runReadinessCheck runs c and times it
+func runReadinessCheck(ctx context.Context, c readinessCheck) dependencyStatus {
+	start := time.Now()
+	err := c.check(ctx)
+	dep := dependencyStatus{
+		Status:    "ok",
+		Critical:  c.critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		dep.Status = "error"
+		dep.Error = err.Error()
+	}
+	return dep
+}
+
+// Option configures optional Handler dependencies
+type Option func(*Handler)
+
+// WithReadinessCheck adds a named dependency check to the readiness endpoint,
+// e.g. a Redis ping. Critical checks return 503 when they fail.
+func WithReadinessCheck(name string, critical bool, check func(ctx context.Context) error) Option {
+	return func(h *Handler) {
+		h.checks = append(h.checks, readinessCheck{name: name, critical: critical, check: check})
+	}
+}
+
+// WithPipelineStatus reports whether the ingestion pipeline is running,
+// typically Pipeline.IsRunning, under the "pipeline" readiness check
+func WithPipelineStatus(isRunning func() bool, critical bool) Option {
+	return WithReadinessCheck("pipeline", critical, func(ctx context.Context) error {
+		if !isRunning() {
+			return errPipelineStopped
+		}
+		return nil
+	})
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// Bounds for GET /alerts/{id}/related
+const (
+	defaultRelatedWindow = 24 * time.Hour
+	maxRelatedWindow     = 30 * 24 * time.Hour
+	defaultRelatedLimit  = 20
+	maxRelatedLimit      = 100
+)
+
+// relatedAlertsHandler handles GET /alerts/{id}/related, returning alerts
+// with the same disruption and region detected within window of the
+// anchor alert, closest in time first
+func (h *Handler) relatedAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	alertID := chi.URLParam(r, "id")
+
+	window := defaultRelatedWindow
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		d, err := time.ParseDuration(windowStr)
+		if err != nil || d <= 0 || d > maxRelatedWindow {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, fmt.Sprintf("window must be a positive duration up to %s, got %s", maxRelatedWindow, windowStr))
+			return
+		}
+		window = d
+	}
+
+	limit := defaultRelatedLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxRelatedLimit {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidLimit, fmt.Sprintf("limit must be between 1 and %d", maxRelatedLimit))
+			return
+		}
+		limit = n
+	}
+
+	includeArchived, err := parseIncludeArchived(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeOf(err), err.Error())
+		return
+	}
+
+	anchor, err := h.store.GetAlert(ctx, alertID)
+	if err != nil {
+		logger.WithContext(ctx).Error("Failed to get alert", "error", err, "alert_id", alertID)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+	if anchor == nil || (anchor.ArchivedAt != nil && !includeArchived) {
+		h.writeErrorResponse(w, r, http.StatusNotFound, apperrors.CodeAlertNotFound, "Alert not found")
+		return
+	}
+
+	// The closest alerts on each side of the anchor are the newest before
+	// it and the oldest after it, so two bounded queries cover the window
+	base := models.AlertQuery{
+		Disruptions:     []string{anchor.Disruption},
+		Regions:         []string{anchor.Region},
+		SortBy:          models.SortDetectedAt,
+		Limit:           limit + 1,
+		IncludeArchived: includeArchived,
+	}
+	before, after := base, base
+	before.Since, before.Until, before.SortOrder = anchor.DetectedAt.Add(-window), anchor.DetectedAt, models.SortDesc
+	after.Since, after.Until, after.SortOrder = anchor.DetectedAt, anchor.DetectedAt.Add(window), models.SortAsc
+
+	var related []models.Alert
+	seen := map[string]bool{anchor.ID: true}
+	for _, q := range []models.AlertQuery{before, after} {
+		alerts, err := h.store.QueryAlerts(ctx, q)
+		if err != nil {
+			logger.WithContext(ctx).Error("Failed to query related alerts", "error", err, "alert_id", alertID)
+			h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+			return
+		}
+		for _, alert := range alerts {
+			if !seen[alert.ID] {
+				seen[alert.ID] = true
+				related = append(related, alert)
+			}
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		return absDuration(related[i].DetectedAt.Sub(anchor.DetectedAt)) < absDuration(related[j].DetectedAt.Sub(anchor.DetectedAt))
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	omitRaw(related)
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"data":      related,
+		"count":     len(related),
+		"window":    window.String(),
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// absDuration returns the magnitude of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
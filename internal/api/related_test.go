@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestHandler_RelatedAlerts(t *testing.T) {
+	logger.Init("error", "text")
+
+	anchorAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	archivedAt := anchorAt
+	store := NewMockStore()
+	for _, alert := range []models.Alert{
+		{ID: "anchor", Disruption: "port_status", Region: "Asia", DetectedAt: anchorAt},
+		{ID: "before-1h", Disruption: "port_status", Region: "Asia", DetectedAt: anchorAt.Add(-time.Hour)},
+		{ID: "after-30m", Disruption: "port_status", Region: "Asia", DetectedAt: anchorAt.Add(30 * time.Minute)},
+		{ID: "after-5h", Disruption: "port_status", Region: "Asia", DetectedAt: anchorAt.Add(5 * time.Hour)},
+		{ID: "before-2d", Disruption: "port_status", Region: "Asia", DetectedAt: anchorAt.Add(-48 * time.Hour)},
+		{ID: "archived", Disruption: "port_status", Region: "Asia", DetectedAt: anchorAt.Add(2 * time.Hour), ArchivedAt: &archivedAt},
+		{ID: "other-region", Disruption: "port_status", Region: "Europe", DetectedAt: anchorAt},
+		{ID: "other-disruption", Disruption: "weather", Region: "Asia", DetectedAt: anchorAt},
+	} {
+		store.alerts[alert.ID] = alert
+	}
+
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit")
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedCode   apperrors.Code
+		wantIDs        []string
+	}{
+		{"Default window", "/v1/alerts/anchor/related", http.StatusOK, "", []string{"after-30m", "before-1h", "after-5h"}},
+		{"Narrow window", "/v1/alerts/anchor/related?window=1h", http.StatusOK, "", []string{"after-30m", "before-1h"}},
+		{"Wide window", "/v1/alerts/anchor/related?window=72h", http.StatusOK, "", []string{"after-30m", "before-1h", "after-5h", "before-2d"}},
+		{"Limit", "/v1/alerts/anchor/related?window=1h&limit=1", http.StatusOK, "", []string{"after-30m"}},
+		{"Include archived", "/v1/alerts/anchor/related?include_archived=true", http.StatusOK, "", []string{"after-30m", "before-1h", "archived", "after-5h"}},
+		{"Unknown anchor", "/v1/alerts/missing/related", http.StatusNotFound, apperrors.CodeAlertNotFound, nil},
+		{"Invalid window", "/v1/alerts/anchor/related?window=soon", http.StatusBadRequest, apperrors.CodeInvalidInput, nil},
+		{"Negative window", "/v1/alerts/anchor/related?window=-1h", http.StatusBadRequest, apperrors.CodeInvalidInput, nil},
+		{"Invalid limit", "/v1/alerts/anchor/related?limit=0", http.StatusBadRequest, apperrors.CodeInvalidLimit, nil},
+		{"Invalid include_archived", "/v1/alerts/anchor/related?include_archived=maybe", http.StatusBadRequest, apperrors.CodeInvalidInput, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != "" {
+				var errResp ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+					t.Fatalf("Failed to decode error response: %v", err)
+				}
+				if errResp.Code != tt.expectedCode {
+					t.Errorf("Expected code %q, got %q", tt.expectedCode, errResp.Code)
+				}
+				return
+			}
+
+			var response struct {
+				Data  []models.Alert `json:"data"`
+				Count int            `json:"count"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			var gotIDs []string
+			for _, alert := range response.Data {
+				gotIDs = append(gotIDs, alert.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("Expected related %v, got %v", tt.wantIDs, gotIDs)
+			}
+			if response.Count != len(tt.wantIDs) {
+				t.Errorf("Expected count %d, got %d", len(tt.wantIDs), response.Count)
+			}
+		})
+	}
+}
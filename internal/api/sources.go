@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/pipeline"
+)
+
+// SourceLister describes the configured ingestion sources, typically
+// *pipeline.Pipeline
+type SourceLister interface {
+	Sources() []pipeline.SourceInfo
+}
+
+// WithSources lists the sources from l in GET /v1/sources. Without it only
+// the sources observed in the store are listed.
+func WithSources(l SourceLister) Option {
+	return func(h *Handler) {
+		h.sources = l
+	}
+}
+
+// sourcesHandler handles GET /sources, listing the configured sources in
+// data and the source names of stored alerts in observed. Either may name
+// sources the other lacks: observed keeps sources that were removed from
+// the configuration, and new sources appear once they have stored alerts.
+func (h *Handler) sourcesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	observed, err := h.store.DistinctSources(ctx)
+	if err != nil {
+		logger.WithContext(ctx).Error("Failed to list sources", "error", err)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+		return
+	}
+
+	configured := []pipeline.SourceInfo{}
+	if h.sources != nil {
+		configured = h.sources.Sources()
+	}
+
+	response := map[string]interface{}{
+		"data":      configured,
+		"count":     len(configured),
+		"observed":  observed,
+		"timestamp": time.Now().UTC(),
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
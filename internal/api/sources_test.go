@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/pipeline"
+)
+
+// mockSources implements SourceLister for testing
+type mockSources []pipeline.SourceInfo
+
+func (m mockSources) Sources() []pipeline.SourceInfo {
+	return m
+}
+
+func TestHandler_Sources(t *testing.T) {
+	store := NewMockStore()
+	err := store.UpsertAlerts(context.Background(), []models.Alert{
+		{ID: "alert-1", Source: "Ports"},
+		{ID: "alert-2", Source: "Retired Feed"},
+		{ID: "alert-3", Source: "Ports"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	configured := mockSources{
+		{Name: "Ports", Type: "rss", Interval: "5m0s"},
+		{Name: "Carriers", Type: "jsonfeed", Interval: "1m0s"},
+	}
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		wantConfigured []pipeline.SourceInfo
+	}{
+		{
+			name:           "configured and observed",
+			opts:           []Option{WithSources(configured)},
+			wantConfigured: configured,
+		},
+		{
+			name:           "observed only",
+			wantConfigured: []pipeline.SourceInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(store, "test-version", "test-build-time", "test-commit", tt.opts...)
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest("GET", "/v1/sources", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+
+			var response struct {
+				Data     []pipeline.SourceInfo `json:"data"`
+				Count    int                   `json:"count"`
+				Observed []string              `json:"observed"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			if !reflect.DeepEqual(response.Data, tt.wantConfigured) {
+				t.Errorf("Expected configured %v, got %v", tt.wantConfigured, response.Data)
+			}
+			if response.Count != len(tt.wantConfigured) {
+				t.Errorf("Expected count %d, got %d", len(tt.wantConfigured), response.Count)
+			}
+			if want := []string{"Ports", "Retired Feed"}; !reflect.DeepEqual(response.Observed, want) {
+				t.Errorf("Expected observed %v, got %v", want, response.Observed)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+)
+
+// WithTimeouts bounds read routes, such as alert queries and health checks,
+// by read and all other routes by request. Manual pipeline runs are exempt.
+// Zero leaves the routes unbounded.
+func WithTimeouts(read, request time.Duration) Option {
+	return func(h *Handler) {
+		h.readTimeout = read
+		h.requestTimeout = request
+	}
+}
+
+// timeout cancels a request's context after d. Handlers that give up
+// without responding get a 504; a non-positive d leaves requests unbounded.
+func (h *Handler) timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() == 0 && ctx.Err() == context.DeadlineExceeded {
+				h.writeErrorResponse(ww, r, http.StatusGatewayTimeout, apperrors.CodeTimeout, "Request timed out")
+			}
+		})
+	}
+}
+
+// WithQueryTimeout bounds each store query made to list, count or group
+// alerts, so a wide unbounded scan is cancelled in the database rather than
+// outliving the client. Zero leaves queries bounded only by the route timeout.
+func WithQueryTimeout(d time.Duration) Option {
+	return func(h *Handler) {
+		h.queryTimeout = d
+	}
+}
+
+// queryContext derives the context for one alert listing, count or
+// aggregate query
+func (h *Handler) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, h.queryTimeout)
+}
+
+// writeQueryError reports a failed query made with ctx from queryContext,
+// answering 504 if it ran out of time
+func (h *Handler) writeQueryError(w http.ResponseWriter, r *http.Request, ctx context.Context, err error, msg string) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logger.WithContext(ctx).Warn(msg, "error", err, "timeout", h.queryTimeout)
+		h.writeErrorResponse(w, r, http.StatusGatewayTimeout, apperrors.CodeTimeout, "Query timed out")
+		return
+	}
+	logger.WithContext(ctx).Error(msg, "error", err)
+	h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Internal server error")
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+)
+
+// slowStore is a MockStore whose queries take delay unless ctx ends first
+type slowStore struct {
+	*MockStore
+	delay time.Duration
+}
+
+func (s *slowStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error) {
+	select {
+	case <-time.After(s.delay):
+		return s.MockStore.QueryAlerts(ctx, q)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// slowRunner is a mockRunner whose runs take delay
+type slowRunner struct {
+	mockRunner
+	delay time.Duration
+}
+
+func (m *slowRunner) RunSourceNow(ctx context.Context, name string) (int, error) {
+	select {
+	case <-time.After(m.delay):
+		return m.mockRunner.RunSourceNow(ctx, name)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// blockingDB is a store.Database whose queries stall until their context
+// ends, as pgx does when a query is cancelled. Multi-row queries return one
+// row first, so a cancellation mid-read can be told from a short result.
+type blockingDB struct {
+	canceled chan error
+}
+
+func (d *blockingDB) block(ctx context.Context) error {
+	<-ctx.Done()
+	d.canceled <- ctx.Err()
+	return ctx.Err()
+}
+
+func (d *blockingDB) Exec(ctx context.Context, sql string, args ...any) error { return d.block(ctx) }
+func (d *blockingDB) Query(ctx context.Context, sql string, args ...any) (interface{}, error) {
+	return &stallingRows{db: d, ctx: ctx}, nil
+}
+func (d *blockingDB) QueryRow(ctx context.Context, sql string, args ...any) interface{} {
+	return stallingRow{db: d, ctx: ctx}
+}
+func (d *blockingDB) QueryReplica(ctx context.Context, sql string, args ...any) (interface{}, error) {
+	return d.Query(ctx, sql, args...)
+}
+func (d *blockingDB) QueryRowReplica(ctx context.Context, sql string, args ...any) interface{} {
+	return d.QueryRow(ctx, sql, args...)
+}
+func (d *blockingDB) Health(ctx context.Context) error { return nil }
+func (d *blockingDB) IsConfigured() bool               { return true }
+
+// stallingRows is a pgx.Rows with one row, after which reading stalls
+// until the query is cancelled
+type stallingRows struct {
+	pgx.Rows
+	db   *blockingDB
+	ctx  context.Context
+	read int
+	err  error
+}
+
+func (r *stallingRows) Next() bool {
+	r.read++
+	if r.read == 1 {
+		return true
+	}
+	r.err = r.db.block(r.ctx)
+	return false
+}
+func (r *stallingRows) Scan(dest ...any) error { return nil }
+func (r *stallingRows) Err() error             { return r.err }
+func (r *stallingRows) Close()                 {}
+
+// stallingRow is a pgx.Row whose Scan stalls until the query is cancelled
+type stallingRow struct {
+	db  *blockingDB
+	ctx context.Context
+}
+
+func (r stallingRow) Scan(dest ...any) error { return r.db.block(r.ctx) }
+
+func TestHandler_QueryTimeout(t *testing.T) {
+	logger.Init("error", "text")
+
+	db := &blockingDB{canceled: make(chan error, 1)}
+	handler := NewHandler(store.NewPostgresStore(db), "test-version", "test-build-time", "test-commit",
+		WithTimeouts(time.Minute, time.Minute),
+		WithQueryTimeout(20*time.Millisecond),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	targets := []string{"/v1/alerts", "/v1/alerts.csv", "/v1/alerts/count", "/v1/alerts/stats?group_by=severity"}
+	for _, target := range targets {
+		t.Run(target, func(t *testing.T) {
+			req := httptest.NewRequest("GET", target, nil)
+			w := httptest.NewRecorder()
+
+			start := time.Now()
+			r.ServeHTTP(w, req)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected the query deadline to end the request, took %s", elapsed)
+			}
+
+			select {
+			case err := <-db.canceled:
+				if err != context.DeadlineExceeded {
+					t.Errorf("Expected the database query to be cancelled by its deadline, got %v", err)
+				}
+			default:
+				t.Fatal("Expected the database to see the query cancelled")
+			}
+
+			if w.Code != http.StatusGatewayTimeout {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusGatewayTimeout, w.Code, w.Body.String())
+			}
+			var errResp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errResp.Code != apperrors.CodeTimeout {
+				t.Errorf("Expected code %q, got %q", apperrors.CodeTimeout, errResp.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_RouteTimeouts(t *testing.T) {
+	logger.Init("error", "text")
+
+	store := &slowStore{MockStore: NewMockStore(), delay: 100 * time.Millisecond}
+	runner := &slowRunner{mockRunner: mockRunner{counts: map[string]int{"Ports": 3}}, delay: 100 * time.Millisecond}
+	handler := NewHandler(store, "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", runner),
+		WithTimeouts(20*time.Millisecond, 20*time.Millisecond),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{"Slow alert read times out", "GET", "/v1/alerts", "", http.StatusGatewayTimeout},
+		{"Fast read is unaffected", "GET", "/v1/version", "", http.StatusOK},
+		{"Manual pipeline run is exempt", "POST", "/v1/admin/pipeline/run", `{"source":"Ports"}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusGatewayTimeout {
+				return
+			}
+			var errResp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errResp.Code != apperrors.CodeTimeout {
+				t.Errorf("Expected code %q, got %q", apperrors.CodeTimeout, errResp.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_TimeoutWithoutResponse(t *testing.T) {
+	logger.Init("error", "text")
+
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit")
+	slow := handler.timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	w := httptest.NewRecorder()
+	slow.ServeHTTP(w, httptest.NewRequest("GET", "/v1/alerts", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
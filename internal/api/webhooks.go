@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+	"github.com/rajasatyajit/SupplyChain/internal/webhook"
+)
+
+// WithWebhooks enables the /v1/admin/webhooks endpoints backed by ws.
+// Like the other admin endpoints they also require WithAdmin.
+func WithWebhooks(ws store.WebhookStore) Option {
+	return func(h *Handler) {
+		h.webhooks = ws
+	}
+}
+
+// createWebhookRequest is the body of POST /v1/admin/webhooks
+type createWebhookRequest struct {
+	URL    string               `json:"url"`
+	Secret string               `json:"secret"`
+	Filter models.WebhookFilter `json:"filter"`
+}
+
+// createWebhookHandler registers an outbound webhook. The signing secret is
+// generated unless given, and is only returned in this response.
+func (h *Handler) createWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var req createWebhookRequest
+	if !h.decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, apperrors.CodeInvalidInput, "url must be an absolute http or https URL")
+		return
+	}
+
+	hook := models.Webhook{URL: req.URL, Secret: req.Secret, Filter: req.Filter}
+	if hook.Secret == "" {
+		if hook.Secret, err = webhook.NewSecret(); err != nil {
+			logger.Error("Failed to generate webhook secret", "error", err)
+			h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to create webhook")
+			return
+		}
+	}
+
+	if err := h.webhooks.CreateWebhook(r.Context(), &hook); err != nil {
+		logger.Error("Failed to create webhook", "error", err)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to create webhook")
+		return
+	}
+
+	logger.Info("Webhook created", "webhook_id", hook.ID, "url", logger.RedactURL(hook.URL))
+	h.writeJSONResponse(w, http.StatusCreated, hook)
+}
+
+// listWebhooksHandler lists outbound webhooks without their secrets
+func (h *Handler) listWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.webhooks.ListWebhooks(r.Context())
+	if err != nil {
+		logger.Error("Failed to list webhooks", "error", err)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to list webhooks")
+		return
+	}
+
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	if hooks == nil {
+		hooks = []models.Webhook{}
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"data":  hooks,
+		"count": len(hooks),
+	})
+}
+
+// deleteWebhookHandler removes an outbound webhook
+func (h *Handler) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.webhooks.DeleteWebhook(r.Context(), id)
+	if errors.Is(err, store.ErrWebhookNotFound) {
+		h.writeErrorResponse(w, r, http.StatusNotFound, apperrors.CodeWebhookNotFound, "Webhook not found")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to delete webhook", "webhook_id", id, "error", err)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to delete webhook")
+		return
+	}
+
+	logger.Info("Webhook deleted", "webhook_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+)
+
+func newWebhookRouter() *chi.Mux {
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", &mockRunner{}),
+		WithWebhooks(store.NewInMemoryWebhookStore()),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func adminRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	return req
+}
+
+func TestHandler_Webhooks(t *testing.T) {
+	logger.Init("error", "text")
+	r := newWebhookRouter()
+
+	// Create with a generated secret
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, adminRequest("POST", "/v1/admin/webhooks", `{"url":"https://example.com/hook","filter":{"severities":["high"]}}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", w.Code)
+	}
+	var created models.Webhook
+	json.NewDecoder(w.Body).Decode(&created)
+	if created.ID == "" || len(created.Secret) != 64 || created.Filter.Severities[0] != "high" {
+		t.Errorf("Unexpected created webhook: %+v", created)
+	}
+
+	// List hides secrets
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, adminRequest("GET", "/v1/admin/webhooks", ""))
+	var list struct {
+		Data  []models.Webhook `json:"data"`
+		Count int              `json:"count"`
+	}
+	json.NewDecoder(w.Body).Decode(&list)
+	if list.Count != 1 || list.Data[0].ID != created.ID || list.Data[0].Secret != "" {
+		t.Errorf("Unexpected webhook list: %+v", list)
+	}
+
+	// Delete, then delete again
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, adminRequest("DELETE", "/v1/admin/webhooks/"+created.ID, ""))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, adminRequest("DELETE", "/v1/admin/webhooks/"+created.ID, ""))
+	var response ErrorResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	if w.Code != http.StatusNotFound || response.Code != apperrors.CodeWebhookNotFound {
+		t.Errorf("Expected 404 webhook_not_found, got %d %s", w.Code, response.Code)
+	}
+}
+
+func TestHandler_CreateWebhookValidation(t *testing.T) {
+	r := newWebhookRouter()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"Invalid JSON", `{`},
+		{"Missing URL", `{}`},
+		{"Relative URL", `{"url":"/hook"}`},
+		{"Unsupported scheme", `{"url":"ftp://example.com/hook"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, adminRequest("POST", "/v1/admin/webhooks", tt.body))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+		})
+	}
+
+	// Admin token is required
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/admin/webhooks", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
+
+func TestHandler_JSONBodyLimits(t *testing.T) {
+	logger.Init("error", "text")
+
+	handler := NewHandler(NewMockStore(), "test-version", "test-build-time", "test-commit",
+		WithAdmin("secret", &mockRunner{counts: map[string]int{"Ports": 1}}),
+		WithWebhooks(store.NewInMemoryWebhookStore()),
+		WithMaxBodyBytes(256),
+	)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		target         string
+		body           string
+		expectedStatus int
+		expectedCode   apperrors.Code
+	}{
+		{"Oversized webhook body", "/v1/admin/webhooks", `{"url":"https://example.com/` + strings.Repeat("a", 512) + `"}`, http.StatusRequestEntityTooLarge, apperrors.CodePayloadTooLarge},
+		{"Oversized run body", "/v1/admin/pipeline/run", `{"source":"` + strings.Repeat("a", 512) + `"}`, http.StatusRequestEntityTooLarge, apperrors.CodePayloadTooLarge},
+		{"Unknown webhook field", "/v1/admin/webhooks", `{"url":"https://example.com/hook","filters":{"severities":["high"]}}`, http.StatusBadRequest, apperrors.CodeInvalidInput},
+		{"Unknown run field", "/v1/admin/pipeline/run", `{"sources":"Ports"}`, http.StatusBadRequest, apperrors.CodeInvalidInput},
+		{"Trailing data", "/v1/admin/pipeline/run", `{"source":"Ports"} {"source":"Ports"}`, http.StatusBadRequest, apperrors.CodeInvalidInput},
+		{"Within limits", "/v1/admin/pipeline/run", `{"source":"Ports"}`, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, adminRequest("POST", tt.target, tt.body))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedCode != "" {
+				var response ErrorResponse
+				json.NewDecoder(w.Body).Decode(&response)
+				if response.Code != tt.expectedCode {
+					t.Errorf("Expected code %s, got %s", tt.expectedCode, response.Code)
+				}
+			}
+		})
+	}
+}
@@ -1,81 +1,250 @@
 package classifier
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 	"strings"
 
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 	"github.com/rajasatyajit/SupplyChain/pkg/utils"
 )
 
-// Classifier provides alert classification functionality
-type Classifier struct{}
+// Keyword categories and the labels they assign, in match precedence order
+const (
+	CategorySeverity  = "severity"
+	CategorySentiment = "sentiment"
+)
 
-// New creates a new classifier instance
-func New() *Classifier {
-	return &Classifier{}
+var categoryLabels = map[string][]string{
+	CategorySeverity:  {"high", "medium"},
+	CategorySentiment: {"negative", "positive"},
 }
 
-// Classify analyzes and classifies an alert
-func (c *Classifier) Classify(alert *models.Alert) {
-	text := strings.ToLower(alert.Title + " " + alert.Summary)
+// KeywordSets maps a category (severity, sentiment) to labels and the
+// keywords that assign them, e.g. {"severity": {"high": ["strike"]}}
+type KeywordSets map[string]map[string][]string
+
+// DefaultKeywordSets returns the built-in keyword sets
+func DefaultKeywordSets() KeywordSets {
+	return KeywordSets{
+		CategorySeverity: {
+			"high": {
+				"strike", "shutdown", "closure", "blocked", "riot",
+				"earthquake", "hurricane", "emergency", "critical",
+				"severe", "major", "catastrophic", "disaster",
+			},
+			"medium": {
+				"delay", "congestion", "backlog", "maintenance",
+				"disruption", "issue", "problem", "warning",
+				"moderate", "minor",
+			},
+		},
+		CategorySentiment: {
+			"negative": {
+				"disrupt", "risk", "shortage", "warning", "danger",
+				"threat", "crisis", "failure", "damage", "loss",
+				"concern", "worry", "fear", "panic",
+			},
+			"positive": {
+				"resolved", "fixed", "restored", "improved",
+				"success", "recovery", "solution", "progress",
+			},
+		},
+	}
+}
 
-	// Classify severity
-	alert.Severity = c.classifySeverity(text)
+// defaultLanguageKeywordSets returns the built-in keyword sets by language
+func defaultLanguageKeywordSets() map[string]KeywordSets {
+	return map[string]KeywordSets{
+		"en": DefaultKeywordSets(),
+		"es": {
+			CategorySeverity: {
+				"high": {
+					"huelga", "paro", "cierre", "cerrado", "bloqueo", "bloqueado",
+					"emergencia", "crítico", "grave", "terremoto", "huracán",
+					"catástrofe", "desastre",
+				},
+				"medium": {
+					"retraso", "demora", "congestión", "atasco", "mantenimiento",
+					"interrupción", "problema", "aviso", "alerta", "moderado",
+				},
+			},
+			CategorySentiment: {
+				"negative": {
+					"riesgo", "escasez", "peligro", "amenaza", "crisis",
+					"fallo", "daño", "pérdida", "preocupación",
+				},
+				"positive": {
+					"resuelto", "restablecido", "reanuda", "mejora",
+					"recuperación", "solución", "éxito", "normalidad",
+				},
+			},
+		},
+		"de": {
+			CategorySeverity: {
+				"high": {
+					"streik", "schließung", "gesperrt", "sperrung", "blockiert",
+					"notfall", "kritisch", "erdbeben", "orkan", "katastrophe",
+					"stillstand",
+				},
+				"medium": {
+					"verspätung", "verzögerung", "stau", "rückstau", "wartung",
+					"störung", "problem", "warnung", "behinderung",
+				},
+			},
+			CategorySentiment: {
+				"negative": {
+					"risiko", "engpass", "mangel", "gefahr", "bedrohung",
+					"krise", "ausfall", "schaden", "verlust", "sorge",
+				},
+				"positive": {
+					"behoben", "wiederhergestellt", "verbessert", "erholung",
+					"lösung", "erfolg", "fortschritt", "normalbetrieb",
+				},
+			},
+		},
+	}
+}
 
-	// Classify sentiment
-	alert.Sentiment = c.classifySentiment(text)
+// LoadKeywordSets reads keyword sets from a JSON file
+func LoadKeywordSets(path string) (KeywordSets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
 
-	// Set initial confidence
-	if alert.Confidence == 0 {
-		alert.Confidence = 0.8 // Default confidence
+	var sets KeywordSets
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, fmt.Errorf("parse keyword sets: %w", err)
 	}
-}
 
-// classifySeverity determines the severity level of an alert
-func (c *Classifier) classifySeverity(text string) string {
-	text = strings.ToLower(text)
+	if err := sets.Validate(); err != nil {
+		return nil, err
+	}
+
+	return sets, nil
+}
 
-	highSeverityKeywords := []string{
-		"strike", "shutdown", "closure", "blocked", "riot",
-		"earthquake", "hurricane", "emergency", "critical",
-		"severe", "major", "catastrophic", "disaster",
+// Validate rejects unknown categories and labels
+func (k KeywordSets) Validate() error {
+	for category, labels := range k {
+		known, ok := categoryLabels[category]
+		if !ok {
+			return fmt.Errorf("unknown keyword category: %s", category)
+		}
+		for label := range labels {
+			if !isKnownLabel(known, label) {
+				return fmt.Errorf("unknown %s label: %s", category, label)
+			}
+		}
 	}
+	return nil
+}
 
-	mediumSeverityKeywords := []string{
-		"delay", "congestion", "backlog", "maintenance",
-		"disruption", "issue", "problem", "warning",
-		"moderate", "minor",
+func isKnownLabel(known []string, label string) bool {
+	for _, k := range known {
+		if k == label {
+			return true
+		}
 	}
+	return false
+}
+
+// Severity and confidence assigned when the detected language has no keyword set
+const (
+	SeverityUnknown             = "unknown"
+	unknownLanguageConfidence   = 0.3
+	defaultClassifiedConfidence = 0.8
+)
 
-	if utils.ContainsAny(text, highSeverityKeywords) {
-		return "high"
-	} else if utils.ContainsAny(text, mediumSeverityKeywords) {
-		return "medium"
+// Classifier provides alert classification functionality
+type Classifier struct {
+	keywords map[string]KeywordSets // by language
+}
+
+// New creates a new classifier instance using the built-in keyword sets
+func New() *Classifier {
+	return NewWithConfig(nil)
+}
+
+// NewWithConfig creates a classifier whose keyword lists override the
+// built-in English defaults label by label; labels not present keep their defaults
+func NewWithConfig(sets KeywordSets) *Classifier {
+	languages := defaultLanguageKeywordSets()
+	keywords := languages[DefaultLanguage]
+	for category, labels := range sets {
+		if keywords[category] == nil {
+			keywords[category] = make(map[string][]string)
+		}
+		for label, words := range labels {
+			lowered := make([]string, len(words))
+			for i, word := range words {
+				lowered[i] = strings.ToLower(word)
+			}
+			keywords[category][label] = lowered
+		}
 	}
 
-	return "low"
+	return &Classifier{keywords: languages}
 }
 
-// classifySentiment determines the sentiment of an alert
-func (c *Classifier) classifySentiment(text string) string {
-	text = strings.ToLower(text)
+// Classify analyzes and classifies an alert. Classification is local, so
+// ctx is only checked before starting.
+func (c *Classifier) Classify(ctx context.Context, alert *models.Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	text := strings.ToLower(alert.Title + " " + alert.Summary)
 
-	negativeKeywords := []string{
-		"disrupt", "risk", "shortage", "warning", "danger",
-		"threat", "crisis", "failure", "damage", "loss",
-		"concern", "worry", "fear", "panic",
+	// Detect language
+	alert.Language = DetectLanguage(text)
+
+	// Without keywords for the language, don't guess a severity
+	if _, ok := c.keywords[alert.Language]; !ok {
+		alert.Severity = SeverityUnknown
+		alert.Sentiment = "neutral"
+		if alert.Confidence == 0 {
+			alert.Confidence = unknownLanguageConfidence
+		}
+		return nil
 	}
 
-	positiveKeywords := []string{
-		"resolved", "fixed", "restored", "improved",
-		"success", "recovery", "solution", "progress",
+	// Classify severity
+	alert.Severity = c.classifySeverity(text, alert.Language)
+
+	// Classify sentiment
+	alert.Sentiment = c.classifySentiment(text, alert.Language)
+
+	// Set initial confidence
+	if alert.Confidence == 0 {
+		alert.Confidence = defaultClassifiedConfidence
 	}
+	return nil
+}
+
+// classifySeverity determines the severity level of an alert
+func (c *Classifier) classifySeverity(text, language string) string {
+	return c.match(language, CategorySeverity, text, "low")
+}
+
+// classifySentiment determines the sentiment of an alert
+func (c *Classifier) classifySentiment(text, language string) string {
+	return c.match(language, CategorySentiment, text, "neutral")
+}
+
+// match returns the first label in precedence order whose keywords for
+// language appear in text, or fallback when none do
+func (c *Classifier) match(language, category, text, fallback string) string {
+	text = strings.ToLower(text)
 
-	if utils.ContainsAny(text, negativeKeywords) {
-		return "negative"
-	} else if utils.ContainsAny(text, positiveKeywords) {
-		return "positive"
+	for _, label := range categoryLabels[category] {
+		if utils.ContainsAny(text, c.keywords[language][category][label]) {
+			return label
+		}
 	}
 
-	return "neutral"
+	return fallback
 }
@@ -1,6 +1,10 @@
 package classifier
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rajasatyajit/SupplyChain/internal/models"
@@ -64,7 +68,7 @@ func TestClassifier_Classify(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			classifier.Classify(&tt.alert)
+			classifier.Classify(context.Background(), &tt.alert)
 
 			if tt.alert.Severity != tt.expectedSeverity {
 				t.Errorf("Expected severity %s, got %s", tt.expectedSeverity, tt.alert.Severity)
@@ -113,7 +117,7 @@ func TestClassifier_ClassifySeverity(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := classifier.classifySeverity(tt.text)
+			result := classifier.classifySeverity(tt.text, "en")
 			if result != tt.expected {
 				t.Errorf("Expected %s, got %s", tt.expected, result)
 			}
@@ -153,10 +157,162 @@ func TestClassifier_ClassifySentiment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := classifier.classifySentiment(tt.text)
+			result := classifier.classifySentiment(tt.text, "en")
 			if result != tt.expected {
 				t.Errorf("Expected %s, got %s", tt.expected, result)
 			}
 		})
 	}
 }
+
+func TestNewWithConfig_OverridesSeverity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keywords.json")
+	data := `{"severity": {"high": ["Sanctions", "embargo"]}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write keyword file: %v", err)
+	}
+
+	sets, err := LoadKeywordSets(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	classifier := NewWithConfig(sets)
+
+	alert := models.Alert{Title: "New sanctions announced on chip exports"}
+	classifier.Classify(context.Background(), &alert)
+	if alert.Severity != "high" {
+		t.Errorf("Expected overridden severity high, got %s", alert.Severity)
+	}
+
+	// Overriding "high" replaces its defaults
+	alert = models.Alert{Title: "Port strike continues"}
+	classifier.Classify(context.Background(), &alert)
+	if alert.Severity != "low" {
+		t.Errorf("Expected replaced high keywords to no longer match, got %s", alert.Severity)
+	}
+
+	// Labels not overridden keep their defaults
+	alert = models.Alert{Title: "Congestion at terminal", Summary: "Operations restored"}
+	classifier.Classify(context.Background(), &alert)
+	if alert.Severity != "medium" || alert.Sentiment != "positive" {
+		t.Errorf("Expected default medium/positive, got %s/%s", alert.Severity, alert.Sentiment)
+	}
+}
+
+func TestLoadKeywordSets_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Malformed JSON", data: `{"severity":`},
+		{name: "Unknown category", data: `{"urgency": {"high": ["now"]}}`},
+		{name: "Unknown label", data: `{"severity": {"extreme": ["meteor"]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "keywords.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatalf("Failed to write keyword file: %v", err)
+			}
+			if _, err := LoadKeywordSets(path); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := LoadKeywordSets(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+}
+
+func TestClassifier_ClassifyLanguages(t *testing.T) {
+	classifier := New()
+
+	tests := []struct {
+		name               string
+		alert              models.Alert
+		expectedLanguage   string
+		expectedSeverity   string
+		expectedSentiment  string
+		expectedConfidence float64
+	}{
+		{
+			name: "Spanish strike",
+			alert: models.Alert{
+				Title:   "Huelga en el puerto de Valencia",
+				Summary: "Los estibadores anuncian un paro con riesgo para las exportaciones",
+			},
+			expectedLanguage:   "es",
+			expectedSeverity:   "high",
+			expectedSentiment:  "negative",
+			expectedConfidence: 0.8,
+		},
+		{
+			name: "German congestion",
+			alert: models.Alert{
+				Title:   "Stau und Verspätung im Hamburger Hafen",
+				Summary: "Die Störung ist behoben",
+			},
+			expectedLanguage:   "de",
+			expectedSeverity:   "medium",
+			expectedSentiment:  "positive",
+			expectedConfidence: 0.8,
+		},
+		{
+			name: "English without stopwords",
+			alert: models.Alert{
+				Title: "Port Strike Disrupts West Coast Operations",
+			},
+			expectedLanguage:   "en",
+			expectedSeverity:   "high",
+			expectedSentiment:  "negative",
+			expectedConfidence: 0.8,
+		},
+		{
+			name: "French has no keyword set",
+			alert: models.Alert{
+				Title:   "Grève dans le port du Havre",
+				Summary: "Les dockers bloquent les terminaux pour la journée",
+			},
+			expectedLanguage:   "fr",
+			expectedSeverity:   SeverityUnknown,
+			expectedSentiment:  "neutral",
+			expectedConfidence: 0.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classifier.Classify(context.Background(), &tt.alert)
+
+			if tt.alert.Language != tt.expectedLanguage {
+				t.Errorf("Expected language %s, got %s", tt.expectedLanguage, tt.alert.Language)
+			}
+			if tt.alert.Severity != tt.expectedSeverity {
+				t.Errorf("Expected severity %s, got %s", tt.expectedSeverity, tt.alert.Severity)
+			}
+			if tt.alert.Sentiment != tt.expectedSentiment {
+				t.Errorf("Expected sentiment %s, got %s", tt.expectedSentiment, tt.alert.Sentiment)
+			}
+			if tt.alert.Confidence != tt.expectedConfidence {
+				t.Errorf("Expected confidence %v, got %v", tt.expectedConfidence, tt.alert.Confidence)
+			}
+		})
+	}
+}
+
+func TestClassifier_ClassifyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	alert := models.Alert{Title: "Port strike continues"}
+	if err := New().Classify(ctx, &alert); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if alert.Severity != "" {
+		t.Errorf("Expected a cancelled classification to leave the alert alone, got severity %q", alert.Severity)
+	}
+}
@@ -0,0 +1,55 @@
+package classifier
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultLanguage is assumed when text carries no language signal,
+// e.g. short headlines without stopwords
+const DefaultLanguage = "en"
+
+// stopwords are frequent function words used to guess a text's language.
+// Languages are listed in tie-break order.
+var stopwords = []struct {
+	language string
+	words    map[string]bool
+}{
+	{"en", wordSet("the", "and", "of", "to", "in", "is", "for", "on", "with", "at", "from", "by", "are", "has", "after")},
+	{"es", wordSet("el", "la", "los", "las", "de", "del", "y", "en", "por", "para", "con", "que", "una", "se", "al")},
+	{"de", wordSet("der", "die", "das", "und", "den", "dem", "von", "mit", "im", "ist", "auf", "für", "nicht", "ein", "eine", "zu", "des", "wegen")},
+	{"fr", wordSet("le", "la", "les", "des", "et", "du", "une", "est", "dans", "pour", "sur", "au", "avec")},
+	{"pt", wordSet("o", "os", "as", "do", "da", "dos", "das", "em", "para", "com", "uma", "não", "ao")},
+	{"it", wordSet("il", "lo", "gli", "della", "di", "che", "è", "per", "con", "una", "nel", "sono")},
+}
+
+func wordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// DetectLanguage guesses the ISO 639-1 language of text by counting
+// stopwords, returning DefaultLanguage when no stopwords are found
+func DetectLanguage(text string) string {
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	best, bestScore := DefaultLanguage, 0
+	for _, sw := range stopwords {
+		score := 0
+		for _, token := range tokens {
+			if sw.words[token] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = sw.language, score
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,27 @@
+package classifier
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "English", text: "Congestion at the Port of Rotterdam is easing", expected: "en"},
+		{name: "Spanish", text: "Retrasos en el puerto de Algeciras por la niebla", expected: "es"},
+		{name: "German", text: "Die Schleuse ist wegen Wartung für den Verkehr gesperrt", expected: "de"},
+		{name: "French", text: "Les dockers en grève dans le port de Marseille", expected: "fr"},
+		{name: "Italian", text: "Sciopero nel porto di Genova per il rinnovo del contratto", expected: "it"},
+		{name: "No stopwords defaults to English", text: "Suez Canal Blocked", expected: DefaultLanguage},
+		{name: "Empty text", text: "", expected: DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
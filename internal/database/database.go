@@ -4,29 +4,80 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rajasatyajit/SupplyChain/config"
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
 	"github.com/rajasatyajit/SupplyChain/internal/metrics"
+	"github.com/rajasatyajit/SupplyChain/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DB represents a database connection
 type DB struct {
 	pool *pgxpool.Pool
 	cfg  config.DatabaseConfig
+	conn querier
+
+	// replicaPool serves QueryReplica and QueryRowReplica when
+	// DATABASE_READ_URL is set
+	replicaPool *pgxpool.Pool
+	replica     querier
+}
+
+// querier is the subset of *pgxpool.Pool used for statements, so tests can
+// substitute a fake
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
-// New creates a new database connection
+// New creates a new database connection, plus a read replica connection
+// when cfg.ReadURL is set
 func New(ctx context.Context, cfg config.DatabaseConfig) (*DB, error) {
 	if cfg.URL == "" {
 		logger.Info("DATABASE_URL not set; using in-memory store only")
 		return &DB{pool: nil, cfg: cfg}, nil
 	}
 
-	poolCfg, err := pgxpool.ParseConfig(cfg.URL)
+	pool, err := connect(ctx, cfg.URL, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{pool: pool, cfg: cfg, conn: pool}
+
+	if cfg.ReadURL != "" {
+		replicaPool, err := connect(ctx, cfg.ReadURL, cfg)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+		db.replicaPool = replicaPool
+		db.replica = replicaPool
+	}
+
+	// Start metrics collection
+	go db.collectMetrics(ctx)
+
+	logger.Info("Database connection established",
+		"max_conns", cfg.MaxConns,
+		"min_conns", cfg.MinConns,
+		"read_replica", db.replicaPool != nil,
+	)
+
+	return db, nil
+}
+
+// connect opens and pings a connection pool for url
+func connect(ctx context.Context, url string, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(url)
 	if err != nil {
 		return nil, fmt.Errorf("parse db url: %w", err)
 	}
@@ -57,21 +108,14 @@ func New(ctx context.Context, cfg config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("ping db: %w", err)
 	}
 
-	db := &DB{pool: pool, cfg: cfg}
-
-	// Start metrics collection
-	go db.collectMetrics(ctx)
-
-	logger.Info("Database connection established",
-		"max_conns", cfg.MaxConns,
-		"min_conns", cfg.MinConns,
-	)
-
-	return db, nil
+	return pool, nil
 }
 
 // Close closes the database connection
 func (d *DB) Close(ctx context.Context) {
+	if d.replicaPool != nil {
+		d.replicaPool.Close()
+	}
 	if d.pool != nil {
 		d.pool.Close()
 		logger.Info("Database connection closed")
@@ -98,12 +142,16 @@ func (d *DB) collectMetrics(ctx context.Context) {
 	}
 }
 
-// Exec executes a statement
-func (d *DB) Exec(ctx context.Context, sql string, args ...any) error {
-	if d.pool == nil {
+// Exec executes a statement, retrying failures only where isSafeToRetry
+// shows it didn't run
+func (d *DB) Exec(ctx context.Context, sql string, args ...any) (err error) {
+	if d.conn == nil {
 		return nil
 	}
 
+	ctx, span := startSpan(ctx, "db.exec", sql)
+	defer func() { tracing.End(span, err) }()
+
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
@@ -113,10 +161,13 @@ func (d *DB) Exec(ctx context.Context, sql string, args ...any) error {
 		)
 	}()
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+	err = d.withRetry(ctx, isSafeToRetry, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
 
-	_, err := d.pool.Exec(ctx, sql, args...)
+		_, err := d.conn.Exec(ctx, sql, args...)
+		return err
+	})
 
 	status := "success"
 	if err != nil {
@@ -128,46 +179,207 @@ func (d *DB) Exec(ctx context.Context, sql string, args ...any) error {
 	return err
 }
 
-// Query executes a query and returns rows
+// Query executes a query on the primary and returns rows, retrying
+// failures to start the query as retryPolicy allows. Errors while reading
+// rows are not retried.
 func (d *DB) Query(ctx context.Context, sql string, args ...any) (interface{}, error) {
-	if d.pool == nil {
+	return d.query(ctx, d.conn, "query", sql, args...)
+}
+
+// QueryReplica is Query against the read replica, or the primary when no
+// replica is configured. Results may lag recent writes.
+func (d *DB) QueryReplica(ctx context.Context, sql string, args ...any) (interface{}, error) {
+	return d.query(ctx, d.reader(), "replica_query", sql, args...)
+}
+
+// QueryRow executes a query on the primary that returns a single row. The
+// query runs when the row is scanned, and failures are retried then as
+// retryPolicy allows.
+func (d *DB) QueryRow(ctx context.Context, sql string, args ...any) interface{} {
+	if d.conn == nil {
+		return nil
+	}
+
+	return &retryRow{db: d, conn: d.conn, ctx: ctx, sql: sql, args: args}
+}
+
+// QueryRowReplica is QueryRow against the read replica, or the primary when
+// no replica is configured
+func (d *DB) QueryRowReplica(ctx context.Context, sql string, args ...any) interface{} {
+	conn := d.reader()
+	if conn == nil {
+		return nil
+	}
+
+	return &retryRow{db: d, conn: conn, ctx: ctx, sql: sql, args: args}
+}
+
+// reader returns the connection reads should use
+func (d *DB) reader() querier {
+	if d.replica != nil {
+		return d.replica
+	}
+	return d.conn
+}
+
+// query runs sql on conn, recording it under operation in metrics
+func (d *DB) query(ctx context.Context, conn querier, operation, sql string, args ...any) (_ interface{}, err error) {
+	if conn == nil {
 		return nil, errors.New("db not configured")
 	}
 
+	ctx, span := startSpan(ctx, "db."+operation, sql)
+	defer func() { tracing.End(span, err) }()
+
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
 		logger.Debug("Database query",
 			"sql", sql,
+			"operation", operation,
 			"duration_ms", duration.Milliseconds(),
 		)
 	}()
 
+	// The timeout must outlive this call, as pgx reads rows under ctx; it
+	// is cancelled when the caller closes the rows
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
 
-	rows, err := d.pool.Query(ctx, sql, args...)
+	var rows pgx.Rows
+	err = d.withRetry(ctx, retryPolicy(sql), func(ctx context.Context) error {
+		var err error
+		rows, err = conn.Query(ctx, sql, args...)
+		return err
+	})
 
 	status := "success"
 	if err != nil {
 		status = "error"
 		logger.Error("Database query failed", "error", err, "sql", sql)
 	}
-	metrics.RecordDBQuery("query", status)
+	metrics.RecordDBQuery(operation, status)
 
-	return rows, err
+	if err != nil || rows == nil {
+		cancel()
+		return rows, err
+	}
+	return &cancelRows{Rows: rows, cancel: cancel}, nil
 }
 
-// QueryRow executes a query that returns a single row
-func (d *DB) QueryRow(ctx context.Context, sql string, args ...any) interface{} {
+// cancelRows releases a query's context once its rows are closed
+type cancelRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+// Close closes the rows, then cancels the query's context
+func (r *cancelRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// retryRow is a pgx.Row that runs its query on Scan, retrying failures as
+// retryPolicy allows
+type retryRow struct {
+	db   *DB
+	conn querier
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+// Scan runs the query and scans the first row into dest
+func (r *retryRow) Scan(dest ...any) (err error) {
+	ctx, span := startSpan(r.ctx, "db.query_row", r.sql)
+	defer func() {
+		// No rows is an answer, not a failure
+		if errors.Is(err, pgx.ErrNoRows) {
+			span.End()
+			return
+		}
+		tracing.End(span, err)
+	}()
+
+	return r.db.withRetry(ctx, retryPolicy(r.sql), func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		return r.conn.QueryRow(ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
+
+// startSpan starts a client span for a statement, named after the DB
+// method running it. Only the statement's leading keyword is recorded, as
+// bulk statements can be very long.
+func startSpan(ctx context.Context, name, sql string) (context.Context, trace.Span) {
+	return tracing.Tracer(nil).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", sqlOperation(sql)),
+		),
+	)
+}
+
+// sqlOperation returns the leading keyword of a statement, e.g. SELECT
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// Tx is a database transaction
+type Tx = pgx.Tx
+
+// Begin starts a transaction; callers must Commit or Rollback it
+func (d *DB) Begin(ctx context.Context) (Tx, error) {
 	if d.pool == nil {
-		return nil
+		return nil, errors.New("db not configured")
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	return tx, nil
+}
+
+// WithTx runs fn in a transaction, committing if fn succeeds and rolling
+// back if it returns an error or panics
+func (d *DB) WithTx(ctx context.Context, fn func(tx Tx) error) (err error) {
+	tx, err := d.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.RecordDBQuery("tx", status)
+	}()
 
-	return d.pool.QueryRow(ctx, sql, args...)
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			logger.Error("Database rollback failed", "error", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
 }
 
 // Health checks database connectivity
@@ -179,7 +391,15 @@ func (d *DB) Health(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return d.pool.Ping(ctx)
+	if err := d.pool.Ping(ctx); err != nil {
+		return err
+	}
+	if d.replicaPool != nil {
+		if err := d.replicaPool.Ping(ctx); err != nil {
+			return fmt.Errorf("replica: %w", err)
+		}
+	}
+	return nil
 }
 
 // IsConfigured returns true if database is configured
@@ -2,11 +2,18 @@ package database
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rajasatyajit/SupplyChain/config"
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestNew_NoDatabase(t *testing.T) {
@@ -81,6 +88,25 @@ func TestDB_Operations_NoPool(t *testing.T) {
 	}
 }
 
+func TestDB_WithTx_NoPool(t *testing.T) {
+	db := &DB{
+		pool: nil,
+		cfg:  config.DatabaseConfig{},
+	}
+
+	called := false
+	err := db.WithTx(context.Background(), func(tx Tx) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error for WithTx with no pool, got nil")
+	}
+	if called {
+		t.Error("Expected fn not to run without a transaction")
+	}
+}
+
 func TestDB_Close(t *testing.T) {
 	db := &DB{
 		pool: nil,
@@ -239,3 +265,154 @@ func BenchmarkDB_Health(b *testing.B) {
 		db.Health(ctx)
 	}
 }
+
+func TestDB_ReplicaRouting(t *testing.T) {
+	logger.Init("error", "text")
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		withReplica bool
+		wantPrimary int
+		wantReplica int
+	}{
+		{"replica serves reads", true, 0, 2},
+		{"falls back to primary", false, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeQuerier{}
+			db := &DB{conn: primary}
+			var replica *fakeQuerier
+			if tt.withReplica {
+				replica = &fakeQuerier{}
+				db.replica = replica
+			}
+
+			if _, err := db.QueryReplica(ctx, "SELECT 1"); err != nil {
+				t.Fatalf("QueryReplica: %v", err)
+			}
+			if err := db.QueryRowReplica(ctx, "SELECT 1").(pgx.Row).Scan(); err != nil {
+				t.Fatalf("QueryRowReplica: %v", err)
+			}
+
+			if primary.calls != tt.wantPrimary {
+				t.Errorf("Expected %d primary calls, got %d", tt.wantPrimary, primary.calls)
+			}
+			if replica != nil && replica.calls != tt.wantReplica {
+				t.Errorf("Expected %d replica calls, got %d", tt.wantReplica, replica.calls)
+			}
+
+			// Writes and primary reads never touch the replica
+			if err := db.Exec(ctx, "UPDATE alerts SET title = ''"); err != nil {
+				t.Fatalf("Exec: %v", err)
+			}
+			if _, err := db.Query(ctx, "SELECT 1"); err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if primary.calls != tt.wantPrimary+2 {
+				t.Errorf("Expected writes on the primary, got %d primary calls", primary.calls)
+			}
+			if replica != nil && replica.calls != tt.wantReplica {
+				t.Errorf("Expected no writes on the replica, got %d replica calls", replica.calls)
+			}
+		})
+	}
+}
+
+func TestDB_Spans(t *testing.T) {
+	logger.Init("error", "text")
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx := context.Background()
+	db := &DB{conn: &fakeQuerier{errs: []error{nil, nil, errors.New("syntax error"), pgx.ErrNoRows}}}
+
+	db.Exec(ctx, "  insert INTO alerts VALUES ($1)", "a")
+	db.Query(ctx, "SELECT 1")
+	db.QueryRow(ctx, "UPDATE alerts SET title = $1 RETURNING id", "t").(pgx.Row).Scan()
+	db.QueryRow(ctx, "SELECT id FROM alerts").(pgx.Row).Scan()
+
+	want := []struct {
+		name      string
+		operation string
+		status    codes.Code
+	}{
+		{"db.exec", "INSERT", codes.Unset},
+		{"db.query", "SELECT", codes.Unset},
+		{"db.query_row", "UPDATE", codes.Error},
+		{"db.query_row", "SELECT", codes.Unset},
+	}
+	spans := recorder.Ended()
+	if len(spans) != len(want) {
+		t.Fatalf("Expected %d spans, got %d", len(want), len(spans))
+	}
+	for i, w := range want {
+		span := spans[i]
+		if span.Name() != w.name {
+			t.Errorf("Span %d: expected name %s, got %s", i, w.name, span.Name())
+		}
+		if !hasAttribute(span.Attributes(), attribute.String("db.operation", w.operation)) {
+			t.Errorf("Span %d: expected db.operation %s, got %v", i, w.operation, span.Attributes())
+		}
+		if span.Status().Code != w.status {
+			t.Errorf("Span %d: expected status %v, got %v", i, w.status, span.Status().Code)
+		}
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
+
+// ctxQuerier records the context its queries run under
+type ctxQuerier struct {
+	fakeQuerier
+	ctx  context.Context
+	rows closeRows
+}
+
+func (q *ctxQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.ctx = ctx
+	return &q.rows, nil
+}
+
+// closeRows is a pgx.Rows that only tracks Close
+type closeRows struct {
+	pgx.Rows
+	closed bool
+}
+
+func (r *closeRows) Close() { r.closed = true }
+
+func TestDB_QueryContextOutlivesCall(t *testing.T) {
+	logger.Init("error", "text")
+
+	q := &ctxQuerier{}
+	db := &DB{conn: q}
+
+	result, err := db.Query(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if q.ctx.Err() != nil {
+		t.Fatal("Expected the query context to stay live while rows are read")
+	}
+
+	rows := result.(pgx.Rows)
+	rows.Close()
+	if !q.rows.closed {
+		t.Error("Expected the underlying rows to be closed")
+	}
+	if q.ctx.Err() == nil {
+		t.Error("Expected closing the rows to cancel the query context")
+	}
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+)
+
+// Migrations live in migrations/ and are named <version>_<description>.sql,
+// where version is a zero-padded number such as 0001. They are applied in
+// version order, each in its own transaction, and recorded in
+// schema_migrations so a re-run only applies new files. Never edit a
+// migration that has shipped; add a new one instead.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockID is the advisory lock key that serialises migrations
+// across replicas starting at the same time
+const migrationLockID = 7230115
+
+var migrationName = regexp.MustCompile(`^(\d+)_[a-z0-9_]+\.sql$`)
+
+// migration is a single versioned SQL file
+type migration struct {
+	version string
+	name    string
+	sql     string
+}
+
+// Migrate applies any embedded migrations not yet recorded in
+// schema_migrations. It is a no-op when no database is configured.
+func Migrate(ctx context.Context, db *DB) error {
+	if !db.IsConfigured() {
+		return nil
+	}
+
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		return err
+	}
+
+	if err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		applied := false
+		err := db.WithTx(ctx, func(tx Tx) error {
+			if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+				return fmt.Errorf("lock migrations: %w", err)
+			}
+
+			var exists bool
+			if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.version).Scan(&exists); err != nil {
+				return fmt.Errorf("check migration %s: %w", m.name, err)
+			}
+			if exists {
+				return nil
+			}
+
+			if _, err := tx.Exec(ctx, m.sql); err != nil {
+				return fmt.Errorf("apply migration %s: %w", m.name, err)
+			}
+			if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version); err != nil {
+				return fmt.Errorf("record migration %s: %w", m.name, err)
+			}
+			applied = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if applied {
+			logger.Info("Applied database migration", "migration", m.name)
+		}
+	}
+
+	return nil
+}
+
+// loadMigrations reads the .sql files in dir, sorted by version. Files that
+// don't follow the naming convention and duplicate versions are errors.
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	var migrations []migration
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<description>.sql", entry.Name())
+		}
+		version := strings.TrimLeft(match[1], "0")
+		if version == "" {
+			version = "0"
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share a version", other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, migration{version: version, name: entry.Name(), sql: string(data)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		a, b := migrations[i].version, migrations[j].version
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+
+	return migrations, nil
+}
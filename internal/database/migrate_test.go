@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rajasatyajit/SupplyChain/config"
+)
+
+func TestMigrate_NoPool(t *testing.T) {
+	db := &DB{pool: nil, cfg: config.DatabaseConfig{}}
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Errorf("Expected no-op without a database, got %v", err)
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		t.Fatalf("Expected embedded migrations to load, got %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Expected at least one embedded migration")
+	}
+	if migrations[0].version != "1" {
+		t.Errorf("Expected first migration version 1, got %q", migrations[0].version)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     fstest.MapFS
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name: "sorted by numeric version",
+			files: fstest.MapFS{
+				"m/10_later.sql":    {Data: []byte("SELECT 10")},
+				"m/0002_second.sql": {Data: []byte("SELECT 2")},
+				"m/0001_first.sql":  {Data: []byte("SELECT 1")},
+				"m/README.md":       {Data: []byte("ignored")},
+			},
+			wantNames: []string{"0001_first.sql", "0002_second.sql", "10_later.sql"},
+		},
+		{
+			name:    "bad name",
+			files:   fstest.MapFS{"m/first.sql": {Data: []byte("SELECT 1")}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"m/0001_a.sql": {Data: []byte("SELECT 1")},
+				"m/1_b.sql":    {Data: []byte("SELECT 1")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files, "m")
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(migrations) != len(tt.wantNames) {
+				t.Fatalf("Expected %d migrations, got %d", len(tt.wantNames), len(migrations))
+			}
+			for i, name := range tt.wantNames {
+				if migrations[i].name != name {
+					t.Errorf("Expected migration %d to be %s, got %s", i, name, migrations[i].name)
+				}
+			}
+		})
+	}
+}
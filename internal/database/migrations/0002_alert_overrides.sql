@@ -0,0 +1,21 @@
+-- Manual corrections to alert classification
+CREATE TABLE IF NOT EXISTS alert_overrides (
+    alert_id VARCHAR(255) PRIMARY KEY REFERENCES alerts(id) ON DELETE CASCADE,
+    severity VARCHAR(50),
+    disruption VARCHAR(255),
+    submitted_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Alerts as read by the API, with corrections winning over the classifier
+CREATE OR REPLACE VIEW alerts_effective AS
+SELECT a.id, a.source, a.title, a.summary, a.url, a.detected_at, a.published_at,
+       a.region, a.country, a.location, a.latitude, a.longitude,
+       COALESCE(o.disruption, a.disruption) AS disruption,
+       COALESCE(o.severity, a.severity) AS severity,
+       a.sentiment, a.confidence, a.language, a.raw, a.created_at,
+       GREATEST(a.updated_at, o.updated_at) AS updated_at,
+       a.archived_at
+FROM alerts a
+LEFT JOIN alert_overrides o ON o.alert_id = a.id;
@@ -0,0 +1,14 @@
+-- Record how each alert's raw source item is encoded. Rows stored before
+-- this column existed hold an unstructured text dump and keep ''.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS raw_format VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE OR REPLACE VIEW alerts_effective AS
+SELECT a.id, a.source, a.title, a.summary, a.url, a.detected_at, a.published_at,
+       a.region, a.country, a.location, a.latitude, a.longitude,
+       COALESCE(o.disruption, a.disruption) AS disruption,
+       COALESCE(o.severity, a.severity) AS severity,
+       a.sentiment, a.confidence, a.language, a.raw, a.created_at,
+       GREATEST(a.updated_at, o.updated_at) AS updated_at,
+       a.archived_at, a.raw_format
+FROM alerts a
+LEFT JOIN alert_overrides o ON o.alert_id = a.id;
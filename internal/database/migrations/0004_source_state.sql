@@ -0,0 +1,7 @@
+-- Per-source ingestion state. The watermark is the newest published time
+-- the pipeline has processed, so later polls can skip older items.
+CREATE TABLE IF NOT EXISTS source_state (
+    source VARCHAR(255) PRIMARY KEY,
+    watermark TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
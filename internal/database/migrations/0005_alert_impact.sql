@@ -0,0 +1,16 @@
+-- Score how much each alert matters to supply-chain planners, combining
+-- severity with disruption and region weights. Existing rows keep 0.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS impact DECIMAL(3, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_impact ON alerts(impact DESC);
+
+CREATE OR REPLACE VIEW alerts_effective AS
+SELECT a.id, a.source, a.title, a.summary, a.url, a.detected_at, a.published_at,
+       a.region, a.country, a.location, a.latitude, a.longitude,
+       COALESCE(o.disruption, a.disruption) AS disruption,
+       COALESCE(o.severity, a.severity) AS severity,
+       a.sentiment, a.confidence, a.language, a.raw, a.created_at,
+       GREATEST(a.updated_at, o.updated_at) AS updated_at,
+       a.archived_at, a.raw_format, a.impact
+FROM alerts a
+LEFT JOIN alert_overrides o ON o.alert_id = a.id;
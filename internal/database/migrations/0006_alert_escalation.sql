@@ -0,0 +1,17 @@
+-- Flag alerts that raise the severity of the latest earlier alert for the
+-- same location and disruption. Existing rows are not escalations.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS escalated BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_escalated ON alerts(detected_at DESC) WHERE escalated;
+CREATE INDEX IF NOT EXISTS idx_alerts_location_disruption_detected ON alerts(location, disruption, detected_at DESC);
+
+CREATE OR REPLACE VIEW alerts_effective AS
+SELECT a.id, a.source, a.title, a.summary, a.url, a.detected_at, a.published_at,
+       a.region, a.country, a.location, a.latitude, a.longitude,
+       COALESCE(o.disruption, a.disruption) AS disruption,
+       COALESCE(o.severity, a.severity) AS severity,
+       a.sentiment, a.confidence, a.language, a.raw, a.created_at,
+       GREATEST(a.updated_at, o.updated_at) AS updated_at,
+       a.archived_at, a.raw_format, a.impact, a.escalated
+FROM alerts a
+LEFT JOIN alert_overrides o ON o.alert_id = a.id;
@@ -0,0 +1,60 @@
+-- Prior versions of alerts, copied before an update changes their content.
+-- Bookkeeping updates (archiving, updated_at alone) are not recorded.
+CREATE TABLE IF NOT EXISTS alert_history (
+    id BIGSERIAL PRIMARY KEY,
+    alert_id VARCHAR(255) NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    source VARCHAR(255) NOT NULL,
+    title TEXT NOT NULL,
+    summary TEXT,
+    url TEXT,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    region VARCHAR(255),
+    country VARCHAR(255),
+    location VARCHAR(255),
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    disruption VARCHAR(255),
+    severity VARCHAR(50),
+    sentiment VARCHAR(50),
+    confidence DECIMAL(3, 2),
+    language VARCHAR(10) NOT NULL DEFAULT '',
+    impact DECIMAL(3, 2) NOT NULL DEFAULT 0,
+    escalated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_history_alert_updated ON alert_history(alert_id, updated_at, id);
+
+CREATE OR REPLACE FUNCTION record_alert_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO alert_history (
+        alert_id, source, title, summary, url, detected_at, published_at,
+        region, country, location, latitude, longitude, disruption,
+        severity, sentiment, confidence, language, impact, escalated,
+        created_at, updated_at
+    ) VALUES (
+        OLD.id, OLD.source, OLD.title, OLD.summary, OLD.url, OLD.detected_at, OLD.published_at,
+        OLD.region, OLD.country, OLD.location, OLD.latitude, OLD.longitude, OLD.disruption,
+        OLD.severity, OLD.sentiment, OLD.confidence, OLD.language, OLD.impact, OLD.escalated,
+        OLD.created_at, OLD.updated_at
+    );
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS record_alerts_history ON alerts;
+CREATE TRIGGER record_alerts_history
+    AFTER UPDATE ON alerts
+    FOR EACH ROW
+    WHEN ((OLD.title, OLD.summary, OLD.url, OLD.published_at, OLD.region, OLD.country,
+           OLD.location, OLD.latitude, OLD.longitude, OLD.disruption, OLD.severity,
+           OLD.sentiment, OLD.confidence, OLD.language, OLD.impact, OLD.escalated)
+          IS DISTINCT FROM
+          (NEW.title, NEW.summary, NEW.url, NEW.published_at, NEW.region, NEW.country,
+           NEW.location, NEW.latitude, NEW.longitude, NEW.disruption, NEW.severity,
+           NEW.sentiment, NEW.confidence, NEW.language, NEW.impact, NEW.escalated))
+    EXECUTE FUNCTION record_alert_history();
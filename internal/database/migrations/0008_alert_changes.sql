@@ -0,0 +1,4 @@
+-- Indexes for the changes feed, which pages through alerts by the later of
+-- the alert's and its override's updated_at
+CREATE INDEX IF NOT EXISTS idx_alerts_updated_at ON alerts(updated_at);
+CREATE INDEX IF NOT EXISTS idx_alert_overrides_updated_at ON alert_overrides(updated_at);
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+)
+
+// Backoff bounds between retries of transient errors. Each delay is drawn
+// uniformly from zero up to the doubled base, capped at retryMaxDelay.
+var (
+	retryBaseDelay = 50 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// transientCodes are SQLSTATEs worth retrying: the server went away or
+// asked the client to try again
+var transientCodes = map[string]bool{
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// withRetry runs op, retrying up to cfg.RetryAttempts more times with
+// jittered exponential backoff while it fails with an error retryable
+// accepts, typically isTransient or isSafeToRetry.
+func (d *DB) withRetry(ctx context.Context, retryable func(error) bool, op func(ctx context.Context) error) error {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := op(ctx)
+		if err == nil || attempt >= d.cfg.RetryAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := time.Duration(rand.Int63n(int64(delay) + 1))
+		logger.Warn("Retrying transient database error",
+			"error", err,
+			"attempt", attempt+1,
+			"wait_ms", wait.Milliseconds(),
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+// retryPolicy returns which failures of sql to retry. A statement that
+// lost its connection may already have run, so only read-only statements
+// retry on any transient error; writes retry only when isSafeToRetry.
+func retryPolicy(sql string) func(error) bool {
+	if readOnly(sql) {
+		return isTransient
+	}
+	return isSafeToRetry
+}
+
+// readOnly reports whether sql only reads: a SELECT, or a WITH query with
+// no data-modifying statement
+func readOnly(sql string) bool {
+	switch sqlOperation(sql) {
+	case "SELECT":
+		return true
+	case "WITH":
+		words := strings.FieldsFunc(strings.ToUpper(sql), func(r rune) bool {
+			return !unicode.IsLetter(r) && r != '_'
+		})
+		for _, word := range words {
+			switch word {
+			case "INSERT", "UPDATE", "DELETE", "MERGE":
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isSafeToRetry reports whether a write that failed with err can be retried
+// without applying it twice: the request never reached the server, or the
+// server reported an error that rolled the statement back
+func isSafeToRetry(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientCodes[pgErr.Code]
+	}
+	return pgconn.SafeToRetry(err)
+}
+
+// isTransient reports whether err is a connection-level or server-side
+// failure that may succeed on retry. Context cancellation and constraint
+// violations are never transient.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection_exception
+		return transientCodes[pgErr.Code] || len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
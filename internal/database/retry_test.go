@@ -0,0 +1,245 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rajasatyajit/SupplyChain/config"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+)
+
+// fakeQuerier returns the queued errors in order, then succeeds
+type fakeQuerier struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeQuerier) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, f.next()
+}
+
+func (f *fakeQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, f.next()
+}
+
+func (f *fakeQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return fakeRow{err: f.next()}
+}
+
+type fakeRow struct{ err error }
+
+func (r fakeRow) Scan(dest ...any) error { return r.err }
+
+func withFastRetries(t *testing.T) {
+	t.Helper()
+	base, max := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = base, max })
+}
+
+func TestDB_RetriesTransientErrors(t *testing.T) {
+	logger.Init("error", "text")
+	withFastRetries(t)
+
+	shutdown := &pgconn.PgError{Code: "57P01", Message: "terminating connection due to administrator command"}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func(db *DB) error
+	}{
+		{"exec", func(db *DB) error { return db.Exec(ctx, "SELECT 1") }},
+		{"query", func(db *DB) error { _, err := db.Query(ctx, "SELECT 1"); return err }},
+		{"query row", func(db *DB) error { return db.QueryRow(ctx, "SELECT 1").(pgx.Row).Scan() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeQuerier{errs: []error{shutdown}}
+			db := &DB{cfg: config.DatabaseConfig{RetryAttempts: 2}, conn: fake}
+
+			if err := tt.run(db); err != nil {
+				t.Fatalf("Expected retry to succeed, got %v", err)
+			}
+			if fake.calls != 2 {
+				t.Errorf("Expected 2 calls, got %d", fake.calls)
+			}
+		})
+	}
+}
+
+// unsentError is a failure pgconn.SafeToRetry reports as never sent
+type unsentError struct{}
+
+func (unsentError) Error() string     { return "dial: connection refused" }
+func (unsentError) SafeToRetry() bool { return true }
+
+func TestDB_WithRetry(t *testing.T) {
+	logger.Init("error", "text")
+	withFastRetries(t)
+
+	reset := fmt.Errorf("read: %w", syscall.ECONNRESET)
+	unique := &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"}
+	serialization := &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+	ctx := context.Background()
+
+	exec := func(sql string) func(db *DB) error {
+		return func(db *DB) error { return db.Exec(ctx, sql) }
+	}
+	query := func(sql string) func(db *DB) error {
+		return func(db *DB) error { _, err := db.Query(ctx, sql); return err }
+	}
+	queryRow := func(sql string) func(db *DB) error {
+		return func(db *DB) error { return db.QueryRow(ctx, sql).(pgx.Row).Scan() }
+	}
+
+	tests := []struct {
+		name      string
+		run       func(db *DB) error
+		attempts  int
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"read succeeds after reset", query("SELECT 1"), 2, []error{reset}, 2, false},
+		{"read gives up after attempts", query("SELECT 1"), 2, []error{reset, reset, reset, reset}, 3, true},
+		{"read with cte retried", queryRow("WITH recent AS (SELECT updated_at FROM alerts) SELECT count(*) FROM recent"), 2, []error{reset}, 2, false},
+		{"retries disabled", query("SELECT 1"), 0, []error{reset}, 1, true},
+		{"exec not retried after reset", exec("INSERT 1"), 2, []error{reset}, 1, true},
+		{"exec retried when unsent", exec("INSERT 1"), 2, []error{unsentError{}}, 2, false},
+		{"exec retried on serialization failure", exec("INSERT 1"), 2, []error{serialization}, 2, false},
+		{"returning write not retried after reset", queryRow("INSERT INTO alerts (id) VALUES ($1) RETURNING id"), 2, []error{reset}, 1, true},
+		{"writing cte not retried after reset", queryRow("WITH archived AS (UPDATE alerts SET archived_at = now() RETURNING 1) SELECT count(*) FROM archived"), 2, []error{reset}, 1, true},
+		{"returning write retried when unsent", query("DELETE FROM webhooks WHERE id = $1 RETURNING id"), 2, []error{unsentError{}}, 2, false},
+		{"constraint violation", exec("INSERT 1"), 2, []error{unique}, 1, true},
+		{"context canceled", query("SELECT 1"), 2, []error{context.Canceled}, 1, true},
+		{"no rows", queryRow("SELECT 1"), 2, []error{pgx.ErrNoRows}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeQuerier{errs: tt.errs}
+			db := &DB{cfg: config.DatabaseConfig{RetryAttempts: tt.attempts}, conn: fake}
+
+			err := tt.run(db)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, fake.calls)
+			}
+		})
+	}
+}
+
+func TestDB_WithRetry_StopsWhenContextDone(t *testing.T) {
+	logger.Init("error", "text")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	db := &DB{cfg: config.DatabaseConfig{RetryAttempts: 5}}
+
+	err := db.withRetry(ctx, isTransient, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return io.ErrUnexpectedEOF
+	})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected last error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"syntax error", &pgconn.PgError{Code: "42601"}, false},
+		{"connection reset", fmt.Errorf("write: %w", syscall.ECONNRESET), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"canceled", context.Canceled, false},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSafeToRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, false},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"unsent", fmt.Errorf("connect: %w", unsentError{}), true},
+		{"connection reset", fmt.Errorf("write: %w", syscall.ECONNRESET), false},
+		{"unexpected eof", io.ErrUnexpectedEOF, false},
+		{"canceled", context.Canceled, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSafeToRetry(tt.err); got != tt.want {
+				t.Errorf("isSafeToRetry(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT id FROM alerts", true},
+		{"  select id FROM alerts FOR UPDATE", true},
+		{"WITH recent AS (SELECT updated_at FROM alerts) SELECT * FROM recent", true},
+		{"WITH archived AS (UPDATE alerts SET archived_at = now() RETURNING 1) SELECT count(*) FROM archived", false},
+		{"INSERT INTO alerts (id) VALUES ($1) RETURNING id", false},
+		{"DELETE FROM webhooks WHERE id = $1 RETURNING id", false},
+		{"UPDATE alerts SET severity = $1", false},
+	}
+
+	for _, tt := range tests {
+		if got := readOnly(tt.sql); got != tt.want {
+			t.Errorf("readOnly(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
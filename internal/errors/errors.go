@@ -18,6 +18,87 @@ var (
 	ErrNotImplemented     = errors.New("not implemented")
 )
 
+// Code is a machine-readable error code returned in API error responses
+type Code string
+
+// Error codes
+const (
+	CodeInvalidInput       Code = "invalid_input"
+	CodeInvalidLimit       Code = "invalid_limit"
+	CodeInvalidOffset      Code = "invalid_offset"
+	CodeInvalidCursor      Code = "invalid_cursor"
+	CodeInvalidSort        Code = "invalid_sort"
+	CodeInvalidGroupBy     Code = "invalid_group_by"
+	CodeInvalidTime        Code = "invalid_time"
+	CodeInvalidTimeRange   Code = "invalid_time_range"
+	CodeInvalidBBox        Code = "invalid_bbox"
+	CodeUnsupportedFormat  Code = "unsupported_format"
+	CodePayloadTooLarge    Code = "payload_too_large"
+	CodeNotFound           Code = "not_found"
+	CodeAlertNotFound      Code = "alert_not_found"
+	CodeSourceNotFound     Code = "source_not_found"
+	CodeWebhookNotFound    Code = "webhook_not_found"
+	CodeMethodNotAllowed   Code = "method_not_allowed"
+	CodeUnauthorized       Code = "unauthorized"
+	CodeForbidden          Code = "forbidden"
+	CodeConflict           Code = "conflict"
+	CodeRateLimited        Code = "rate_limited"
+	CodeServiceUnavailable Code = "service_unavailable"
+	CodeTimeout            Code = "timeout"
+	CodeNotImplemented     Code = "not_implemented"
+	CodeInternal           Code = "internal_error"
+)
+
+// sentinelCodes maps the application errors to their codes
+var sentinelCodes = []struct {
+	err  error
+	code Code
+}{
+	{ErrNotFound, CodeNotFound},
+	{ErrInvalidInput, CodeInvalidInput},
+	{ErrUnauthorized, CodeUnauthorized},
+	{ErrForbidden, CodeForbidden},
+	{ErrConflict, CodeConflict},
+	{ErrRateLimit, CodeRateLimited},
+	{ErrServiceUnavailable, CodeServiceUnavailable},
+	{ErrTimeout, CodeTimeout},
+	{ErrNotImplemented, CodeNotImplemented},
+}
+
+// CodedError attaches an error code to an error
+type CodedError struct {
+	Code Code
+	Err  error
+}
+
+func (e CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e CodedError) Unwrap() error {
+	return e.Err
+}
+
+// Newf creates an error with a code and a formatted message
+func Newf(code Code, format string, args ...any) error {
+	return CodedError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// CodeOf returns the code attached to err, the code of the application
+// error it wraps, or CodeInternal
+func CodeOf(err error) Code {
+	var coded CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	for _, sc := range sentinelCodes {
+		if errors.Is(err, sc.err) {
+			return sc.code
+		}
+	}
+	return CodeInternal
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -184,3 +185,52 @@ func TestErrorConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{
+			name:     "Coded error",
+			err:      Newf(CodeInvalidLimit, "invalid limit: %s", "abc"),
+			expected: CodeInvalidLimit,
+		},
+		{
+			name:     "Wrapped coded error",
+			err:      fmt.Errorf("parse: %w", Newf(CodeInvalidCursor, "bad cursor")),
+			expected: CodeInvalidCursor,
+		},
+		{
+			name:     "Sentinel not found",
+			err:      fmt.Errorf("alert 123: %w", ErrNotFound),
+			expected: CodeNotFound,
+		},
+		{
+			name:     "Sentinel rate limit",
+			err:      ErrRateLimit,
+			expected: CodeRateLimited,
+		},
+		{
+			name:     "Unknown error",
+			err:      errors.New("boom"),
+			expected: CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCodedError_Message(t *testing.T) {
+	err := Newf(CodeInvalidLimit, "invalid limit: %s", "abc")
+	if err.Error() != "invalid limit: abc" {
+		t.Errorf("Expected message to be preserved, got %s", err.Error())
+	}
+}
@@ -0,0 +1,83 @@
+package geocoder
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// countriesCSV lists every ISO 3166-1 country as alpha2,alpha3,name,region.
+// Regions are continents, with Central America and the Caribbean under
+// North America and the Middle East under Asia.
+//
+//go:embed data/countries.csv
+var countriesCSV []byte
+
+// codeAliases are common codes that are not ISO 3166-1 alpha-2
+var codeAliases = map[string]string{
+	"UK": "GB",
+}
+
+// countryTable resolves country codes to names and names to regions
+type countryTable struct {
+	byCode   map[string]string // alpha-2 and alpha-3 code -> name
+	byName   map[string]string // name -> region
+	numCodes int
+}
+
+// loadCountries parses the embedded table once; it is static data, so a
+// parse failure is a bug and panics
+var loadCountries = sync.OnceValue(func() *countryTable {
+	t, err := parseCountries(countriesCSV)
+	if err != nil {
+		panic(err)
+	}
+	return t
+})
+
+// parseCountries reads a country table in the countriesCSV format
+func parseCountries(data []byte) (*countryTable, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = 4
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse countries: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("parse countries: missing header")
+	}
+
+	t := &countryTable{
+		byCode: make(map[string]string, 2*len(records)),
+		byName: make(map[string]string, len(records)),
+	}
+	for _, rec := range records[1:] {
+		alpha2, alpha3, name, region := rec[0], rec[1], rec[2], rec[3]
+		if len(alpha2) != 2 || len(alpha3) != 3 || name == "" || region == "" {
+			return nil, fmt.Errorf("parse countries: invalid row %q", strings.Join(rec, ","))
+		}
+		t.byCode[alpha2] = name
+		t.byCode[alpha3] = name
+		t.byName[name] = region
+		t.numCodes++
+	}
+	for alias, code := range codeAliases {
+		t.byCode[alias] = t.byCode[code]
+	}
+
+	return t, nil
+}
+
+// country returns the country name for an upper-case alpha-2 or alpha-3 code
+func (t *countryTable) country(code string) (string, bool) {
+	name, ok := t.byCode[code]
+	return name, ok
+}
+
+// region returns the region of a country name, or "" if it is unknown
+func (t *countryTable) region(country string) string {
+	return t.byName[country]
+}
@@ -0,0 +1,63 @@
+package geocoder
+
+import "testing"
+
+func TestLoadCountries(t *testing.T) {
+	countries := loadCountries()
+	if countries.numCodes != 249 {
+		t.Errorf("Expected all 249 ISO 3166-1 countries, got %d", countries.numCodes)
+	}
+
+	tests := []struct {
+		code    string
+		country string
+		region  string
+	}{
+		{"US", "United States", "North America"},
+		{"USA", "United States", "North America"},
+		{"UK", "United Kingdom", "Europe"},
+		{"GB", "United Kingdom", "Europe"},
+		{"NG", "Nigeria", "Africa"},
+		{"SG", "Singapore", "Asia"},
+		{"CL", "Chile", "South America"},
+		{"AU", "Australia", "Oceania"},
+		{"PA", "Panama", "North America"},
+		{"AQ", "Antarctica", "Antarctica"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			country, ok := countries.country(tt.code)
+			if !ok || country != tt.country {
+				t.Fatalf("Expected %s for %s, got %q", tt.country, tt.code, country)
+			}
+			if region := countries.region(country); region != tt.region {
+				t.Errorf("Expected region %s for %s, got %s", tt.region, country, region)
+			}
+		})
+	}
+
+	if _, ok := countries.country("XX"); ok {
+		t.Error("Expected unassigned code XX to be unknown")
+	}
+}
+
+func TestParseCountries_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"missing column", "alpha2,alpha3,name,region\nUS,USA,United States\n"},
+		{"bad code", "alpha2,alpha3,name,region\nUSA,US,United States,North America\n"},
+		{"missing region", "alpha2,alpha3,name,region\nUS,USA,United States,\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseCountries([]byte(tt.data)); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package geocoder
 
 import (
+	"context"
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -10,102 +12,100 @@ import (
 // Geocoder provides geolocation functionality for alerts
 type Geocoder struct {
 	cityRegex *regexp.Regexp
+	countries *countryTable
+	ports     *gazetteer
+	provider  Provider
 }
 
-// New creates a new geocoder instance
+// New creates a new geocoder instance that only extracts place names
 func New() *Geocoder {
 	return &Geocoder{
 		// Match "Port of X Y" (case-insensitive for the phrase 'Port of') or "City, ST"
 		cityRegex: regexp.MustCompile(`\b((?i:port of)\s+[A-Z][a-zA-Z]+(?:\s+[A-Z][a-zA-Z]+)?|[A-Z][a-zA-Z]+(?:\s+[A-Z][a-zA-Z]+)*,\s*[A-Z]{2})\b`),
+		countries: loadCountries(),
+		ports:     loadGazetteer(),
 	}
 }
 
-// Geocode extracts location information from an alert
-func (g *Geocoder) Geocode(alert *models.Alert) error {
+// NewWithProvider creates a geocoder that resolves extracted place names to
+// coordinates using provider
+func NewWithProvider(provider Provider) *Geocoder {
+	g := New()
+	g.provider = provider
+	return g
+}
+
+// Geocode extracts location information from an alert. Ports in the
+// bundled gazetteer resolve to coordinates without consulting the provider,
+// whose lookups are bounded by ctx.
+func (g *Geocoder) Geocode(ctx context.Context, alert *models.Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	text := alert.Title + " " + alert.Summary
 
+	if p, ok := g.ports.find(text); ok {
+		alert.Location = p.name
+		alert.Latitude = p.lat
+		alert.Longitude = p.lon
+		alert.Country, _ = g.countries.country(p.country)
+		alert.Region = g.countries.region(alert.Country)
+		return nil
+	}
+
 	// Extract location using regex
-	if loc := g.cityRegex.FindString(text); loc != "" {
-		alert.Location = loc
+	loc := g.cityRegex.FindString(text)
+	if loc == "" {
+		return nil
+	}
+
+	alert.Location = loc
+	alert.Latitude = 0.0
+	alert.Longitude = 0.0
 
-		// In a production system, you would:
-		// 1. Use a proper geocoding service (Google Maps, OpenStreetMap, etc.)
-		// 2. Cache results to avoid repeated API calls
-		// 3. Handle rate limiting
-		// 4. Set actual latitude/longitude coordinates
+	// Extract region and country if possible
+	g.extractRegionAndCountry(alert, loc)
 
-		// For now, we'll leave lat/lon as zero and just set the location string
-		alert.Latitude = 0.0
-		alert.Longitude = 0.0
+	if g.provider == nil {
+		return nil
+	}
 
-		// Extract region and country if possible
-		g.extractRegionAndCountry(alert, loc)
+	lat, lon, country, region, err := g.provider.Lookup(ctx, loc)
+	if err != nil {
+		return fmt.Errorf("lookup %q: %w", loc, err)
+	}
+
+	alert.Latitude = lat
+	alert.Longitude = lon
+	if country != "" {
+		alert.Country = country
+	}
+	if region != "" {
+		alert.Region = region
 	}
 
 	return nil
 }
 
-// extractRegionAndCountry attempts to extract region and country from location
-func (g *Geocoder) extractRegionAndCountry(alert *models.Alert, location string) {
-	// This is a simplified implementation
-	// In production, you would use a proper geocoding service
-
-	locationLower := strings.ToLower(location)
-
-	// Simple country detection based on common patterns
-	countryPatterns := map[string]string{
-		"usa": "United States",
-		"us":  "United States",
-		"uk":  "United Kingdom",
-		"ca":  "Canada",
-		"mx":  "Mexico",
-		"de":  "Germany",
-		"fr":  "France",
-		"it":  "Italy",
-		"es":  "Spain",
-		"jp":  "Japan",
-		"cn":  "China",
-		"in":  "India",
-		"br":  "Brazil",
-		"au":  "Australia",
-	}
+// tokenSplit splits a location into letter runs, so codes only match whole
+// words (e.g. "ES" never matches inside "Los Angeles")
+var tokenSplit = regexp.MustCompile(`[^A-Za-z]+`)
 
-	// Tokenize location to avoid substring collisions (e.g., "es" in "los angeles")
-	tokens := regexp.MustCompile(`[^a-z]+`).Split(locationLower, -1)
-	tokenSet := make(map[string]struct{}, len(tokens))
-	for _, tk := range tokens {
-		if tk == "" {
+// extractRegionAndCountry sets the country and region from an upper-case
+// ISO 3166-1 code in location, such as "Hamburg, DE" or "Houston, USA".
+// Codes usually trail the place name, so the last one wins.
+func (g *Geocoder) extractRegionAndCountry(alert *models.Alert, location string) {
+	tokens := tokenSplit.Split(location, -1)
+	for i := len(tokens) - 1; i >= 0; i-- {
+		// Lower-case words such as "in" or "and" are not codes
+		if tokens[i] != strings.ToUpper(tokens[i]) {
 			continue
 		}
-		tokenSet[tk] = struct{}{}
-	}
-	for pattern, country := range countryPatterns {
-		if _, ok := tokenSet[pattern]; ok {
+		if country, ok := g.countries.country(tokens[i]); ok {
 			alert.Country = country
-			break
-		}
-	}
-
-	// Simple region detection
-	if alert.Country != "" {
-		regionMap := map[string]string{
-			"United States":  "North America",
-			"Canada":         "North America",
-			"Mexico":         "North America",
-			"United Kingdom": "Europe",
-			"Germany":        "Europe",
-			"France":         "Europe",
-			"Italy":          "Europe",
-			"Spain":          "Europe",
-			"Japan":          "Asia",
-			"China":          "Asia",
-			"India":          "Asia",
-			"Brazil":         "South America",
-			"Australia":      "Oceania",
-		}
-
-		if region, exists := regionMap[alert.Country]; exists {
-			alert.Region = region
+			alert.Region = g.countries.region(country)
+			return
 		}
 	}
 }
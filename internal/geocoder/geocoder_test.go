@@ -1,6 +1,8 @@
 package geocoder
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/rajasatyajit/SupplyChain/internal/models"
@@ -15,6 +17,8 @@ func TestGeocoder_Geocode(t *testing.T) {
 		expectedLocation string
 		expectedCountry  string
 		expectedRegion   string
+		expectedLat      float64
+		expectedLon      float64
 	}{
 		{
 			name: "Port location extraction",
@@ -23,9 +27,45 @@ func TestGeocoder_Geocode(t *testing.T) {
 				Summary: "Major disruption at the port facility",
 			},
 			expectedLocation: "Port of Los Angeles",
+			expectedCountry:  "United States",
+			expectedRegion:   "North America",
+			expectedLat:      33.7361,
+			expectedLon:      -118.2922,
+		},
+		{
+			name: "Port outside the gazetteer",
+			alert: models.Alert{
+				Title:   "Strike at Port of Eureka halts work",
+				Summary: "Major disruption at the port facility",
+			},
+			expectedLocation: "Port of Eureka",
 			expectedCountry:  "",
 			expectedRegion:   "",
 		},
+		{
+			name: "Gazetteer port",
+			alert: models.Alert{
+				Title:   "Congestion builds at the port of rotterdam",
+				Summary: "Vessels waiting at anchor",
+			},
+			expectedLocation: "Port of Rotterdam",
+			expectedCountry:  "Netherlands",
+			expectedRegion:   "Europe",
+			expectedLat:      51.949,
+			expectedLon:      4.145,
+		},
+		{
+			name: "Gazetteer port without 'Port of'",
+			alert: models.Alert{
+				Title:   "Port Klang volumes recover",
+				Summary: "Throughput back to normal",
+			},
+			expectedLocation: "Port Klang",
+			expectedCountry:  "Malaysia",
+			expectedRegion:   "Asia",
+			expectedLat:      3,
+			expectedLon:      101.39,
+		},
 		{
 			name: "City and state extraction",
 			alert: models.Alert{
@@ -53,14 +93,28 @@ func TestGeocoder_Geocode(t *testing.T) {
 				Summary: "Multiple facilities affected",
 			},
 			expectedLocation: "Port of Miami",
-			expectedCountry:  "",
-			expectedRegion:   "",
+			expectedCountry:  "United States",
+			expectedRegion:   "North America",
+			expectedLat:      25.7743,
+			expectedLon:      -80.17,
+		},
+		{
+			name: "Multiple gazetteer ports - first match",
+			alert: models.Alert{
+				Title:   "Issues at Port of Long Beach and Port of Los Angeles",
+				Summary: "Multiple facilities affected",
+			},
+			expectedLocation: "Port of Long Beach",
+			expectedCountry:  "United States",
+			expectedRegion:   "North America",
+			expectedLat:      33.754,
+			expectedLon:      -118.216,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := geocoder.Geocode(&tt.alert)
+			err := geocoder.Geocode(context.Background(), &tt.alert)
 			if err != nil {
 				t.Errorf("Expected no error, got %v", err)
 			}
@@ -69,13 +123,17 @@ func TestGeocoder_Geocode(t *testing.T) {
 				t.Errorf("Expected location %s, got %s", tt.expectedLocation, tt.alert.Location)
 			}
 
-			// Latitude and longitude should remain 0 in this implementation
-			if tt.alert.Latitude != 0.0 {
-				t.Errorf("Expected latitude 0.0, got %f", tt.alert.Latitude)
+			if tt.alert.Country != tt.expectedCountry || tt.alert.Region != tt.expectedRegion {
+				t.Errorf("Expected %s / %s, got %s / %s", tt.expectedCountry, tt.expectedRegion, tt.alert.Country, tt.alert.Region)
 			}
 
-			if tt.alert.Longitude != 0.0 {
-				t.Errorf("Expected longitude 0.0, got %f", tt.alert.Longitude)
+			// Only gazetteer ports get coordinates without a provider
+			if tt.alert.Latitude != tt.expectedLat {
+				t.Errorf("Expected latitude %f, got %f", tt.expectedLat, tt.alert.Latitude)
+			}
+
+			if tt.alert.Longitude != tt.expectedLon {
+				t.Errorf("Expected longitude %f, got %f", tt.expectedLon, tt.alert.Longitude)
 			}
 		})
 	}
@@ -108,6 +166,20 @@ func TestGeocoder_ExtractRegionAndCountry(t *testing.T) {
 			expectedCountry: "Germany",
 			expectedRegion:  "Europe",
 		},
+		{
+			name:            "Alpha-3 code",
+			location:        "Houston, USA",
+			expectedCountry: "United States",
+			expectedRegion:  "North America",
+		},
+		{"Africa", "Mombasa, KE", "Kenya", "Africa"},
+		{"South America", "Port of Santos, BR", "Brazil", "South America"},
+		{"Middle East", "Jebel Ali, AE", "United Arab Emirates", "Asia"},
+		{"Oceania", "Auckland, NZ", "New Zealand", "Oceania"},
+		{"Caribbean", "Kingston, JM", "Jamaica", "North America"},
+		{"Code collision inside word", "Port of Los Angeles", "", ""},
+		{"Lower-case words are not codes", "Port of Spain in Trinidad", "", ""},
+		{"Last code wins", "Port of Antwerp BE, NL", "Netherlands", "Europe"},
 		{
 			name:            "Unknown location",
 			location:        "Unknown City",
@@ -143,3 +215,72 @@ func TestNew(t *testing.T) {
 		t.Error("Expected city regex to be initialized")
 	}
 }
+
+func TestGeocoder_GeocodeWithProvider(t *testing.T) {
+	provider := &countingProvider{}
+	geocoder := NewWithProvider(provider)
+
+	alert := models.Alert{Title: "Strike at Port of Eureka"}
+	if err := geocoder.Geocode(context.Background(), &alert); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if provider.calls["Port of Eureka"] != 1 {
+		t.Errorf("Expected provider lookup for extracted place, got %v", provider.calls)
+	}
+	if alert.Latitude != 1 || alert.Longitude != 2 {
+		t.Errorf("Expected coordinates from provider, got %v, %v", alert.Latitude, alert.Longitude)
+	}
+	if alert.Country != "Country Port of Eureka" || alert.Region != "Region" {
+		t.Errorf("Expected country and region from provider, got %s / %s", alert.Country, alert.Region)
+	}
+
+	// No place extracted means no lookup
+	other := models.Alert{Title: "General supply chain update"}
+	if err := geocoder.Geocode(context.Background(), &other); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(provider.calls) != 1 {
+		t.Errorf("Expected no additional lookups, got %v", provider.calls)
+	}
+
+	// Gazetteer ports don't need a lookup
+	known := models.Alert{Title: "Strike at Port of Los Angeles"}
+	if err := geocoder.Geocode(context.Background(), &known); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(provider.calls) != 1 {
+		t.Errorf("Expected no lookup for a gazetteer port, got %v", provider.calls)
+	}
+	if known.Latitude == 0 || known.Country != "United States" {
+		t.Errorf("Expected gazetteer coordinates and country, got %v, %v, %s", known.Latitude, known.Longitude, known.Country)
+	}
+}
+
+func TestGeocoder_GeocodeWithProviderError(t *testing.T) {
+	geocoder := NewWithProvider(&countingProvider{err: ErrPlaceNotFound})
+
+	alert := models.Alert{Title: "Delays in Seattle, WA"}
+	if err := geocoder.Geocode(context.Background(), &alert); err == nil {
+		t.Error("Expected error from failed lookup, got nil")
+	}
+	if alert.Location != "Seattle, WA" {
+		t.Errorf("Expected location to be kept on lookup failure, got %s", alert.Location)
+	}
+}
+
+func TestGeocoder_GeocodeCanceled(t *testing.T) {
+	provider := &countingProvider{}
+	geocoder := NewWithProvider(provider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	alert := models.Alert{Title: "Strike at Port of Eureka"}
+	if err := geocoder.Geocode(ctx, &alert); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(provider.calls) != 0 || alert.Location != "" {
+		t.Errorf("Expected a cancelled geocode to do nothing, got lookups %v and location %q", provider.calls, alert.Location)
+	}
+}
@@ -0,0 +1,98 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultNominatimURL is the public OpenStreetMap Nominatim endpoint
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// NominatimProvider resolves places using the Nominatim search API
+type NominatimProvider struct {
+	baseURL   string
+	client    *http.Client
+	countries *countryTable
+}
+
+// NewNominatimProvider creates a Nominatim provider for baseURL with the given request timeout
+func NewNominatimProvider(baseURL string, timeout time.Duration) *NominatimProvider {
+	if baseURL == "" {
+		baseURL = DefaultNominatimURL
+	}
+	return &NominatimProvider{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		client:    &http.Client{Timeout: timeout},
+		countries: loadCountries(),
+	}
+}
+
+type nominatimResult struct {
+	Lat     string `json:"lat"`
+	Lon     string `json:"lon"`
+	Address struct {
+		Country     string `json:"country"`
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+// Lookup queries Nominatim for the best match for place
+func (n *NominatimProvider) Lookup(ctx context.Context, place string) (float64, float64, string, string, error) {
+	params := url.Values{}
+	params.Set("q", place)
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+	params.Set("addressdetails", "1")
+	params.Set("accept-language", "en")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", n.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("create request: %w", err)
+	}
+
+	// Nominatim's usage policy requires an identifying User-Agent
+	req.Header.Set("User-Agent", "SupplyChain-Monitor/1.0")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("nominatim lookup: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, "", "", fmt.Errorf("nominatim HTTP %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, "", "", fmt.Errorf("parse nominatim response: %w", err)
+	}
+
+	if len(results) == 0 {
+		return 0, 0, "", "", ErrPlaceNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("parse latitude: %w", err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("parse longitude: %w", err)
+	}
+
+	// Nominatim's English names don't always match ours, so the region
+	// comes from the ISO code when there is one
+	country := results[0].Address.Country
+	region := n.countries.region(country)
+	if name, ok := n.countries.country(strings.ToUpper(results[0].Address.CountryCode)); ok {
+		region = n.countries.region(name)
+	}
+	return lat, lon, country, region, nil
+}
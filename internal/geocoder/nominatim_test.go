@@ -0,0 +1,81 @@
+package geocoder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNominatimProvider_Lookup(t *testing.T) {
+	var gotQuery, gotUA string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("q")
+		gotUA = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"lat":"33.7361","lon":"-118.2922","address":{"country":"United States"}}]`))
+	}))
+	defer server.Close()
+
+	provider := NewNominatimProvider(server.URL+"/", time.Second)
+	lat, lon, country, region, err := provider.Lookup(context.Background(), "Port of Los Angeles")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if gotQuery != "Port of Los Angeles" {
+		t.Errorf("Expected q=Port of Los Angeles, got %q", gotQuery)
+	}
+	if gotUA == "" {
+		t.Error("Expected a User-Agent header")
+	}
+	if lat != 33.7361 || lon != -118.2922 {
+		t.Errorf("Unexpected coordinates: %v, %v", lat, lon)
+	}
+	if country != "United States" || region != "North America" {
+		t.Errorf("Unexpected country/region: %s / %s", country, region)
+	}
+}
+
+func TestNominatimProvider_RegionFromCountryCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"lat":"41.0","lon":"28.9","address":{"country":"Türkiye","country_code":"tr"}}]`))
+	}))
+	defer server.Close()
+
+	provider := NewNominatimProvider(server.URL, time.Second)
+	_, _, country, region, err := provider.Lookup(context.Background(), "Istanbul")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if country != "Türkiye" || region != "Asia" {
+		t.Errorf("Unexpected country/region: %s / %s", country, region)
+	}
+}
+
+func TestNominatimProvider_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	provider := NewNominatimProvider(server.URL, time.Second)
+	_, _, _, _, err := provider.Lookup(context.Background(), "Nowhere")
+	if !errors.Is(err, ErrPlaceNotFound) {
+		t.Errorf("Expected ErrPlaceNotFound, got %v", err)
+	}
+}
+
+func TestNominatimProvider_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider := NewNominatimProvider(server.URL, time.Second)
+	if _, _, _, _, err := provider.Lookup(context.Background(), "Anywhere"); err == nil {
+		t.Error("Expected error on HTTP 429, got nil")
+	}
+}
@@ -0,0 +1,90 @@
+package geocoder
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// portsCSV lists major ports as name,latitude,longitude,country, where
+// country is an ISO 3166-1 alpha-2 code
+//
+//go:embed data/ports.csv
+var portsCSV []byte
+
+// port is a gazetteer entry
+type port struct {
+	name    string
+	lat     float64
+	lon     float64
+	country string // alpha-2 code
+}
+
+// gazetteer finds well-known ports in free text without a network lookup
+type gazetteer struct {
+	pattern *regexp.Regexp
+	ports   map[string]port // lower-case name -> port
+}
+
+// loadGazetteer parses the embedded port table once; like the country
+// table it is static data, so a parse failure panics
+var loadGazetteer = sync.OnceValue(func() *gazetteer {
+	g, err := parsePorts(portsCSV, loadCountries())
+	if err != nil {
+		panic(err)
+	}
+	return g
+})
+
+// parsePorts reads a port table in the portsCSV format, rejecting country
+// codes that countries doesn't know
+func parsePorts(data []byte, countries *countryTable) (*gazetteer, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = 4
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse ports: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("parse ports: missing header")
+	}
+
+	g := &gazetteer{ports: make(map[string]port, len(records))}
+	names := make([]string, 0, len(records))
+	for _, rec := range records[1:] {
+		lat, latErr := strconv.ParseFloat(rec[1], 64)
+		lon, lonErr := strconv.ParseFloat(rec[2], 64)
+		_, known := countries.country(rec[3])
+		if rec[0] == "" || latErr != nil || lonErr != nil || len(rec[3]) != 2 || !known {
+			return nil, fmt.Errorf("parse ports: invalid row %q", strings.Join(rec, ","))
+		}
+		g.ports[strings.ToLower(rec[0])] = port{name: rec[0], lat: lat, lon: lon, country: rec[3]}
+		names = append(names, regexp.QuoteMeta(rec[0]))
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("parse ports: no ports")
+	}
+
+	// Longer names first so "Port of Long Beach" is never cut short by a
+	// shorter name sharing its prefix
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	g.pattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join(names, "|") + `)\b`)
+
+	return g, nil
+}
+
+// find returns the first port named in text
+func (g *gazetteer) find(text string) (port, bool) {
+	match := g.pattern.FindString(text)
+	if match == "" {
+		return port{}, false
+	}
+	p, ok := g.ports[strings.ToLower(match)]
+	return p, ok
+}
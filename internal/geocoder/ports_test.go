@@ -0,0 +1,39 @@
+package geocoder
+
+import "testing"
+
+func TestGazetteer_Find(t *testing.T) {
+	ports := loadGazetteer()
+
+	p, ok := ports.find("Fog delays berthing at PORT OF ROTTERDAM terminals")
+	if !ok {
+		t.Fatal("Expected Port of Rotterdam to be found")
+	}
+	if p.name != "Port of Rotterdam" || p.country != "NL" || p.lat == 0 || p.lon == 0 {
+		t.Errorf("Unexpected port: %+v", p)
+	}
+
+	if _, ok := ports.find("Port of Rotterdamned"); ok {
+		t.Error("Expected names to match on word boundaries only")
+	}
+}
+
+func TestParsePorts_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"no ports", "name,latitude,longitude,country\n"},
+		{"bad latitude", "name,latitude,longitude,country\nPort of X,north,4.1,NL\n"},
+		{"unknown country", "name,latitude,longitude,country\nPort of X,51.9,4.1,XX\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePorts([]byte(tt.data), loadCountries()); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package geocoder
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPlaceNotFound is returned by a Provider when a place cannot be resolved
+var ErrPlaceNotFound = errors.New("place not found")
+
+// Provider resolves a place name to coordinates
+type Provider interface {
+	Lookup(ctx context.Context, place string) (lat, lon float64, country, region string, err error)
+}
+
+// CachedProvider wraps a Provider with an in-memory LRU cache keyed by place.
+// Only successful lookups are cached.
+type CachedProvider struct {
+	provider Provider
+	size     int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	place   string
+	lat     float64
+	lon     float64
+	country string
+	region  string
+}
+
+// NewCachedProvider creates a CachedProvider holding up to size places
+func NewCachedProvider(provider Provider, size int) *CachedProvider {
+	if size < 1 {
+		size = 1
+	}
+	return &CachedProvider{
+		provider: provider,
+		size:     size,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Lookup returns the cached result for place or queries the wrapped provider
+func (c *CachedProvider) Lookup(ctx context.Context, place string) (float64, float64, string, string, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[place]; ok {
+		c.order.MoveToFront(elem)
+		e := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return e.lat, e.lon, e.country, e.region, nil
+	}
+	c.mu.Unlock()
+
+	lat, lon, country, region, err := c.provider.Lookup(ctx, place)
+	if err != nil {
+		return 0, 0, "", "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[place]; ok {
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[place] = c.order.PushFront(&cacheEntry{
+			place:   place,
+			lat:     lat,
+			lon:     lon,
+			country: country,
+			region:  region,
+		})
+		if c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).place)
+		}
+	}
+
+	return lat, lon, country, region, nil
+}
+
+// Len returns the number of cached places
+func (c *CachedProvider) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,87 @@
+package geocoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type countingProvider struct {
+	calls map[string]int
+	err   error
+}
+
+func (p *countingProvider) Lookup(ctx context.Context, place string) (float64, float64, string, string, error) {
+	if p.calls == nil {
+		p.calls = make(map[string]int)
+	}
+	p.calls[place]++
+	if p.err != nil {
+		return 0, 0, "", "", p.err
+	}
+	return 1, 2, "Country " + place, "Region", nil
+}
+
+func TestCachedProvider_CachesLookups(t *testing.T) {
+	inner := &countingProvider{}
+	cache := NewCachedProvider(inner, 10)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		lat, lon, country, _, err := cache.Lookup(ctx, "Rotterdam")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if lat != 1 || lon != 2 || country != "Country Rotterdam" {
+			t.Errorf("Unexpected result: %v %v %s", lat, lon, country)
+		}
+	}
+
+	if inner.calls["Rotterdam"] != 1 {
+		t.Errorf("Expected 1 upstream lookup, got %d", inner.calls["Rotterdam"])
+	}
+}
+
+func TestCachedProvider_EvictsLeastRecentlyUsed(t *testing.T) {
+	inner := &countingProvider{}
+	cache := NewCachedProvider(inner, 2)
+	ctx := context.Background()
+
+	cache.Lookup(ctx, "a")
+	cache.Lookup(ctx, "b")
+	cache.Lookup(ctx, "a") // a is now most recent
+	cache.Lookup(ctx, "c") // evicts b
+
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 cached places, got %d", cache.Len())
+	}
+
+	cache.Lookup(ctx, "a")
+	cache.Lookup(ctx, "b")
+
+	if inner.calls["a"] != 1 {
+		t.Errorf("Expected a to stay cached, got %d lookups", inner.calls["a"])
+	}
+	if inner.calls["b"] != 2 {
+		t.Errorf("Expected b to be evicted and looked up again, got %d lookups", inner.calls["b"])
+	}
+}
+
+func TestCachedProvider_DoesNotCacheErrors(t *testing.T) {
+	inner := &countingProvider{err: errors.New("unavailable")}
+	cache := NewCachedProvider(inner, 10)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, _, _, _, err := cache.Lookup(ctx, "Oakland"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	}
+
+	if inner.calls["Oakland"] != 2 {
+		t.Errorf("Expected errors to be retried upstream, got %d lookups", inner.calls["Oakland"])
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected empty cache, got %d", cache.Len())
+	}
+}
@@ -2,27 +2,35 @@ package logger
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
 )
 
 var defaultLogger *slog.Logger
 
-// Init initializes the global logger
+// Init initializes the global logger, writing to stdout
 func Init(level, format string) {
+	InitWriter(os.Stdout, level, format)
+}
+
+// InitWriter initializes the global logger, writing to w. Values of
+// sensitive keys are masked; see Redact.
+func InitWriter(w io.Writer, level, format string) {
 	var handler slog.Handler
 
 	logLevel := parseLevel(level)
 	opts := &slog.HandlerOptions{
-		Level:     logLevel,
-		AddSource: true,
+		Level:       logLevel,
+		AddSource:   true,
+		ReplaceAttr: redactAttr,
 	}
 
 	switch format {
 	case "json":
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(w, opts)
 	default:
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		handler = slog.NewTextHandler(w, opts)
 	}
 
 	defaultLogger = slog.New(handler)
@@ -50,9 +58,22 @@ func WithContext(ctx context.Context) *slog.Logger {
 	return defaultLogger.With(
 		"request_id", ctx.Value("request_id"),
 		"trace_id", ctx.Value("trace_id"),
+		"span_id", ctx.Value("span_id"),
 	)
 }
 
+// WithTrace returns a copy of ctx carrying the trace and span IDs that
+// WithContext logs. An empty ID is left unset.
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	if traceID != "" {
+		ctx = context.WithValue(ctx, "trace_id", traceID)
+	}
+	if spanID != "" {
+		ctx = context.WithValue(ctx, "span_id", spanID)
+	}
+	return ctx
+}
+
 // Info logs an info message
 func Info(msg string, args ...any) {
 	defaultLogger.Info(msg, args...)
@@ -40,6 +40,12 @@ func TestInitAndWithContext(t *testing.T) {
 		t.Fatalf("WithContext returned nil")
 	}
 
+	// WithTrace leaves empty IDs unset
+	traced := WithTrace(context.Background(), "trace-abc", "")
+	if traced.Value("trace_id") != "trace-abc" || traced.Value("span_id") != nil {
+		t.Errorf("Expected only trace_id set, got trace_id=%v span_id=%v", traced.Value("trace_id"), traced.Value("span_id"))
+	}
+
 	// Exercise logging methods to ensure they don't panic
 	Info("info message", "k", "v")
 	Warn("warn message")
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive keys in logs
+const Redacted = "REDACTED"
+
+// sensitiveKeys are header, query parameter and log attribute names whose
+// values are never logged, normalised by normaliseKey
+var sensitiveKeys = map[string]bool{
+	"authorization":       true,
+	"proxy_authorization": true,
+	"cookie":              true,
+	"set_cookie":          true,
+	"api_key":             true,
+	"apikey":              true,
+	"x_api_key":           true,
+	"stripe_signature":    true,
+	"x_signature":         true,
+	"password":            true,
+	"secret":              true,
+	"token":               true,
+	"access_token":        true,
+}
+
+// normaliseKey lowercases key and treats dashes as underscores, so that
+// X-API-Key and x_api_key match
+func normaliseKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
+
+// IsSensitive reports whether values under key must not be logged
+func IsSensitive(key string) bool {
+	return sensitiveKeys[normaliseKey(key)]
+}
+
+// Redact returns value, or Redacted if key is sensitive
+func Redact(key, value string) string {
+	if value != "" && IsSensitive(key) {
+		return Redacted
+	}
+	return value
+}
+
+// RedactHeaders returns h as a flat map for logging, with sensitive values
+// masked
+func RedactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for key, values := range h {
+		out[key] = Redact(key, strings.Join(values, ", "))
+	}
+	return out
+}
+
+// RedactQuery returns the encoded query string with sensitive values masked
+func RedactQuery(q url.Values) string {
+	masked := make(url.Values, len(q))
+	for key, values := range q {
+		for _, v := range values {
+			masked.Add(key, Redact(key, v))
+		}
+	}
+	return masked.Encode()
+}
+
+// RedactURL returns raw with sensitive query values and any password
+// masked. Unparseable URLs are returned whole as Redacted.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), Redacted)
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = RedactQuery(u.Query())
+	}
+	return u.String()
+}
+
+// redactAttr masks log attributes with sensitive keys, whatever the caller
+// passed
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindGroup && IsSensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
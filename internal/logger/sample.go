@@ -0,0 +1,28 @@
+package logger
+
+import "sync/atomic"
+
+// Sampler decides whether to emit a log line that may be dropped under load
+type Sampler interface {
+	Sample() bool
+}
+
+// everyN samples the first of every n calls
+type everyN struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+// EveryN returns a Sampler that keeps one in every n calls. n <= 1 keeps
+// every call.
+func EveryN(n int) Sampler {
+	if n <= 1 {
+		return nil
+	}
+	return &everyN{n: uint64(n)}
+}
+
+// Sample reports whether this call should be logged
+func (s *everyN) Sample() bool {
+	return s.count.Add(1)%s.n == 1
+}
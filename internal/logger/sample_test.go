@@ -0,0 +1,27 @@
+package logger
+
+import "testing"
+
+func TestEveryN(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{0, 10},
+		{1, 10},
+		{3, 4},
+		{10, 1},
+	}
+	for _, tt := range tests {
+		s := EveryN(tt.n)
+		got := 0
+		for i := 0; i < 10; i++ {
+			if s == nil || s.Sample() {
+				got++
+			}
+		}
+		if got != tt.want {
+			t.Errorf("EveryN(%d) kept %d of 10, want %d", tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -12,6 +13,7 @@ type Metrics interface {
 	RecordPipelineRun(source string, duration time.Duration)
 	SetDBConnectionsActive(count float64)
 	RecordDBQuery(operation, status string)
+	RecordWebhookDelivery(status string)
 	Handler() http.Handler
 }
 
@@ -24,15 +26,27 @@ func (m *NoOpMetrics) RecordAlertProcessed(source, status string)              {
 func (m *NoOpMetrics) RecordPipelineRun(source string, duration time.Duration) {}
 func (m *NoOpMetrics) SetDBConnectionsActive(count float64)                    {}
 func (m *NoOpMetrics) RecordDBQuery(operation, status string)                  {}
+func (m *NoOpMetrics) RecordWebhookDelivery(status string)                     {}
 func (m *NoOpMetrics) Handler() http.Handler                                   { return http.NotFoundHandler() }
 
 // Global metrics instance
 var globalMetrics Metrics = &NoOpMetrics{}
 
-// Init initializes metrics (no-op for now, can be extended with Prometheus)
-func Init() {
-	// For now, keep using no-op metrics
-	// In a full implementation, this would initialize Prometheus metrics
+// Init switches the global metrics instance to Prometheus. Each call
+// builds a fresh registry, so calling it again replaces the previous one.
+// The global instance is left unchanged on error.
+func Init(info BuildInfo) error {
+	m, err := NewPrometheusMetrics(info)
+	if err != nil {
+		return fmt.Errorf("init prometheus metrics: %w", err)
+	}
+	globalMetrics = m
+	return nil
+}
+
+// Set replaces the global metrics instance
+func Set(m Metrics) {
+	globalMetrics = m
 }
 
 // Handler returns the metrics handler
@@ -64,3 +78,8 @@ func SetDBConnectionsActive(count float64) {
 func RecordDBQuery(operation, status string) {
 	globalMetrics.RecordDBQuery(operation, status)
 }
+
+// RecordWebhookDelivery records the outcome of an outbound webhook delivery
+func RecordWebhookDelivery(status string) {
+	globalMetrics.RecordWebhookDelivery(status)
+}
@@ -14,6 +14,7 @@ func TestNoOpMetricsAndDelegates(t *testing.T) {
 	m.RecordPipelineRun("src", time.Millisecond)
 	m.SetDBConnectionsActive(1)
 	m.RecordDBQuery("exec", "ok")
+	m.RecordWebhookDelivery("success")
 	h := m.Handler()
 	if h == nil {
 		t.Fatalf("NoOp handler is nil")
@@ -25,6 +26,7 @@ func TestNoOpMetricsAndDelegates(t *testing.T) {
 	RecordPipelineRun("src", time.Millisecond)
 	SetDBConnectionsActive(2)
 	RecordDBQuery("query", "ok")
+	RecordWebhookDelivery("failure")
 
 	// Handler should be NotFound
 	req, _ := http.NewRequest("GET", "/metrics", nil)
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusMetrics implements Metrics using Prometheus collectors
+type PrometheusMetrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	alertsProcessed     *prometheus.CounterVec
+	pipelineRunDuration *prometheus.HistogramVec
+	dbConnectionsActive prometheus.Gauge
+	dbQueries           *prometheus.CounterVec
+	webhookDeliveries   *prometheus.CounterVec
+}
+
+// BuildInfo identifies the running build. It is exported as the constant
+// build_info gauge so dashboards can track deployed versions.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildTime string
+}
+
+// NewPrometheusMetrics creates Prometheus metrics registered on their own registry.
+// Endpoint labels are expected to be route patterns rather than raw paths so
+// the number of series stays bounded.
+func NewPrometheusMetrics(info BuildInfo) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests.",
+		}, []string{"method", "endpoint", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		alertsProcessed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alerts_processed_total",
+			Help: "Total number of pipeline source runs by outcome.",
+		}, []string{"source", "status"}),
+		pipelineRunDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "pipeline_run_duration_seconds",
+			Help:    "Pipeline source run duration in seconds.",
+			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		}, []string{"source"}),
+		dbConnectionsActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "db_connections_active",
+			Help: "Number of database connections currently acquired.",
+		}),
+		dbQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_queries_total",
+			Help: "Total number of database operations.",
+		}, []string{"operation", "status"}),
+		webhookDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "webhook_deliveries_total",
+			Help: "Total number of outbound webhook deliveries by outcome.",
+		}, []string{"status"}),
+	}
+
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "build_info",
+		Help: "Build information of the running binary; always 1.",
+		ConstLabels: prometheus.Labels{
+			"version":    info.Version,
+			"commit":     info.Commit,
+			"build_time": info.BuildTime,
+		},
+	})
+	buildInfo.Set(1)
+
+	for _, c := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.alertsProcessed,
+		m.pipelineRunDuration,
+		m.dbConnectionsActive,
+		m.dbQueries,
+		m.webhookDeliveries,
+		buildInfo,
+	} {
+		if err := m.registry.Register(c); err != nil {
+			return nil, fmt.Errorf("register collector: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) RecordHTTPRequest(method, endpoint string, statusCode int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
+	m.httpRequestDuration.WithLabelValues(method, endpoint).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMetrics) RecordAlertProcessed(source, status string) {
+	m.alertsProcessed.WithLabelValues(source, status).Inc()
+}
+
+func (m *PrometheusMetrics) RecordPipelineRun(source string, duration time.Duration) {
+	m.pipelineRunDuration.WithLabelValues(source).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMetrics) SetDBConnectionsActive(count float64) {
+	m.dbConnectionsActive.Set(count)
+}
+
+func (m *PrometheusMetrics) RecordDBQuery(operation, status string) {
+	m.dbQueries.WithLabelValues(operation, status).Inc()
+}
+
+func (m *PrometheusMetrics) RecordWebhookDelivery(status string) {
+	m.webhookDeliveries.WithLabelValues(status).Inc()
+}
+
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
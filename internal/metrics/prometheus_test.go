@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetrics_Handler(t *testing.T) {
+	m, err := NewPrometheusMetrics(BuildInfo{Version: "1.2.3", Commit: "abc123", BuildTime: "2024-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatalf("NewPrometheusMetrics: %v", err)
+	}
+	m.RecordHTTPRequest("GET", "/v1/alerts/{id}", 200, 15*time.Millisecond)
+	m.RecordAlertProcessed("src", "success")
+	m.RecordPipelineRun("src", time.Second)
+	m.SetDBConnectionsActive(3)
+	m.RecordDBQuery("query", "success")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != 200 {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+
+	for _, want := range []string{
+		`http_requests_total{endpoint="/v1/alerts/{id}",method="GET",status="200"} 1`,
+		`http_request_duration_seconds_count{endpoint="/v1/alerts/{id}",method="GET"} 1`,
+		`alerts_processed_total{source="src",status="success"} 1`,
+		`pipeline_run_duration_seconds_count{source="src"} 1`,
+		`db_connections_active 3`,
+		`db_queries_total{operation="query",status="success"} 1`,
+		`build_info{build_time="2024-01-01T00:00:00Z",commit="abc123",version="1.2.3"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in metrics output", want)
+		}
+	}
+}
+
+func TestInit_SwapsGlobalMetrics(t *testing.T) {
+	prev := globalMetrics
+	defer func() { globalMetrics = prev }()
+
+	// Init may run more than once, e.g. from tests; each call must succeed
+	for i := 0; i < 2; i++ {
+		if err := Init(BuildInfo{Version: "v1", Commit: "c1", BuildTime: "t1"}); err != nil {
+			t.Fatalf("Init call %d: %v", i+1, err)
+		}
+	}
+	if _, ok := globalMetrics.(*PrometheusMetrics); !ok {
+		t.Fatalf("expected PrometheusMetrics after Init, got %T", globalMetrics)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != 200 {
+		t.Errorf("expected 200 from metrics handler, got %d", rec.Code)
+	}
+	want := `build_info{build_time="t1",commit="c1",version="v1"} 1`
+	if out := rec.Body.String(); strings.Count(out, want) != 1 {
+		t.Errorf("expected one %q in metrics output", want)
+	}
+}
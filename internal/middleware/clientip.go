@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP replaces r.RemoteAddr with the client address reported by
+// X-Forwarded-For or X-Real-IP, but only for requests arriving from one of
+// trustedProxies. Unlike chi's RealIP, a client connecting directly cannot
+// pick its own address by sending the headers.
+func RealIP(trustedProxies ...netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := parseAddr(r.RemoteAddr); ok && isTrusted(peer, trustedProxies) {
+				r.RemoteAddr = clientIP(r, trustedProxies)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address of the client behind r. Forwarding headers
+// are only honored when the peer is a trusted proxy, and X-Forwarded-For is
+// read from the nearest hop back, skipping trusted proxies, so a client
+// cannot prepend a fake address. Addresses are normalized, so IPv4-mapped
+// IPv6 and bracketed or zoned forms of one address share a key.
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	addr, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !isTrusted(addr, trustedProxies) {
+		return addr.String()
+	}
+
+	hops := forwardedFor(r.Header)
+	if len(hops) == 0 {
+		if real, ok := parseAddr(r.Header.Get("X-Real-IP")); ok {
+			return real.String()
+		}
+		return addr.String()
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(hops[i])
+		if !ok {
+			// A malformed hop can't be attributed; stop at the last proxy
+			break
+		}
+		addr = hop
+		if !isTrusted(hop, trustedProxies) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+// forwardedFor returns the X-Forwarded-For hops across all header lines,
+// client first
+func forwardedFor(h http.Header) []string {
+	var hops []string
+	for _, value := range h.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// parseAddr parses an address with or without a port, dropping any zone
+// and unmapping IPv4-mapped IPv6
+func parseAddr(s string) (netip.Addr, bool) {
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr().Unmap().WithZone(""), true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
+
+// isTrusted reports whether addr is in one of prefixes
+func isTrusted(addr netip.Addr, prefixes []netip.Prefix) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8:ffff::/48"),
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{"IPv4 with port", "192.168.1.1:12345", nil, "", "192.168.1.1"},
+		{"IPv6 with port", "[2001:db8::1]:443", nil, "", "2001:db8::1"},
+		{"Bare IPv6 set by RealIP", "2001:db8::1", nil, "", "2001:db8::1"},
+		{"Bracketed IPv6 without port", "[2001:db8::1]", nil, "", "2001:db8::1"},
+		{"Zoned IPv6", "[fe80::1%eth0]:80", nil, "", "fe80::1"},
+		{"IPv4-mapped IPv6", "[::ffff:192.168.1.1]:80", nil, "", "192.168.1.1"},
+		{"Unparseable address", "pipe", nil, "", "pipe"},
+		{"Forwarded from untrusted peer", "203.0.113.9:5000", []string{"198.51.100.7"}, "", "203.0.113.9"},
+		{"Real IP from untrusted peer", "203.0.113.9:5000", nil, "198.51.100.7", "203.0.113.9"},
+		{"Forwarded from trusted proxy", "10.0.0.2:5000", []string{"198.51.100.7"}, "", "198.51.100.7"},
+		{"Forwarded through trusted IPv6 proxy", "[2001:db8:ffff::2]:5000", []string{"2001:db8:1::7"}, "", "2001:db8:1::7"},
+		{"Spoofed hop before the real client", "10.0.0.2:5000", []string{"1.2.3.4, 198.51.100.7"}, "", "198.51.100.7"},
+		{"Chain of trusted proxies", "10.0.0.2:5000", []string{"198.51.100.7, 10.0.0.3", "10.0.0.4"}, "", "198.51.100.7"},
+		{"Only trusted hops", "10.0.0.2:5000", []string{"10.0.0.3"}, "", "10.0.0.3"},
+		{"Malformed hop", "10.0.0.2:5000", []string{"198.51.100.7, garbage"}, "", "10.0.0.2"},
+		{"Real IP from trusted proxy", "10.0.0.2:5000", nil, "198.51.100.7", "198.51.100.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := clientIP(req, trusted); got != tt.want {
+				t.Errorf("Expected client IP %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	trusted := netip.MustParsePrefix("10.0.0.0/8")
+
+	var got string
+	handler := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{"Trusted proxy", "10.0.0.2:5000", "198.51.100.7"},
+		{"Untrusted peer", "203.0.113.9:5000", "203.0.113.9:5000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("Expected RemoteAddr %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRateLimit_TrustedProxies(t *testing.T) {
+	handler := RateLimit(1, netip.MustParsePrefix("10.0.0.0/8"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(remoteAddr, forwarded string) int {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Clients behind the load balancer are limited separately
+	if code := request("10.0.0.2:5000", "198.51.100.7"); code != http.StatusOK {
+		t.Errorf("Expected first client to be allowed, got %d", code)
+	}
+	if code := request("10.0.0.2:5001", "198.51.100.8"); code != http.StatusOK {
+		t.Errorf("Expected second client behind the proxy to be allowed, got %d", code)
+	}
+	if code := request("10.0.0.2:5002", "198.51.100.7"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected first client's second request to be limited, got %d", code)
+	}
+
+	// An untrusted client can't dodge the limit by rotating the header
+	if code := request("203.0.113.9:5000", "1.1.1.1"); code != http.StatusOK {
+		t.Errorf("Expected untrusted client to be allowed once, got %d", code)
+	}
+	if code := request("203.0.113.9:5001", "2.2.2.2"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected untrusted client to be limited despite a new header, got %d", code)
+	}
+}
@@ -2,46 +2,71 @@ package middleware
 
 import (
 	"context"
-	"net"
+	"encoding/json"
+	"math"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	apperrors "github.com/rajasatyajit/SupplyChain/internal/errors"
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
 	"github.com/rajasatyajit/SupplyChain/internal/metrics"
 )
 
 // Logging provides structured logging for HTTP requests
 func Logging(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
+	return SampledLogging(nil)(next)
+}
 
-		// Add request ID to context
-		requestID := middleware.GetReqID(r.Context())
-		ctx := context.WithValue(r.Context(), "request_id", requestID)
-		r = r.WithContext(ctx)
+// SampledLogging is Logging that only logs the requests chosen by s among
+// those that succeed (status below 400). Client and server errors are
+// always logged. A nil sampler logs every request.
+func SampledLogging(s logger.Sampler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
 
-		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			// Add request ID to context
+			requestID := middleware.GetReqID(r.Context())
+			ctx := context.WithValue(r.Context(), "request_id", requestID)
+			traceID, spanID := traceFromRequest(r)
+			ctx = logger.WithTrace(ctx, traceID, spanID)
+			r = r.WithContext(ctx)
 
-		defer func() {
-			duration := time.Since(start)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
-			logger.WithContext(ctx).Info("HTTP request",
-				"method", r.Method,
-				"path", r.URL.Path,
-				"status", ww.Status(),
-				"duration_ms", duration.Milliseconds(),
-				"bytes", ww.BytesWritten(),
-				"remote_addr", r.RemoteAddr,
-				"user_agent", r.UserAgent(),
-			)
-		}()
+			defer func() {
+				status := ww.Status()
+				if s != nil && status < http.StatusBadRequest && !s.Sample() {
+					return
+				}
 
-		next.ServeHTTP(ww, r)
-	})
+				duration := time.Since(start)
+
+				logger.WithContext(ctx).Info("HTTP request",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"query", logger.RedactQuery(r.URL.Query()),
+					"status", status,
+					"duration_ms", duration.Milliseconds(),
+					"bytes", ww.BytesWritten(),
+					"remote_addr", r.RemoteAddr,
+					"user_agent", r.UserAgent(),
+				)
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
 }
 
-// Metrics records HTTP metrics
+// Metrics records HTTP metrics, labelled by chi route pattern to keep
+// cardinality bounded
 func Metrics(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -51,7 +76,7 @@ func Metrics(next http.Handler) http.Handler {
 			duration := time.Since(start)
 			metrics.RecordHTTPRequest(
 				r.Method,
-				r.URL.Path,
+				routePattern(r),
 				ww.Status(),
 				duration,
 			)
@@ -61,6 +86,17 @@ func Metrics(next http.Handler) http.Handler {
 	})
 }
 
+// routePattern returns the matched chi route pattern, e.g. /v1/alerts/{id}.
+// The pattern is only complete once routing has finished.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unknown"
+}
+
 // Security adds security headers
 func Security(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -76,70 +112,205 @@ func Security(next http.Handler) http.Handler {
 	})
 }
 
-// RateLimit provides rate limiting (basic implementation)
-func RateLimit(requestsPerMinute int) func(http.Handler) http.Handler {
+// RateLimit provides rate limiting (basic implementation). Clients are keyed
+// by address, honoring forwarding headers only from trustedProxies. A
+// requestsPerMinute below 1 is raised to 1.
+func RateLimit(requestsPerMinute int, trustedProxies ...netip.Prefix) func(http.Handler) http.Handler {
 	// This is a simple in-memory rate limiter
 	// For production, consider using Redis-based rate limiting
-	clients := make(map[string][]time.Time)
+	limiter := newRateLimiter(requestsPerMinute, time.Minute)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			clientIP := r.RemoteAddr
-			if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
-				clientIP = host
-			}
-			now := time.Now()
-
-			// Clean old entries
-			if timestamps, exists := clients[clientIP]; exists {
-				var validTimestamps []time.Time
-				for _, ts := range timestamps {
-					if now.Sub(ts) < time.Minute {
-						validTimestamps = append(validTimestamps, ts)
-					}
-				}
-				clients[clientIP] = validTimestamps
-			}
-
 			// Check rate limit
-			if len(clients[clientIP]) >= requestsPerMinute {
-				w.Header().Set("Retry-After", "60")
-				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			allowed, remaining, reset := limiter.allow(clientIP(r, trustedProxies), time.Now())
+			resetSeconds := max(int(math.Ceil(reset.Seconds())), 1)
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(resetSeconds))
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(resetSeconds))
+				writeError(w, r, http.StatusTooManyRequests, apperrors.CodeRateLimited, "Rate limit exceeded")
 				return
 			}
 
-			// Add current request
-			clients[clientIP] = append(clients[clientIP], now)
-
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-// CORS handles CORS headers
-func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+// errorResponse mirrors api.ErrorResponse for errors raised before a
+// request reaches the API handlers
+type errorResponse struct {
+	Error     string         `json:"error"`
+	Code      apperrors.Code `json:"code"`
+	Message   string         `json:"message,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	RequestID string         `json:"request_id,omitempty"`
+}
+
+// writeError writes a JSON error in the API's error format
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, code apperrors.Code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorResponse{
+		Error:     http.StatusText(statusCode),
+		Code:      code,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+		RequestID: middleware.GetReqID(r.Context()),
+	})
+}
+
+// rateLimiter tracks request timestamps per client over a sliding window
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string][]time.Time
+	lastSweep time.Time
+}
+
+// newRateLimiter allows limit requests per window, at least one, since
+// allow reports the reset time from the oldest counted request
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   max(limit, 1),
+		window:  window,
+		clients: make(map[string][]time.Time),
+	}
+}
+
+// allow records a request for key at now and reports whether it is within
+// the limit, how many requests remain, and how long until the oldest
+// counted request leaves the window
+func (l *rateLimiter) allow(key string, now time.Time) (bool, int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop clients with no requests in the window once per window
+	if now.Sub(l.lastSweep) >= l.window {
+		l.sweep(now)
+	}
+
+	// Clean old entries
+	timestamps := l.prune(l.clients[key], now)
+	allowed := len(timestamps) < l.limit
+	if allowed {
+		// Add current request
+		timestamps = append(timestamps, now)
+	}
+	l.clients[key] = timestamps
+
+	remaining := l.limit - len(timestamps)
+	if remaining < 0 {
+		remaining = 0
+	}
+	reset := l.window - now.Sub(timestamps[0])
+
+	return allowed, remaining, reset
+}
+
+// prune returns the timestamps still inside the window
+func (l *rateLimiter) prune(timestamps []time.Time, now time.Time) []time.Time {
+	valid := timestamps[:0]
+	for _, ts := range timestamps {
+		if now.Sub(ts) < l.window {
+			valid = append(valid, ts)
+		}
+	}
+	return valid
+}
+
+// sweep removes clients whose requests have all left the window
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, timestamps := range l.clients {
+		if valid := l.prune(timestamps, now); len(valid) > 0 {
+			l.clients[key] = valid
+		} else {
+			delete(l.clients, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// corsMethods are the methods a preflight may report for a route
+var corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// CORSOption configures the CORS middleware
+type CORSOption func(*corsPolicy)
+
+// corsPolicy is the CORS middleware configuration
+type corsPolicy struct {
+	routes  chi.Routes
+	headers []string
+}
+
+// WithRoutes makes CORS advertise only the methods routes registers for the
+// requested path, instead of every method
+func WithRoutes(routes chi.Routes) CORSOption {
+	return func(p *corsPolicy) {
+		p.routes = routes
+	}
+}
+
+// WithAllowedHeaders allows request headers beyond Content-Type and
+// Authorization
+func WithAllowedHeaders(headers ...string) CORSOption {
+	return func(p *corsPolicy) {
+		p.headers = append(p.headers, headers...)
+	}
+}
+
+// allowedMethods lists the methods the policy advertises for path
+func (p *corsPolicy) allowedMethods(path string) string {
+	if p.routes == nil {
+		return "GET, POST, PUT, DELETE, OPTIONS"
+	}
+
+	var methods []string
+	for _, method := range corsMethods {
+		if p.routes.Match(chi.NewRouteContext(), method, path) {
+			methods = append(methods, method)
+		}
+	}
+	return strings.Join(append(methods, http.MethodOptions), ", ")
+}
+
+// CORS handles CORS headers. Only requests from an allowed origin get any
+// CORS headers; "*" allows every origin. Preflight requests are answered
+// here without reaching the router.
+func CORS(allowedOrigins []string, opts ...CORSOption) func(http.Handler) http.Handler {
+	policy := &corsPolicy{headers: []string{"Content-Type", "Authorization"}}
+	for _, opt := range opts {
+		opt(policy)
+	}
+	allowHeaders := strings.Join(policy.headers, ", ")
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
 			// Check if origin is allowed
 			allowed := false
-			for _, allowedOrigin := range allowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					allowed = true
-					break
+			if origin != "" {
+				for _, allowedOrigin := range allowedOrigins {
+					if allowedOrigin == "*" || allowedOrigin == origin {
+						allowed = true
+						break
+					}
 				}
 			}
 
+			w.Header().Add("Vary", "Origin")
 			if allowed {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", policy.allowedMethods(r.URL.Path))
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+				w.Header().Set("Access-Control-Max-Age", "86400")
 			}
 
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			w.Header().Set("Access-Control-Max-Age", "86400")
-
-			if r.Method == "OPTIONS" {
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
 				w.WriteHeader(http.StatusOK)
 				return
 			}
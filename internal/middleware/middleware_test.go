@@ -1,14 +1,21 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/metrics"
 )
 
 func TestLogging(t *testing.T) {
@@ -47,6 +54,54 @@ func TestLogging(t *testing.T) {
 	}
 }
 
+func TestLogging_RedactsQuery(t *testing.T) {
+	var buf bytes.Buffer
+	logger.InitWriter(&buf, "info", "json")
+	t.Cleanup(func() { logger.Init("error", "text") })
+
+	handler := Logging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/alerts?severity=high&api_key=s3cret", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	out := buf.String()
+	if strings.Contains(out, "s3cret") {
+		t.Errorf("Expected credentials to be masked, got %s", out)
+	}
+	if !strings.Contains(out, "api_key=REDACTED&severity=high") {
+		t.Errorf("Expected redacted query in log, got %s", out)
+	}
+}
+
+func TestSampledLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger.InitWriter(&buf, "info", "json")
+	t.Cleanup(func() { logger.Init("error", "text") })
+
+	handler := SampledLogging(logger.EveryN(4))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+	}))
+
+	for i := 0; i < 8; i++ {
+		for _, status := range []string{"200", "500"} {
+			req := httptest.NewRequest("GET", "/test?status="+status, nil)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	out := buf.String()
+	if got := strings.Count(out, `"status":200`); got != 2 {
+		t.Errorf("Expected 2 of 8 successful requests logged, got %d", got)
+	}
+	if got := strings.Count(out, `"status":500`); got != 8 {
+		t.Errorf("Expected all 8 failed requests logged, got %d", got)
+	}
+}
+
 func TestMetrics(t *testing.T) {
 	// Create a test handler
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -74,6 +129,47 @@ func TestMetrics(t *testing.T) {
 	}
 }
 
+type recordingMetrics struct {
+	metrics.NoOpMetrics
+	endpoints []string
+}
+
+func (m *recordingMetrics) RecordHTTPRequest(method, endpoint string, statusCode int, duration time.Duration) {
+	m.endpoints = append(m.endpoints, endpoint)
+}
+
+func TestMetrics_UsesRoutePattern(t *testing.T) {
+	rec := &recordingMetrics{}
+	metrics.Set(rec)
+	defer metrics.Set(&metrics.NoOpMetrics{})
+
+	r := chi.NewRouter()
+	r.Use(Metrics)
+	r.Get("/v1/alerts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, path := range []string{"/v1/alerts/abc", "/v1/alerts/def"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	if len(rec.endpoints) != 2 {
+		t.Fatalf("Expected 2 recorded requests, got %d", len(rec.endpoints))
+	}
+	for _, endpoint := range rec.endpoints {
+		if endpoint != "/v1/alerts/{id}" {
+			t.Errorf("Expected endpoint label /v1/alerts/{id}, got %s", endpoint)
+		}
+	}
+
+	// Without a chi router there is no route pattern
+	Metrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/raw/path", nil))
+	if got := rec.endpoints[len(rec.endpoints)-1]; got != "unknown" {
+		t.Errorf("Expected endpoint label unknown, got %s", got)
+	}
+}
+
 func TestSecurity(t *testing.T) {
 	// Create a test handler
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -160,6 +256,97 @@ func TestRateLimit(t *testing.T) {
 	if retryAfter != "60" {
 		t.Errorf("Expected Retry-After header '60', got %s", retryAfter)
 	}
+
+	// The rejection carries the rate limit headers and a JSON error
+	if got := w3.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("Expected X-RateLimit-Remaining 0, got %s", got)
+	}
+	if got := w3.Header().Get("X-RateLimit-Reset"); got != retryAfter {
+		t.Errorf("Expected X-RateLimit-Reset to match Retry-After, got %s", got)
+	}
+	if ct := w3.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %s", ct)
+	}
+	var body struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.NewDecoder(w3.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON body: %v", err)
+	}
+	if body.Code != "rate_limited" || body.Error != "Too Many Requests" {
+		t.Errorf("Unexpected error body: %+v", body)
+	}
+}
+
+func TestRateLimit_Headers(t *testing.T) {
+	handler := RateLimit(3)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i, expected := range []string{"2", "1", "0", "0"} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.2:12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != expected {
+			t.Errorf("Request %d: expected X-RateLimit-Remaining %s, got %s", i+1, expected, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "3" {
+			t.Errorf("Request %d: expected X-RateLimit-Limit 3, got %s", i+1, got)
+		}
+		reset, err := strconv.Atoi(w.Header().Get("X-RateLimit-Reset"))
+		if err != nil || reset < 1 || reset > 60 {
+			t.Errorf("Request %d: expected X-RateLimit-Reset in seconds, got %q", i+1, w.Header().Get("X-RateLimit-Reset"))
+		}
+	}
+}
+
+func TestRateLimiter_NonPositiveLimit(t *testing.T) {
+	for _, limit := range []int{0, -5} {
+		limiter := newRateLimiter(limit, time.Minute)
+		now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+		if allowed, _, _ := limiter.allow("10.0.0.1", now); !allowed {
+			t.Errorf("limit %d: expected first request to be allowed", limit)
+		}
+		allowed, remaining, reset := limiter.allow("10.0.0.1", now.Add(time.Second))
+		if allowed || remaining != 0 || reset != 59*time.Second {
+			t.Errorf("limit %d: expected second request to be limited, got allowed=%v remaining=%d reset=%v", limit, allowed, remaining, reset)
+		}
+	}
+}
+
+func TestRateLimiter_SweepsStaleClients(t *testing.T) {
+	limiter := newRateLimiter(2, time.Minute)
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 100; i++ {
+		limiter.allow(fmt.Sprintf("10.0.0.%d", i), start)
+	}
+	if len(limiter.clients) != 100 {
+		t.Fatalf("Expected 100 tracked clients, got %d", len(limiter.clients))
+	}
+
+	// A request after the window reclaims every idle client
+	if allowed, _, _ := limiter.allow("10.0.1.1", start.Add(2*time.Minute)); !allowed {
+		t.Error("Expected request from new client to be allowed")
+	}
+	if len(limiter.clients) != 1 {
+		t.Errorf("Expected stale clients to be reclaimed, got %d tracked", len(limiter.clients))
+	}
+
+	// Active clients survive a sweep and stay limited
+	now := start.Add(2 * time.Minute)
+	limiter.allow("10.0.1.1", now)
+	if allowed, _, _ := limiter.allow("10.0.1.1", now.Add(time.Second)); allowed {
+		t.Error("Expected third request within the window to be limited")
+	}
+	limiter.sweep(now.Add(30 * time.Second))
+	if _, ok := limiter.clients["10.0.1.1"]; !ok {
+		t.Error("Expected active client to survive the sweep")
+	}
 }
 
 func TestCORS(t *testing.T) {
@@ -213,6 +400,9 @@ func TestCORS(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			req := httptest.NewRequest(tt.method, "/test", nil)
 			req.Header.Set("Origin", tt.origin)
+			if tt.method == "OPTIONS" {
+				req.Header.Set("Access-Control-Request-Method", "GET")
+			}
 			w := httptest.NewRecorder()
 
 			wrappedHandler.ServeHTTP(w, req)
@@ -221,19 +411,25 @@ func TestCORS(t *testing.T) {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
 			}
 
-			// Check CORS headers
+			// Check CORS headers, which are only sent to allowed origins
 			allowMethods := w.Header().Get("Access-Control-Allow-Methods")
-			if !strings.Contains(allowMethods, "GET") {
+			if tt.expectOrigin && !strings.Contains(allowMethods, "GET") {
 				t.Error("Expected Access-Control-Allow-Methods to contain GET")
 			}
+			if !tt.expectOrigin && allowMethods != "" {
+				t.Errorf("Expected no Access-Control-Allow-Methods for a disallowed origin, got %s", allowMethods)
+			}
 
 			allowHeaders := w.Header().Get("Access-Control-Allow-Headers")
-			if !strings.Contains(allowHeaders, "Content-Type") {
+			if tt.expectOrigin && !strings.Contains(allowHeaders, "Content-Type") {
 				t.Error("Expected Access-Control-Allow-Headers to contain Content-Type")
 			}
+			if !tt.expectOrigin && allowHeaders != "" {
+				t.Errorf("Expected no Access-Control-Allow-Headers for a disallowed origin, got %s", allowHeaders)
+			}
 
 			maxAge := w.Header().Get("Access-Control-Max-Age")
-			if maxAge != "86400" {
+			if tt.expectOrigin && maxAge != "86400" {
 				t.Errorf("Expected Access-Control-Max-Age '86400', got %s", maxAge)
 			}
 
@@ -264,3 +460,48 @@ func TestCORS(t *testing.T) {
 		}
 	})
 }
+
+func TestCORS_RouteMethods(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(CORS([]string{"https://app.example.com"},
+		WithRoutes(r),
+		WithAllowedHeaders("X-Client-Type"),
+	))
+	r.Route("/v1", func(r chi.Router) {
+		r.Get("/alerts", func(w http.ResponseWriter, r *http.Request) {})
+		r.Post("/webhooks", func(w http.ResponseWriter, r *http.Request) {})
+		r.Get("/webhooks", func(w http.ResponseWriter, r *http.Request) {})
+		r.Delete("/webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	tests := []struct {
+		path        string
+		wantMethods string
+	}{
+		{"/v1/alerts", "GET, OPTIONS"},
+		{"/v1/webhooks", "GET, POST, OPTIONS"},
+		{"/v1/webhooks/abc", "DELETE, OPTIONS"},
+		{"/v1/missing", "OPTIONS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("OPTIONS", tt.path, nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			req.Header.Set("Access-Control-Request-Method", "GET")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Expected methods %q, got %q", tt.wantMethods, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Authorization, X-Client-Type" {
+				t.Errorf("Expected configured headers, got %q", got)
+			}
+		})
+	}
+}
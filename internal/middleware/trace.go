@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// maxTraceIDLen bounds an X-Trace-Id value copied into logs
+const maxTraceIDLen = 64
+
+// traceFromRequest returns the trace and span IDs a caller propagated, so
+// request logs can be correlated with its traces. A valid W3C traceparent
+// header wins over X-Trace-Id, which carries a trace ID only. tracestate
+// holds vendor-specific data rather than IDs, so it is not read.
+func traceFromRequest(r *http.Request) (traceID, spanID string) {
+	if traceID, spanID, ok := parseTraceparent(r.Header.Get("traceparent")); ok {
+		return traceID, spanID
+	}
+	if id := strings.TrimSpace(r.Header.Get("X-Trace-Id")); validTraceToken(id) {
+		return id, ""
+	}
+	return "", ""
+}
+
+// parseTraceparent parses a W3C traceparent header,
+// version-traceid-parentid-flags, rejecting the all-zero IDs the spec
+// marks invalid
+func parseTraceparent(header string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+
+	// Version 00 has exactly four fields; later versions may append more
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", "", false
+	}
+	if !isLowerHex(traceID, 32) || !isLowerHex(spanID, 16) || !isLowerHex(flags, 2) {
+		return "", "", false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// isLowerHex reports whether s is n lower-case hex digits
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// validTraceToken reports whether a free-form trace ID is short and made
+// of letters, digits, '-' and '_', so it is safe to log as is
+func validTraceToken(s string) bool {
+	if s == "" || len(s) > maxTraceIDLen {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+)
+
+func TestTraceFromRequest(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		traceparent string
+		xTraceID    string
+		wantTrace   string
+		wantSpan    string
+	}{
+		{"Traceparent", "00-" + traceID + "-" + spanID + "-01", "", traceID, spanID},
+		{"Traceparent wins over X-Trace-Id", "00-" + traceID + "-" + spanID + "-00", "abc-123", traceID, spanID},
+		{"Future version with extra fields", "01-" + traceID + "-" + spanID + "-01-extra", "", traceID, spanID},
+		{"X-Trace-Id", "", "abc-123_DEF", "abc-123_DEF", ""},
+		{"Invalid traceparent falls back", "00-" + traceID + "-" + spanID, "abc-123", "abc-123", ""},
+		{"Upper-case hex", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", "", "", ""},
+		{"Zero trace ID", "00-00000000000000000000000000000000-" + spanID + "-01", "", "", ""},
+		{"Zero span ID", "00-" + traceID + "-0000000000000000-01", "", "", ""},
+		{"Version ff", "ff-" + traceID + "-" + spanID + "-01", "", "", ""},
+		{"Version 00 with extra fields", "00-" + traceID + "-" + spanID + "-01-extra", "", "", ""},
+		{"X-Trace-Id with unsafe characters", "", "abc\nlevel=ERROR", "", ""},
+		{"Overlong X-Trace-Id", "", string(bytes.Repeat([]byte("a"), maxTraceIDLen+1)), "", ""},
+		{"No headers", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			if tt.xTraceID != "" {
+				req.Header.Set("X-Trace-Id", tt.xTraceID)
+			}
+
+			gotTrace, gotSpan := traceFromRequest(req)
+			if gotTrace != tt.wantTrace || gotSpan != tt.wantSpan {
+				t.Errorf("Expected trace %q span %q, got %q %q", tt.wantTrace, tt.wantSpan, gotTrace, gotSpan)
+			}
+		})
+	}
+}
+
+func TestLogging_TraceFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger.InitWriter(&buf, "info", "json")
+	t.Cleanup(func() { logger.Init("error", "text") })
+
+	handler := Logging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.WithContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/alerts", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	dec := json.NewDecoder(&buf)
+	lines := 0
+	for dec.More() {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Failed to decode log line: %v", err)
+		}
+		lines++
+		if entry["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || entry["span_id"] != "00f067aa0ba902b7" {
+			t.Errorf("Expected trace fields on %q, got trace_id=%v span_id=%v", entry["msg"], entry["trace_id"], entry["span_id"])
+		}
+	}
+	if lines != 2 {
+		t.Errorf("Expected handler and request log lines, got %d", lines)
+	}
+}
@@ -1,30 +1,46 @@
 package models
 
-import "time"
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
 
 // Alert represents a supply chain disruption alert
 type Alert struct {
-	ID          string    `json:"id" db:"id"`
-	Source      string    `json:"source" db:"source"`
-	Title       string    `json:"title" db:"title"`
-	Summary     string    `json:"summary" db:"summary"`
-	URL         string    `json:"url" db:"url"`
-	DetectedAt  time.Time `json:"detected_at" db:"detected_at"`
-	PublishedAt time.Time `json:"published_at" db:"published_at"`
-	Region      string    `json:"region" db:"region"`
-	Country     string    `json:"country" db:"country"`
-	Location    string    `json:"location" db:"location"`
-	Latitude    float64   `json:"latitude" db:"latitude"`
-	Longitude   float64   `json:"longitude" db:"longitude"`
-	Disruption  string    `json:"disruption" db:"disruption"`
-	Severity    string    `json:"severity" db:"severity"`
-	Sentiment   string    `json:"sentiment" db:"sentiment"`
-	Confidence  float64   `json:"confidence" db:"confidence"`
-	Raw         string    `json:"raw" db:"raw"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          string     `json:"id" db:"id"`
+	Source      string     `json:"source" db:"source"`
+	Title       string     `json:"title" db:"title"`
+	Summary     string     `json:"summary" db:"summary"`
+	URL         string     `json:"url" db:"url"`
+	DetectedAt  time.Time  `json:"detected_at" db:"detected_at"`
+	PublishedAt time.Time  `json:"published_at" db:"published_at"`
+	Region      string     `json:"region" db:"region"`
+	Country     string     `json:"country" db:"country"`
+	Location    string     `json:"location" db:"location"`
+	Latitude    float64    `json:"latitude" db:"latitude"`
+	Longitude   float64    `json:"longitude" db:"longitude"`
+	Disruption  string     `json:"disruption" db:"disruption"`
+	Severity    string     `json:"severity" db:"severity"`
+	Sentiment   string     `json:"sentiment" db:"sentiment"`
+	Confidence  float64    `json:"confidence" db:"confidence"`
+	Impact      float64    `json:"impact" db:"impact"`
+	Escalated   bool       `json:"escalated" db:"escalated"`
+	Language    string     `json:"language" db:"language"`
+	Raw         string     `json:"raw,omitempty" db:"raw"`
+	RawFormat   string     `json:"raw_format,omitempty" db:"raw_format"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty" db:"archived_at"`
 }
 
+// RawFormatJSON marks Raw as the source item encoded as compact JSON.
+// Alerts stored before raw formats were recorded have an empty RawFormat
+// and an unstructured text dump in Raw.
+const RawFormatJSON = "json"
+
 // AlertQuery represents query parameters for filtering alerts
 type AlertQuery struct {
 	IDs         []string  `json:"ids"`
@@ -33,14 +49,249 @@ type AlertQuery struct {
 	Disruptions []string  `json:"disruptions"`
 	Regions     []string  `json:"regions"`
 	Countries   []string  `json:"countries"`
+	Locations   []string  `json:"locations,omitempty"`
+	Text        string    `json:"text,omitempty"`
+	BBox        *BBox     `json:"bbox,omitempty"`
 	Since       time.Time `json:"since"`
 	Until       time.Time `json:"until"`
 	Limit       int       `json:"limit"`
 	Offset      int       `json:"offset"`
+	Cursor      *Cursor   `json:"cursor,omitempty"`
+	SortBy      string    `json:"sort_by,omitempty"`
+	SortOrder   string    `json:"sort_order,omitempty"`
+
+	MinSeverity     string  `json:"min_severity,omitempty"`    // matches this SeverityRank and above
+	MinConfidence   float64 `json:"min_confidence,omitempty"`  // 0 matches every alert
+	MinImpact       float64 `json:"min_impact,omitempty"`      // 0 matches every alert
+	Escalated       *bool   `json:"escalated,omitempty"`       // nil matches every alert
+	HasCoordinates  *bool   `json:"has_coordinates,omitempty"` // nil matches every alert
+	IncludeArchived bool    `json:"include_archived,omitempty"`
+}
+
+// Sort fields accepted by AlertQuery.SortBy
+const (
+	SortDetectedAt  = "detected_at"
+	SortPublishedAt = "published_at"
+	SortSeverity    = "severity"
+	SortConfidence  = "confidence"
+	SortImpact      = "impact"
+)
+
+// Sort orders accepted by AlertQuery.SortOrder
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ValidSortField reports whether field is an allowed sort field
+func ValidSortField(field string) bool {
+	switch field {
+	case SortDetectedAt, SortPublishedAt, SortSeverity, SortConfidence, SortImpact:
+		return true
+	}
+	return false
+}
+
+// IsDefaultSort reports whether the query uses the default
+// detected_at descending order, the only order cursors support
+func (q AlertQuery) IsDefaultSort() bool {
+	return (q.SortBy == "" || q.SortBy == SortDetectedAt) && (q.SortOrder == "" || q.SortOrder == SortDesc)
+}
+
+// Group-by fields accepted by aggregate queries
+const (
+	GroupBySeverity   = "severity"
+	GroupByDisruption = "disruption"
+	GroupByRegion     = "region"
+	GroupByCountry    = "country"
+)
+
+// ValidGroupBy reports whether field is an allowed group-by field
+func ValidGroupBy(field string) bool {
+	switch field {
+	case GroupBySeverity, GroupByDisruption, GroupByRegion, GroupByCountry:
+		return true
+	}
+	return false
+}
+
+// GroupKey returns the alert's value for a group-by field, or "" if the
+// field is not groupable
+func (a Alert) GroupKey(field string) string {
+	switch field {
+	case GroupBySeverity:
+		return a.Severity
+	case GroupByDisruption:
+		return a.Disruption
+	case GroupByRegion:
+		return a.Region
+	case GroupByCountry:
+		return a.Country
+	}
+	return ""
+}
+
+// GroupCount is the number of alerts sharing one group-by value
+type GroupCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// SortGroupCounts orders counts largest first, breaking ties by key
+func SortGroupCounts(counts []GroupCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Key < counts[j].Key
+	})
+}
+
+// SeverityRank orders severities so that high > medium > low > anything else.
+// The Postgres store mirrors it in severityRankSQL.
+func SeverityRank(severity string) int {
+	switch severity {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	}
+	return 0
+}
+
+// BBox is a geographic bounding box in degrees
+type BBox struct {
+	MinLon float64 `json:"min_lon"`
+	MinLat float64 `json:"min_lat"`
+	MaxLon float64 `json:"max_lon"`
+	MaxLat float64 `json:"max_lat"`
+}
+
+// HasCoordinates reports whether the alert was geocoded; (0, 0) marks an
+// alert without coordinates
+func (a Alert) HasCoordinates() bool {
+	return a.Latitude != 0 || a.Longitude != 0
+}
+
+// Contains reports whether the alert's coordinates fall inside the box.
+// Alerts without coordinates (0, 0) are never contained.
+func (b BBox) Contains(alert Alert) bool {
+	if !alert.HasCoordinates() {
+		return false
+	}
+	return alert.Longitude >= b.MinLon && alert.Longitude <= b.MaxLon &&
+		alert.Latitude >= b.MinLat && alert.Latitude <= b.MaxLat
+}
+
+// Cursor marks a position in the (detected_at, id) ordering of alerts.
+// Results after a cursor are those strictly older than it.
+type Cursor struct {
+	DetectedAt time.Time
+	ID         string
+}
+
+// ErrInvalidCursor is returned when a cursor string cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// NewCursor returns a cursor positioned at the given alert
+func NewCursor(alert Alert) *Cursor {
+	return &Cursor{DetectedAt: alert.DetectedAt, ID: alert.ID}
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	return encodePosition(c.DetectedAt, c.ID)
+}
+
+// DecodeCursor parses a cursor previously produced by Cursor.Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	detectedAt, id, ok := decodePosition(s)
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{DetectedAt: detectedAt, ID: id}, nil
+}
+
+// After reports whether the alert sorts after the cursor in
+// (detected_at DESC, id DESC) order
+func (c Cursor) After(alert Alert) bool {
+	if alert.DetectedAt.Equal(c.DetectedAt) {
+		return alert.ID < c.ID
+	}
+	return alert.DetectedAt.Before(c.DetectedAt)
+}
+
+// ChangeToken marks a position in the (updated_at, id) ordering of alerts.
+// Changes since a token are alerts strictly newer than it.
+type ChangeToken struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+// ErrInvalidChangeToken is returned when a change token cannot be decoded
+var ErrInvalidChangeToken = errors.New("invalid change token")
+
+// NewChangeToken returns a token positioned at the given alert
+func NewChangeToken(alert Alert) *ChangeToken {
+	return &ChangeToken{UpdatedAt: alert.UpdatedAt, ID: alert.ID}
+}
+
+// Encode returns the opaque string form of the token
+func (t ChangeToken) Encode() string {
+	return encodePosition(t.UpdatedAt, t.ID)
+}
+
+// DecodeChangeToken parses a token previously produced by ChangeToken.Encode
+func DecodeChangeToken(s string) (*ChangeToken, error) {
+	updatedAt, id, ok := decodePosition(s)
+	if !ok {
+		return nil, ErrInvalidChangeToken
+	}
+	return &ChangeToken{UpdatedAt: updatedAt, ID: id}, nil
+}
+
+// Before reports whether the alert sorts after the token in
+// (updated_at ASC, id ASC) order, i.e. changed since it
+func (t ChangeToken) Before(alert Alert) bool {
+	if alert.UpdatedAt.Equal(t.UpdatedAt) {
+		return alert.ID > t.ID
+	}
+	return alert.UpdatedAt.After(t.UpdatedAt)
+}
+
+// encodePosition encodes a (time, id) keyset position as an opaque string
+func encodePosition(at time.Time, id string) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePosition reverses encodePosition
+func decodePosition(s string) (time.Time, string, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", false
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	return at, id, true
 }
 
 // Matches checks if an alert matches the query criteria
 func (q AlertQuery) Matches(alert Alert) bool {
+	if !q.IncludeArchived && alert.ArchivedAt != nil {
+		return false
+	}
 	if len(q.IDs) > 0 && !contains(q.IDs, alert.ID) {
 		return false
 	}
@@ -59,15 +310,50 @@ func (q AlertQuery) Matches(alert Alert) bool {
 	if len(q.Countries) > 0 && !contains(q.Countries, alert.Country) {
 		return false
 	}
+	if len(q.Locations) > 0 && !contains(q.Locations, alert.Location) {
+		return false
+	}
+	if q.Text != "" && !matchesText(alert, q.Text) {
+		return false
+	}
+	if q.BBox != nil && !q.BBox.Contains(alert) {
+		return false
+	}
+	if q.MinSeverity != "" && SeverityRank(alert.Severity) < SeverityRank(q.MinSeverity) {
+		return false
+	}
+	if q.MinConfidence > 0 && alert.Confidence < q.MinConfidence {
+		return false
+	}
+	if q.MinImpact > 0 && alert.Impact < q.MinImpact {
+		return false
+	}
+	if q.Escalated != nil && alert.Escalated != *q.Escalated {
+		return false
+	}
+	if q.HasCoordinates != nil && alert.HasCoordinates() != *q.HasCoordinates {
+		return false
+	}
 	if !q.Since.IsZero() && alert.DetectedAt.Before(q.Since) {
 		return false
 	}
 	if !q.Until.IsZero() && alert.DetectedAt.After(q.Until) {
 		return false
 	}
+	if q.Cursor != nil && !q.Cursor.After(alert) {
+		return false
+	}
 	return true
 }
 
+// matchesText reports whether text appears in the alert title or summary,
+// ignoring case
+func matchesText(alert Alert, text string) bool {
+	needle := strings.ToLower(text)
+	return strings.Contains(strings.ToLower(alert.Title), needle) ||
+		strings.Contains(strings.ToLower(alert.Summary), needle)
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
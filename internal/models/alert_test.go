@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -16,6 +17,10 @@ func TestAlertQuery_Matches(t *testing.T) {
 		Disruption: "port_status",
 		Region:     "North America",
 		Country:    "United States",
+		Confidence: 0.7,
+		Impact:     0.5,
+		Location:   "Port of Oakland",
+		Escalated:  true,
 	}
 
 	tests := []struct {
@@ -56,6 +61,90 @@ func TestAlertQuery_Matches(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "Text filter matches title case-insensitively",
+			query: AlertQuery{
+				Text: "test ALERT",
+			},
+			expected: true,
+		},
+		{
+			name: "Text filter matches summary",
+			query: AlertQuery{
+				Text: "summary",
+			},
+			expected: true,
+		},
+		{
+			name: "Text filter doesn't match",
+			query: AlertQuery{
+				Text: "typhoon",
+			},
+			expected: false,
+		},
+		{
+			name: "Min confidence at the alert's confidence matches",
+			query: AlertQuery{
+				MinConfidence: 0.7,
+			},
+			expected: true,
+		},
+		{
+			name: "Min confidence above the alert's confidence doesn't match",
+			query: AlertQuery{
+				MinConfidence: 0.9,
+			},
+			expected: false,
+		},
+		{
+			name: "Min impact at the alert's impact matches",
+			query: AlertQuery{
+				MinImpact: 0.5,
+			},
+			expected: true,
+		},
+		{
+			name: "Min impact above the alert's impact doesn't match",
+			query: AlertQuery{
+				MinImpact: 0.6,
+			},
+			expected: false,
+		},
+		{
+			name: "Escalated filter matches",
+			query: AlertQuery{
+				Escalated: &[]bool{true}[0],
+			},
+			expected: true,
+		},
+		{
+			name: "Not escalated filter doesn't match",
+			query: AlertQuery{
+				Escalated: &[]bool{false}[0],
+			},
+			expected: false,
+		},
+		{
+			name: "Has coordinates filter doesn't match",
+			query: AlertQuery{
+				HasCoordinates: &[]bool{true}[0],
+			},
+			expected: false,
+		},
+		{
+			name: "Without coordinates filter matches",
+			query: AlertQuery{
+				HasCoordinates: &[]bool{false}[0],
+			},
+			expected: true,
+		},
+		{
+			name: "Location filter doesn't match",
+			query: AlertQuery{
+				Locations: []string{"Port of Seattle"},
+			},
+			expected: false,
+		},
 		{
 			name: "Multiple filters match",
 			query: AlertQuery{
@@ -135,3 +224,142 @@ func TestContains(t *testing.T) {
 		})
 	}
 }
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	c := Cursor{
+		DetectedAt: time.Date(2024, 1, 15, 10, 0, 0, 123456789, time.UTC),
+		ID:         "alert|with|pipes",
+	}
+
+	decoded, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !decoded.DetectedAt.Equal(c.DetectedAt) || decoded.ID != c.ID {
+		t.Errorf("Expected %+v, got %+v", c, *decoded)
+	}
+
+	for _, bad := range []string{"", "!!!", "bm8tc2VwYXJhdG9y", "bm90LWEtdGltZXxpZA"} {
+		if _, err := DecodeCursor(bad); err == nil {
+			t.Errorf("Expected error decoding %q", bad)
+		}
+	}
+}
+
+func TestCursor_After(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	c := Cursor{DetectedAt: at, ID: "m"}
+
+	tests := []struct {
+		name     string
+		alert    Alert
+		expected bool
+	}{
+		{"Older alert", Alert{ID: "z", DetectedAt: at.Add(-time.Second)}, true},
+		{"Newer alert", Alert{ID: "a", DetectedAt: at.Add(time.Second)}, false},
+		{"Same time, lower ID", Alert{ID: "a", DetectedAt: at}, true},
+		{"Same time, same ID", Alert{ID: "m", DetectedAt: at}, false},
+		{"Same time, higher ID", Alert{ID: "z", DetectedAt: at}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.After(tt.alert); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestChangeToken_EncodeDecode(t *testing.T) {
+	tok := ChangeToken{
+		UpdatedAt: time.Date(2024, 1, 15, 10, 0, 0, 123456789, time.UTC),
+		ID:        "alert-1",
+	}
+
+	decoded, err := DecodeChangeToken(tok.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !decoded.UpdatedAt.Equal(tok.UpdatedAt) || decoded.ID != tok.ID {
+		t.Errorf("Expected %+v, got %+v", tok, *decoded)
+	}
+
+	if _, err := DecodeChangeToken("!!!"); !errors.Is(err, ErrInvalidChangeToken) {
+		t.Errorf("Expected ErrInvalidChangeToken, got %v", err)
+	}
+}
+
+func TestChangeToken_Before(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tok := ChangeToken{UpdatedAt: at, ID: "m"}
+
+	tests := []struct {
+		name     string
+		alert    Alert
+		expected bool
+	}{
+		{"Updated later", Alert{ID: "a", UpdatedAt: at.Add(time.Second)}, true},
+		{"Updated earlier", Alert{ID: "z", UpdatedAt: at.Add(-time.Second)}, false},
+		{"Same time, higher ID", Alert{ID: "z", UpdatedAt: at}, true},
+		{"Same time, same ID", Alert{ID: "m", UpdatedAt: at}, false},
+		{"Same time, lower ID", Alert{ID: "a", UpdatedAt: at}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tok.Before(tt.alert); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBBox_Contains(t *testing.T) {
+	box := BBox{MinLon: -120, MinLat: 30, MaxLon: -115, MaxLat: 35}
+
+	tests := []struct {
+		name     string
+		alert    Alert
+		expected bool
+	}{
+		{"Inside", Alert{Latitude: 33.7, Longitude: -118.3}, true},
+		{"On edge", Alert{Latitude: 30, Longitude: -115}, true},
+		{"Outside latitude", Alert{Latitude: 40, Longitude: -118}, false},
+		{"Outside longitude", Alert{Latitude: 33, Longitude: -100}, false},
+		{"No coordinates", Alert{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := box.Contains(tt.alert); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// Zero coordinates are excluded even when the box covers the origin
+	origin := BBox{MinLon: -10, MinLat: -10, MaxLon: 10, MaxLat: 10}
+	if origin.Contains(Alert{}) {
+		t.Error("Expected alert at 0/0 to be excluded")
+	}
+}
+
+func TestValidSortField(t *testing.T) {
+	for _, field := range []string{"detected_at", "published_at", "severity", "confidence"} {
+		if !ValidSortField(field) {
+			t.Errorf("Expected %s to be allowed", field)
+		}
+	}
+	for _, field := range []string{"", "id; DROP TABLE alerts", "title", "Severity"} {
+		if ValidSortField(field) {
+			t.Errorf("Expected %q to be rejected", field)
+		}
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if !(SeverityRank("high") > SeverityRank("medium") && SeverityRank("medium") > SeverityRank("low") && SeverityRank("low") > SeverityRank("")) {
+		t.Error("Expected high > medium > low > unknown")
+	}
+}
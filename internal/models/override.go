@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// AlertOverride is a manual correction of an alert's classification. Set
+// fields win over the classifier's values when the alert is read; empty
+// fields leave them alone.
+type AlertOverride struct {
+	AlertID     string    `json:"alert_id"`
+	Severity    string    `json:"severity,omitempty"`
+	Disruption  string    `json:"disruption,omitempty"`
+	SubmittedBy string    `json:"submitted_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Apply returns alert with the override's fields applied. The alert's
+// UpdatedAt moves forward to the override's so that ETags change.
+func (o AlertOverride) Apply(alert Alert) Alert {
+	if o.Severity != "" {
+		alert.Severity = o.Severity
+	}
+	if o.Disruption != "" {
+		alert.Disruption = o.Disruption
+	}
+	if o.UpdatedAt.After(alert.UpdatedAt) {
+		alert.UpdatedAt = o.UpdatedAt
+	}
+	return alert
+}
+
+// Merge returns o with the fields set in newer replacing its own, as when
+// a second correction to the same alert arrives
+func (o AlertOverride) Merge(newer AlertOverride) AlertOverride {
+	if newer.Severity != "" {
+		o.Severity = newer.Severity
+	}
+	if newer.Disruption != "" {
+		o.Disruption = newer.Disruption
+	}
+	o.SubmittedBy = newer.SubmittedBy
+	o.UpdatedAt = newer.UpdatedAt
+	return o
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Webhook is an outbound subscription that receives new alerts matching its filter
+type Webhook struct {
+	ID             string        `json:"id"`
+	URL            string        `json:"url"`
+	Secret         string        `json:"secret,omitempty"`
+	Filter         WebhookFilter `json:"filter"`
+	FailureCount   int           `json:"failure_count"`
+	LastError      string        `json:"last_error,omitempty"`
+	LastDeliveryAt *time.Time    `json:"last_delivery_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// WebhookFilter selects the alerts a webhook receives. Empty fields match
+// every alert, as in AlertQuery.
+type WebhookFilter struct {
+	Sources     []string `json:"sources,omitempty"`
+	Severities  []string `json:"severities,omitempty"`
+	Disruptions []string `json:"disruptions,omitempty"`
+	Regions     []string `json:"regions,omitempty"`
+	Countries   []string `json:"countries,omitempty"`
+	Text        string   `json:"text,omitempty"`
+}
+
+// Matches reports whether alert satisfies the filter
+func (f WebhookFilter) Matches(alert Alert) bool {
+	q := AlertQuery{
+		Sources:         f.Sources,
+		Severities:      f.Severities,
+		Disruptions:     f.Disruptions,
+		Regions:         f.Regions,
+		Countries:       f.Countries,
+		Text:            f.Text,
+		IncludeArchived: true,
+	}
+	return q.Matches(alert)
+}
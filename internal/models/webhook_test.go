@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+func TestWebhookFilter_Matches(t *testing.T) {
+	alert := Alert{
+		Source:     "Ports",
+		Title:      "Strike halts terminal",
+		Severity:   "high",
+		Disruption: "labor_strike",
+		Region:     "Europe",
+		Country:    "Netherlands",
+	}
+
+	tests := []struct {
+		name     string
+		filter   WebhookFilter
+		expected bool
+	}{
+		{"Empty filter matches all", WebhookFilter{}, true},
+		{"Matching severity", WebhookFilter{Severities: []string{"medium", "high"}}, true},
+		{"Other severity", WebhookFilter{Severities: []string{"low"}}, false},
+		{"Matching disruption and country", WebhookFilter{Disruptions: []string{"labor_strike"}, Countries: []string{"Netherlands"}}, true},
+		{"Other region", WebhookFilter{Regions: []string{"Asia"}}, false},
+		{"Matching source", WebhookFilter{Sources: []string{"Ports"}}, true},
+		{"Matching text", WebhookFilter{Text: "STRIKE"}, true},
+		{"Other text", WebhookFilter{Text: "canal"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(alert); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
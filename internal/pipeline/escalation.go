@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// previousAlert returns the most recent stored alert, other than alert
+// itself, with the same location and disruption detected no later than
+// alert. Alerts without a location or disruption, stores that can't be
+// queried and failed lookups have none.
+func (p *Pipeline) previousAlert(ctx context.Context, alert models.Alert) *models.Alert {
+	querier, ok := p.store.(Querier)
+	if !ok || alert.Location == "" || alert.Disruption == "" {
+		return nil
+	}
+
+	// Two results, as the newest may be this alert stored by an earlier run
+	previous, err := querier.QueryAlerts(ctx, models.AlertQuery{
+		Locations:   []string{alert.Location},
+		Disruptions: []string{alert.Disruption},
+		Until:       alert.DetectedAt,
+		Limit:       2,
+	})
+	if err != nil {
+		logger.Warn("Failed to look up previous alert, skipping escalation check",
+			"alert_id", alert.ID,
+			"error", err,
+		)
+		return nil
+	}
+
+	for _, prev := range previous {
+		if prev.ID != alert.ID {
+			return &prev
+		}
+	}
+	return nil
+}
+
+// markEscalations flags the alerts of a batch that are more severe than the
+// previous alert for their location and disruption. Alerts are compared in
+// DetectedAt order, so the previous alert is the later of stored[i], the
+// stored alert found by previousAlert, and the latest earlier alert in the
+// batch; the batch itself keeps its order.
+func markEscalations(alerts []models.Alert, stored []*models.Alert) {
+	order := make([]int, len(alerts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return alerts[order[a]].DetectedAt.Before(alerts[order[b]].DetectedAt)
+	})
+
+	type place struct{ location, disruption string }
+	latest := make(map[place]models.Alert)
+	for _, i := range order {
+		alert := &alerts[i]
+		alert.Escalated = false
+		if alert.Location == "" || alert.Disruption == "" {
+			continue
+		}
+
+		key := place{alert.Location, alert.Disruption}
+		prev := stored[i]
+		if earlier, ok := latest[key]; ok && earlier.ID != alert.ID &&
+			(prev == nil || !earlier.DetectedAt.Before(prev.DetectedAt)) {
+			prev = &earlier
+		}
+		if prev != nil {
+			alert.Escalated = models.SeverityRank(alert.Severity) > models.SeverityRank(prev.Severity)
+		}
+		latest[key] = *alert
+	}
+}
@@ -0,0 +1,18 @@
+package pipeline
+
+import (
+	"math"
+
+	"github.com/rajasatyajit/SupplyChain/config"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// impactScore rates an alert between 0 and 1 as its severity scaled by the
+// weights of its disruption and region. It is 0 when any of the three is
+// missing or unweighted. The score is rounded to two decimals, the
+// precision the alerts table stores.
+func impactScore(alert models.Alert, weights config.ImpactWeights) float64 {
+	severity := float64(models.SeverityRank(alert.Severity)) / float64(models.SeverityRank("high"))
+	score := severity * weights.Disruptions[alert.Disruption] * weights.Regions[alert.Region]
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/rajasatyajit/SupplyChain/config"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestImpactScore(t *testing.T) {
+	defaults := config.DefaultImpactWeights()
+	flat := config.ImpactWeights{
+		Disruptions: map[string]float64{"port_status": 1, "weather": 1},
+		Regions:     map[string]float64{"Asia": 1, "Europe": 1},
+	}
+	regionOnly := config.ImpactWeights{
+		Disruptions: map[string]float64{"port_status": 1, "weather": 1},
+		Regions:     map[string]float64{"Asia": 1, "Europe": 0.25},
+	}
+
+	tests := []struct {
+		name    string
+		alert   models.Alert
+		weights config.ImpactWeights
+		want    float64
+	}{
+		{"Default high port alert in Asia", models.Alert{Severity: "high", Disruption: "port_status", Region: "Asia"}, defaults, 1},
+		{"Default medium strike in Europe", models.Alert{Severity: "medium", Disruption: "labor_strike", Region: "Europe"}, defaults, 0.54},
+		{"Default low general alert in Oceania", models.Alert{Severity: "low", Disruption: "general", Region: "Oceania"}, defaults, 0.07},
+		{"Flat weights rank by severity", models.Alert{Severity: "medium", Disruption: "weather", Region: "Europe"}, flat, 0.67},
+		{"Region weight scales the score", models.Alert{Severity: "high", Disruption: "weather", Region: "Europe"}, regionOnly, 0.25},
+		{"Missing severity", models.Alert{Disruption: "port_status", Region: "Asia"}, defaults, 0},
+		{"Unknown severity", models.Alert{Severity: "unknown", Disruption: "port_status", Region: "Asia"}, defaults, 0},
+		{"Missing region", models.Alert{Severity: "high", Disruption: "port_status"}, defaults, 0},
+		{"Unweighted disruption", models.Alert{Severity: "high", Disruption: "cyber", Region: "Asia"}, flat, 0},
+		{"No weights configured", models.Alert{Severity: "high", Disruption: "port_status", Region: "Asia"}, config.ImpactWeights{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := impactScore(tt.alert, tt.weights); got != tt.want {
+				t.Errorf("Expected impact %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// JSONFeedSource implements Source for JSON Feed 1.1 feeds
+type JSONFeedSource struct {
+	name        string
+	urls        []string
+	interval    time.Duration
+	client      *http.Client
+	headers     http.Header
+	maxBodySize int64
+}
+
+// JSONFeedOption configures a JSONFeedSource
+type JSONFeedOption func(*JSONFeedSource)
+
+// WithJSONFeedMaxBodySize caps the bytes read from each feed response;
+// feeds larger than n fail to fetch. A non-positive n keeps
+// DefaultMaxFeedSize.
+func WithJSONFeedMaxBodySize(n int64) JSONFeedOption {
+	return func(j *JSONFeedSource) {
+		if n > 0 {
+			j.maxBodySize = n
+		}
+	}
+}
+
+// WithJSONFeedHeaders sends headers with every request, overriding the
+// default User-Agent and Accept headers
+func WithJSONFeedHeaders(headers map[string]string) JSONFeedOption {
+	return func(j *JSONFeedSource) {
+		j.headers = feedHeaders(headers)
+	}
+}
+
+// NewJSONFeedSource creates a new JSON Feed source polled every interval.
+// A non-positive interval uses DefaultRSSInterval.
+func NewJSONFeedSource(name string, urls []string, interval time.Duration, opts ...JSONFeedOption) *JSONFeedSource {
+	if interval <= 0 {
+		interval = DefaultRSSInterval
+	}
+
+	j := &JSONFeedSource{
+		name:        name,
+		urls:        urls,
+		interval:    interval,
+		client:      newFeedClient(),
+		maxBodySize: DefaultMaxFeedSize,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	return j
+}
+
+// Name returns the source name
+func (j *JSONFeedSource) Name() string {
+	return j.name
+}
+
+// Interval returns the polling interval
+func (j *JSONFeedSource) Interval() time.Duration {
+	return j.interval
+}
+
+// Fetch fetches alerts from JSON feeds. Like RSSSource.Fetch, a failed URL
+// doesn't stop the others and its error is returned with the alerts.
+func (j *JSONFeedSource) Fetch(ctx context.Context) ([]models.Alert, error) {
+	var allAlerts []models.Alert
+	errs := make([]error, len(j.urls))
+
+	for i, url := range j.urls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		alerts, err := j.fetchFromURL(ctx, url)
+		if err != nil {
+			logURLError(j.name, url, err)
+			errs[i] = err
+			continue
+		}
+		allAlerts = append(allAlerts, alerts...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return allAlerts, urlErrors(j.urls, errs)
+}
+
+// FetchURL fetches and parses one of the source's feed URLs
+func (j *JSONFeedSource) FetchURL(ctx context.Context, url string) ([]models.Alert, error) {
+	return j.fetchFromURL(ctx, url)
+}
+
+// fetchFromURL fetches and parses a JSON feed from a single URL
+func (j *JSONFeedSource) fetchFromURL(ctx context.Context, url string) ([]models.Alert, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	setFeedHeaders(req, "application/feed+json, application/json", j.headers)
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JSON feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Feed unchanged, e.g. when a configured header made the request conditional
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	// Read one byte past the limit to tell a full-size feed from an oversized one
+	body, err := io.ReadAll(io.LimitReader(resp.Body, j.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read feed: %w", err)
+	}
+	if int64(len(body)) > j.maxBodySize {
+		return nil, fmt.Errorf("feed exceeds %d bytes", j.maxBodySize)
+	}
+
+	var feed JSONFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, &FeedFormatError{Err: fmt.Errorf("parse JSON feed: %w", err)}
+	}
+
+	return j.convertToAlerts(feed), nil
+}
+
+// convertToAlerts converts JSON Feed items to Alert models
+func (j *JSONFeedSource) convertToAlerts(feed JSONFeed) []models.Alert {
+	var alerts []models.Alert
+
+	for _, item := range feed.Items {
+		summary := item.ContentText
+		if summary == "" {
+			summary = item.ContentHTML
+		}
+
+		url := item.URL
+		if url == "" {
+			url = item.ID
+		}
+
+		alert := models.Alert{
+			Source:     j.name,
+			Title:      item.Title,
+			Summary:    summary,
+			URL:        url,
+			DetectedAt: time.Now().UTC(),
+			Confidence: 0.7, // Default confidence for feeds
+		}
+		alert.Raw, alert.RawFormat = rawJSON(item)
+
+		if item.DatePublished != "" {
+			if pubDate, err := time.Parse(time.RFC3339, item.DatePublished); err == nil {
+				alert.PublishedAt = pubDate
+			}
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts
+}
+
+// JSONFeed represents a JSON Feed 1.1 document
+type JSONFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem represents a JSON Feed item
+type JSONFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	ContentHTML   string `json:"content_html"`
+	DatePublished string `json:"date_published"`
+}
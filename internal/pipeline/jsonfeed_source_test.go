@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/config"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestJSONFeedSource_Fetch(t *testing.T) {
+	feedContent := `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Port Authority",
+  "items": [
+    {
+      "id": "1",
+      "url": "http://example.com/news/1",
+      "title": "Terminal Closure",
+      "content_text": "Terminal closed due to weather",
+      "content_html": "<p>Terminal closed due to weather</p>",
+      "date_published": "2024-01-15T10:00:00Z"
+    },
+    {
+      "id": "http://example.com/news/2",
+      "title": "Berth Congestion",
+      "content_html": "<p>Vessels waiting at anchor</p>"
+    }
+  ]
+}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json")
+		w.Write([]byte(feedContent))
+	}))
+	defer server.Close()
+
+	source := NewJSONFeedSource("Test Source", []string{server.URL}, 0)
+
+	alerts, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatalf("Expected 2 alerts, got %d", len(alerts))
+	}
+
+	if alerts[0].Source != "Test Source" || alerts[0].Title != "Terminal Closure" || alerts[0].URL != "http://example.com/news/1" {
+		t.Errorf("Unexpected first alert: %+v", alerts[0])
+	}
+	if alerts[0].Summary != "Terminal closed due to weather" {
+		t.Errorf("Expected summary from content_text, got %s", alerts[0].Summary)
+	}
+	if !alerts[0].PublishedAt.Equal(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected published date from date_published, got %v", alerts[0].PublishedAt)
+	}
+
+	var raw JSONFeedItem
+	if err := json.Unmarshal([]byte(alerts[0].Raw), &raw); err != nil || alerts[0].RawFormat != models.RawFormatJSON {
+		t.Errorf("Expected raw item as JSON, got %q (%q): %v", alerts[0].Raw, alerts[0].RawFormat, err)
+	} else if raw.Title != "Terminal Closure" {
+		t.Errorf("Expected raw item title 'Terminal Closure', got %q", raw.Title)
+	}
+
+	if alerts[1].Summary != "<p>Vessels waiting at anchor</p>" {
+		t.Errorf("Expected summary from content_html, got %s", alerts[1].Summary)
+	}
+	if alerts[1].URL != "http://example.com/news/2" {
+		t.Errorf("Expected URL to fall back to id, got %s", alerts[1].URL)
+	}
+}
+
+func TestJSONFeedSource_FetchMalformed(t *testing.T) {
+	logger.Init("error", "text")
+
+	malformed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items": [{"title": "Broken"`))
+	}))
+	defer malformed.Close()
+
+	valid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "https://jsonfeed.org/version/1.1", "items": [{"id": "1", "title": "Port Strike"}]}`))
+	}))
+	defer valid.Close()
+
+	source := NewJSONFeedSource("Test Source", []string{malformed.URL, valid.URL}, 0)
+
+	// The malformed feed is reported and the remaining URLs still fetched
+	alerts, err := source.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 URLs failed") || !strings.Contains(err.Error(), malformed.URL) {
+		t.Errorf("Expected the malformed feed's error, got %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Title != "Port Strike" {
+		t.Errorf("Expected only the valid feed's alert, got %+v", alerts)
+	}
+
+	if _, err := source.fetchFromURL(context.Background(), malformed.URL); err == nil {
+		t.Error("Expected parse error for malformed feed")
+	}
+}
+
+func TestJSONFeedSource_FetchOversized(t *testing.T) {
+	logger.Init("error", "text")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		title := "Port Strike"
+		if r.URL.Path == "/large" {
+			title = strings.Repeat("x", 4096)
+		}
+		json.NewEncoder(w).Encode(JSONFeed{Items: []JSONFeedItem{{ID: "1", Title: title}}})
+	}))
+	defer server.Close()
+
+	src, err := NewSource(config.SourceConfig{
+		Name:         "Feed",
+		Type:         "jsonfeed",
+		URLs:         []string{server.URL + "/large", server.URL + "/small"},
+		MaxBodyBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	source := src.(*JSONFeedSource)
+
+	_, err = source.fetchFromURL(context.Background(), server.URL+"/large")
+	if err == nil || !strings.Contains(err.Error(), "feed exceeds 1024 bytes") {
+		t.Errorf("Expected oversized feed error, got %v", err)
+	}
+
+	alerts, err := source.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 URLs failed") {
+		t.Errorf("Expected the oversized feed's error, got %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Title != "Port Strike" {
+		t.Errorf("Expected only the small feed's alert, got %+v", alerts)
+	}
+}
+
+func TestNewSource_JSONFeed(t *testing.T) {
+	src, err := NewSource(config.SourceConfig{Name: "Feed", Type: "jsonfeed", URLs: []string{"http://example.com/feed.json"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := src.(*JSONFeedSource); !ok {
+		t.Errorf("Expected *JSONFeedSource, got %T", src)
+	}
+}
@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -11,26 +12,35 @@ import (
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
 	"github.com/rajasatyajit/SupplyChain/internal/metrics"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/tracing"
 	"github.com/rajasatyajit/SupplyChain/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 	"golang.org/x/time/rate"
 )
 
-// Source defines a pluggable data source implementation
+// Source defines a pluggable data source implementation. Fetch may return
+// alerts along with an error when only part of the source failed, e.g. one
+// of its feed URLs; the alerts are still processed and the run is recorded
+// as failed.
 type Source interface {
 	Name() string
 	Fetch(ctx context.Context) ([]models.Alert, error)
 	Interval() time.Duration
 }
 
-// Classifier interface for alert classification
+// Classifier interface for alert classification. Alerts of a batch are
+// classified concurrently, so implementations must be safe for concurrent use.
 type Classifier interface {
-	Classify(alert *models.Alert)
+	Classify(ctx context.Context, alert *models.Alert) error
 }
 
-// Geocoder interface for alert geocoding
+// Geocoder interface for alert geocoding. Like Classifier, it must be safe
+// for concurrent use.
 type Geocoder interface {
-	Geocode(alert *models.Alert) error
+	Geocode(ctx context.Context, alert *models.Alert) error
 }
 
 // Store interface for alert storage
@@ -38,26 +48,122 @@ type Store interface {
 	UpsertAlerts(ctx context.Context, alerts []models.Alert) error
 }
 
+// Querier is implemented by stores that can look up existing alerts
+type Querier interface {
+	QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error)
+}
+
+// Notifier is told about alerts stored for the first time, e.g. to push
+// them to webhooks
+type Notifier interface {
+	Notify(alerts []models.Alert)
+}
+
+// Archiver is implemented by stores that support archiving old alerts
+type Archiver interface {
+	ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// Watermarker is implemented by stores that remember the newest published
+// time processed for each source, so items at or before it can be skipped
+// without re-classifying and re-geocoding them
+type Watermarker interface {
+	SourceWatermark(ctx context.Context, source string) (time.Time, error)
+	SetSourceWatermark(ctx context.Context, source string, at time.Time) error
+}
+
+// ErrSourceNotFound is returned by RunSourceNow for an unknown source name
+var ErrSourceNotFound = errors.New("source not found")
+
+// ErrBreakerOpen is returned when a poll is skipped because the source's
+// circuit breaker is open
+var ErrBreakerOpen = errors.New("circuit breaker open")
+
+// maxBreakerCooldown caps how long an open breaker pauses a source
+const maxBreakerCooldown = time.Hour
+
+// Breaker states reported in SourceStatus
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// defaultGeocodeFailurePenalty is used when the config leaves
+// GeocodeFailurePenalty unset
+const defaultGeocodeFailurePenalty = 0.8
+
+// retentionInterval is how often the retention job archives old alerts
+const retentionInterval = 24 * time.Hour
+
 // Pipeline coordinates concurrent fetching, classification, geocoding, and storing
 type Pipeline struct {
 	store      Store
+	notifier   Notifier
 	classifier Classifier
 	geocoder   Geocoder
+	tracer     trace.Tracer
 	clients    map[string]*http.Client
 	limiter    *rate.Limiter
+	limiters   map[string]*rate.Limiter
 	sources    []Source
+	types      map[string]string
 	cfg        config.PipelineConfig
 	sem        *semaphore.Weighted
 	mu         sync.RWMutex
 	running    bool
+	stopping   bool
+	cancel     context.CancelFunc
+	inflight   sync.WaitGroup
+
+	statsMu sync.Mutex
+	stats   map[string]*SourceStatus
+}
+
+// SourceStatus reports the outcome of a source's most recent run
+type SourceStatus struct {
+	Name                string     `json:"name"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastCount           int        `json:"last_count"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Breaker             string     `json:"breaker"`
+	OpenUntil           *time.Time `json:"breaker_open_until,omitempty"`
+}
+
+// SourceInfo describes a configured source
+type SourceInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Interval string `json:"interval"`
+}
+
+// Option configures optional Pipeline dependencies
+type Option func(*Pipeline)
+
+// WithNotifier notifies n of new alerts after each stored batch. The store
+// must implement Querier so that alerts already stored are not re-notified.
+func WithNotifier(n Notifier) Option {
+	return func(p *Pipeline) {
+		p.notifier = n
+	}
+}
+
+// WithTracerProvider starts the pipeline's spans from tp instead of the
+// global tracer provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(p *Pipeline) {
+		p.tracer = tracing.Tracer(tp)
+	}
 }
 
 // New creates a new pipeline instance
-func New(store Store, classifier Classifier, geocoder Geocoder, cfg config.PipelineConfig) *Pipeline {
+func New(store Store, classifier Classifier, geocoder Geocoder, cfg config.PipelineConfig, opts ...Option) *Pipeline {
 	p := &Pipeline{
 		store:      store,
 		classifier: classifier,
 		geocoder:   geocoder,
+		tracer:     tracing.Tracer(nil),
 		cfg:        cfg,
 		clients: map[string]*http.Client{
 			"default": {
@@ -69,15 +175,39 @@ func New(store Store, classifier Classifier, geocoder Geocoder, cfg config.Pipel
 				},
 			},
 		},
-		limiter: rate.NewLimiter(rate.Limit(cfg.RateLimit), int(cfg.RateLimit)),
-		sem:     semaphore.NewWeighted(int64(cfg.WorkerCount)),
+		limiter:  newLimiter(cfg.RateLimit),
+		limiters: make(map[string]*rate.Limiter),
+		types:    make(map[string]string),
+		sem:      semaphore.NewWeighted(int64(cfg.WorkerCount)),
+		stats:    make(map[string]*SourceStatus),
 	}
 
-	// Register sources (in production, this would be configurable)
-	p.sources = []Source{
-		NewRSSSource("Global Shipping News", []string{
-			"https://news.un.org/feed/subscribe/en/news/region/africa/feed/rss.xml",
-		}),
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	// Register configured sources
+	sourceConfigs := cfg.Sources
+	if len(sourceConfigs) == 0 {
+		sourceConfigs = config.DefaultSources()
+	}
+	for _, sc := range sourceConfigs {
+		src, err := NewSource(sc)
+		if err != nil {
+			logger.Error("Skipping pipeline source", "source", sc.Name, "error", err)
+			continue
+		}
+		if len(sc.Headers) > 0 {
+			logger.Debug("Pipeline source sends custom headers",
+				"source", sc.Name,
+				"headers", logger.RedactHeaders(feedHeaders(sc.Headers)),
+			)
+		}
+		p.sources = append(p.sources, src)
+		p.types[sc.Name] = sc.Type
+		if sc.RateLimit > 0 {
+			p.limiters[sc.Name] = newLimiter(sc.RateLimit)
+		}
 	}
 
 	logger.Info("Pipeline initialized",
@@ -89,14 +219,49 @@ func New(store Store, classifier Classifier, geocoder Geocoder, cfg config.Pipel
 	return p
 }
 
+// NewSource constructs a Source from its configuration
+func NewSource(sc config.SourceConfig) (Source, error) {
+	switch sc.Type {
+	case "rss", "atom":
+		return NewRSSSource(sc.Name, sc.URLs, sc.Interval, WithMaxBodySize(sc.MaxBodyBytes), WithHeaders(sc.Headers)), nil
+	case "jsonfeed":
+		return NewJSONFeedSource(sc.Name, sc.URLs, sc.Interval, WithJSONFeedMaxBodySize(sc.MaxBodyBytes), WithJSONFeedHeaders(sc.Headers)), nil
+	default:
+		return nil, fmt.Errorf("unsupported source type %q", sc.Type)
+	}
+}
+
+// newLimiter creates a rate limiter allowing rps requests per second with a
+// burst of at least one so that fractional rates still admit requests
+func newLimiter(rps float64) *rate.Limiter {
+	burst := int(rps)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// limiterFor returns the source's own rate limiter, or the shared one if it has none
+func (p *Pipeline) limiterFor(name string) *rate.Limiter {
+	if l, ok := p.limiters[name]; ok {
+		return l
+	}
+	return p.limiter
+}
+
 // Run starts the pipeline and runs until context is cancelled
 func (p *Pipeline) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	p.mu.Lock()
 	if p.running {
 		p.mu.Unlock()
 		return fmt.Errorf("pipeline already running")
 	}
 	p.running = true
+	p.stopping = false
+	p.cancel = cancel
 	p.mu.Unlock()
 
 	defer func() {
@@ -127,6 +292,15 @@ func (p *Pipeline) Run(ctx context.Context) error {
 		}()
 	}
 
+	// Archive alerts past the retention period
+	if archiver, ok := p.store.(Archiver); ok && p.cfg.RetentionDays > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.runRetention(ctx, archiver)
+		}()
+	}
+
 	// Wait for all pollers to finish
 	go func() {
 		wg.Wait()
@@ -148,6 +322,78 @@ func (p *Pipeline) Run(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown stops new polls and waits for in-flight batches to be stored,
+// returning an error if ctx expires first
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopping = true
+	cancel := p.cancel
+	p.mu.Unlock()
+
+	logger.Info("Pipeline shutting down, draining in-flight batches")
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("Pipeline drained")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("drain in-flight batches: %w", ctx.Err())
+	}
+}
+
+// beginBatch registers in-flight work unless the pipeline is shutting down
+func (p *Pipeline) beginBatch() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopping {
+		return false
+	}
+	p.inflight.Add(1)
+	return true
+}
+
+// runRetention archives expired alerts immediately and then once per retentionInterval
+func (p *Pipeline) runRetention(ctx context.Context, archiver Archiver) {
+	logger.Info("Starting retention job", "retention_days", p.cfg.RetentionDays)
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		p.archiveExpired(ctx, archiver)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archiveExpired archives alerts detected before the retention cutoff
+func (p *Pipeline) archiveExpired(ctx context.Context, archiver Archiver) {
+	cutoff := time.Now().UTC().AddDate(0, 0, -p.cfg.RetentionDays)
+
+	count, err := archiver.ArchiveOlderThan(ctx, cutoff)
+	if err != nil {
+		logger.Error("Failed to archive expired alerts", "cutoff", cutoff, "error", err)
+		return
+	}
+
+	logger.Info("Archived expired alerts", "count", count, "cutoff", cutoff)
+}
+
 // runSourcePoller runs a single source poller
 func (p *Pipeline) runSourcePoller(ctx context.Context, src Source) error {
 	logger.Info("Starting source poller", "source", src.Name())
@@ -156,7 +402,7 @@ func (p *Pipeline) runSourcePoller(ctx context.Context, src Source) error {
 	defer ticker.Stop()
 
 	// Initial immediate run
-	if err := p.runOnce(ctx, src); err != nil {
+	if err := p.runOnce(ctx, src); err != nil && !errors.Is(err, ErrBreakerOpen) {
 		logger.Error("Initial source run failed", "source", src.Name(), "error", err)
 	}
 
@@ -166,7 +412,12 @@ func (p *Pipeline) runSourcePoller(ctx context.Context, src Source) error {
 			logger.Info("Source poller stopping", "source", src.Name())
 			return ctx.Err()
 		case <-ticker.C:
-			if err := p.runOnce(ctx, src); err != nil {
+			err := p.runOnce(ctx, src)
+			if errors.Is(err, ErrBreakerOpen) {
+				logger.Debug("Skipping poll, circuit breaker open", "source", src.Name())
+				continue
+			}
+			if err != nil {
 				logger.Error("Source run failed", "source", src.Name(), "error", err)
 
 				// Implement exponential backoff on errors
@@ -181,19 +432,154 @@ func (p *Pipeline) runSourcePoller(ctx context.Context, src Source) error {
 	}
 }
 
-// runOnce executes a single pipeline run for a source
+// RunSourceNow runs the named source immediately, outside its polling
+// schedule, and returns how many alerts were processed. It shares the worker
+// semaphore and rate limiter with the pollers.
+func (p *Pipeline) RunSourceNow(ctx context.Context, name string) (int, error) {
+	for _, src := range p.sources {
+		if src.Name() == name {
+			return p.run(ctx, src)
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrSourceNotFound, name)
+}
+
+// Sources describes the configured sources, in registration order
+func (p *Pipeline) Sources() []SourceInfo {
+	infos := make([]SourceInfo, 0, len(p.sources))
+	for _, src := range p.sources {
+		infos = append(infos, SourceInfo{
+			Name:     src.Name(),
+			Type:     p.types[src.Name()],
+			Interval: src.Interval().String(),
+		})
+	}
+	return infos
+}
+
+// Stats returns the latest run status of every source, in registration order
+func (p *Pipeline) Stats() []SourceStatus {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	now := time.Now()
+	stats := make([]SourceStatus, 0, len(p.sources))
+	for _, src := range p.sources {
+		status := SourceStatus{Name: src.Name()}
+		if s, ok := p.stats[src.Name()]; ok {
+			status = *s
+		}
+
+		switch {
+		case status.OpenUntil == nil:
+			status.Breaker = BreakerClosed
+		case now.Before(*status.OpenUntil):
+			status.Breaker = BreakerOpen
+		default:
+			status.Breaker = BreakerHalfOpen
+		}
+		stats = append(stats, status)
+	}
+	return stats
+}
+
+// breakerOpen reports whether polls of the source are paused. Once the
+// cooldown has passed the breaker is half-open: the next poll is let through
+// and either closes it or reopens it for longer.
+func (p *Pipeline) breakerOpen(name string, now time.Time) bool {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	status, ok := p.stats[name]
+	return ok && status.OpenUntil != nil && now.Before(*status.OpenUntil)
+}
+
+// breakerCooldown returns how long to pause a source after failures
+// consecutive failures, doubling with each failure past the threshold
+func (p *Pipeline) breakerCooldown(failures int) time.Duration {
+	cooldown := p.cfg.BreakerCooldown
+	for i := p.cfg.BreakerThreshold; i < failures && cooldown < maxBreakerCooldown; i++ {
+		cooldown *= 2
+	}
+	if cooldown > maxBreakerCooldown {
+		cooldown = maxBreakerCooldown
+	}
+	return cooldown
+}
+
+// recordRun updates a source's status after a run
+func (p *Pipeline) recordRun(name string, at time.Time, count int, err error) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	status, ok := p.stats[name]
+	if !ok {
+		status = &SourceStatus{Name: name}
+		p.stats[name] = status
+	}
+
+	status.LastRunAt = &at
+	status.LastCount = count
+	if err == nil {
+		status.LastError = ""
+		status.ConsecutiveFailures = 0
+		status.OpenUntil = nil
+		return
+	}
+
+	status.LastError = err.Error()
+	status.ConsecutiveFailures++
+
+	if p.cfg.BreakerThreshold > 0 && status.ConsecutiveFailures >= p.cfg.BreakerThreshold {
+		cooldown := p.breakerCooldown(status.ConsecutiveFailures)
+		openUntil := at.Add(cooldown)
+		status.OpenUntil = &openUntil
+		logger.Warn("Source circuit breaker opened",
+			"source", name,
+			"consecutive_failures", status.ConsecutiveFailures,
+			"cooldown", cooldown,
+		)
+	}
+}
+
+// runOnce executes a scheduled pipeline run for a source, skipping it with
+// ErrBreakerOpen while the source's circuit breaker is open
 func (p *Pipeline) runOnce(ctx context.Context, src Source) error {
+	if p.breakerOpen(src.Name(), time.Now()) {
+		return ErrBreakerOpen
+	}
+	_, err := p.run(ctx, src)
+	return err
+}
+
+// run executes a single pipeline run for a source, returning the number of
+// alerts processed, and records the outcome in the source's stats
+func (p *Pipeline) run(ctx context.Context, src Source) (count int, err error) {
 	start := time.Now()
 
+	ctx, span := p.tracer.Start(ctx, "pipeline.run", trace.WithAttributes(attribute.String("source", src.Name())))
+	defer func() {
+		span.SetAttributes(attribute.Int("alert_count", count))
+		tracing.End(span, err)
+	}()
+
+	defer func() {
+		// Runs cut short by shutdown say nothing about the source's health
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+		p.recordRun(src.Name(), start.UTC(), count, err)
+	}()
+
 	// Acquire semaphore to limit concurrent processing
 	if err := p.sem.Acquire(ctx, 1); err != nil {
-		return fmt.Errorf("acquire semaphore: %w", err)
+		return 0, fmt.Errorf("acquire semaphore: %w", err)
 	}
 	defer p.sem.Release(1)
 
 	// Rate limiting
-	if err := p.limiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit: %w", err)
+	if err := p.limiterFor(src.Name()).Wait(ctx); err != nil {
+		return 0, fmt.Errorf("rate limit: %w", err)
 	}
 
 	defer func() {
@@ -207,7 +593,6 @@ func (p *Pipeline) runOnce(ctx context.Context, src Source) error {
 
 	// Fetch alerts with retry logic
 	var alerts []models.Alert
-	var err error
 
 	for attempt := 0; attempt <= p.cfg.RetryAttempts; attempt++ {
 		if attempt > 0 {
@@ -216,12 +601,12 @@ func (p *Pipeline) runOnce(ctx context.Context, src Source) error {
 
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return 0, ctx.Err()
 			case <-time.After(delay):
 			}
 		}
 
-		alerts, err = src.Fetch(ctx)
+		alerts, err = p.fetch(ctx, src, attempt)
 		if err == nil {
 			break
 		}
@@ -235,14 +620,34 @@ func (p *Pipeline) runOnce(ctx context.Context, src Source) error {
 
 	if err != nil {
 		metrics.RecordAlertProcessed(src.Name(), "fetch_error")
-		return fmt.Errorf("%s fetch failed after %d attempts: %w", src.Name(), p.cfg.RetryAttempts+1, err)
+		err = fmt.Errorf("%s fetch failed after %d attempts: %w", src.Name(), p.cfg.RetryAttempts+1, err)
+		if len(alerts) == 0 {
+			return 0, err
+		}
 	}
 
+	// A partial fetch still stores what was fetched, then reports the failure
+	fetchErr := err
+
 	if len(alerts) == 0 {
 		logger.Debug("No alerts fetched", "source", src.Name())
-		return nil
+		return 0, nil
+	}
+
+	alerts = p.skipSeen(ctx, src.Name(), alerts)
+	if len(alerts) == 0 {
+		logger.Debug("No new alerts since watermark", "source", src.Name())
+		return 0, fetchErr
 	}
 
+	// Once started, a run's batches are stored even if a shutdown cancels ctx
+	if !p.beginBatch() {
+		logger.Info("Pipeline shutting down, dropping fetched alerts", "source", src.Name(), "count", len(alerts))
+		return 0, fetchErr
+	}
+	defer p.inflight.Done()
+	batchCtx := context.WithoutCancel(ctx)
+
 	logger.Debug("Processing alerts", "source", src.Name(), "count", len(alerts))
 
 	// Process alerts in batches
@@ -258,7 +663,7 @@ func (p *Pipeline) runOnce(ctx context.Context, src Source) error {
 		}
 
 		batch := alerts[i:end]
-		if err := p.processBatch(ctx, src.Name(), batch); err != nil {
+		if err := p.processBatch(batchCtx, src.Name(), batch); err != nil {
 			logger.Error("Batch processing failed",
 				"source", src.Name(),
 				"batch_start", i,
@@ -266,61 +671,256 @@ func (p *Pipeline) runOnce(ctx context.Context, src Source) error {
 				"error", err,
 			)
 			metrics.RecordAlertProcessed(src.Name(), "process_error")
-			return err
+			return i, err
 		}
 	}
 
+	p.advanceWatermark(batchCtx, src.Name(), alerts)
+
 	metrics.RecordAlertProcessed(src.Name(), "success")
 	logger.Info("Successfully processed alerts",
 		"source", src.Name(),
 		"count", len(alerts),
 	)
 
-	return nil
+	return len(alerts), fetchErr
 }
 
-// processBatch processes a batch of alerts
-func (p *Pipeline) processBatch(ctx context.Context, sourceName string, alerts []models.Alert) error {
-	// Process each alert
-	for i := range alerts {
-		alert := &alerts[i]
+// skipSeen drops alerts published at or before the source's watermark.
+// Undated alerts are always kept, and if the watermark can't be read
+// everything is processed, as upserts make that safe.
+func (p *Pipeline) skipSeen(ctx context.Context, source string, alerts []models.Alert) []models.Alert {
+	wm, ok := p.store.(Watermarker)
+	if !ok {
+		return alerts
+	}
 
-		// Set source if not already set
-		if alert.Source == "" {
-			alert.Source = sourceName
-		}
+	watermark, err := wm.SourceWatermark(ctx, source)
+	if err != nil {
+		logger.Warn("Failed to read source watermark, processing all alerts", "source", source, "error", err)
+		return alerts
+	}
+	if watermark.IsZero() {
+		return alerts
+	}
 
-		// Set detection time
-		if alert.DetectedAt.IsZero() {
-			alert.DetectedAt = time.Now().UTC()
+	unseen := alerts[:0:0]
+	for _, alert := range alerts {
+		if alert.PublishedAt.IsZero() || alert.PublishedAt.After(watermark) {
+			unseen = append(unseen, alert)
 		}
+	}
+	if skipped := len(alerts) - len(unseen); skipped > 0 {
+		logger.Debug("Skipped alerts at or before watermark", "source", source, "skipped", skipped, "watermark", watermark)
+	}
+	return unseen
+}
 
-		// Generate ID if not set
-		if alert.ID == "" {
-			alert.ID = utils.HashString(alert.URL + alert.Title + alert.PublishedAt.String())
-		}
+// advanceWatermark records the newest published time among alerts once
+// they are all stored. A failure only costs re-processing on the next poll.
+func (p *Pipeline) advanceWatermark(ctx context.Context, source string, alerts []models.Alert) {
+	wm, ok := p.store.(Watermarker)
+	if !ok {
+		return
+	}
 
-		// Set disruption type
-		if alert.Disruption == "" {
-			alert.Disruption = utils.InferDisruption(alert.Title + " " + alert.Summary)
+	var newest time.Time
+	for _, alert := range alerts {
+		if alert.PublishedAt.After(newest) {
+			newest = alert.PublishedAt
 		}
+	}
+	if newest.IsZero() {
+		return
+	}
 
-		// Classify alert
-		p.classifier.Classify(alert)
+	if err := wm.SetSourceWatermark(ctx, source, newest); err != nil {
+		logger.Warn("Failed to save source watermark", "source", source, "error", err)
+	}
+}
 
-		// Geocode alert
-		if err := p.geocoder.Geocode(alert); err != nil {
-			logger.Warn("Geocoding failed",
-				"alert_id", alert.ID,
-				"error", err,
-			)
-			// Reduce confidence but continue processing
-			alert.Confidence *= 0.8
+// geocodeFailurePenalty returns the factor applied to the confidence of
+// alerts whose location could not be resolved
+func (p *Pipeline) geocodeFailurePenalty() float64 {
+	if p.cfg.GeocodeFailurePenalty > 0 {
+		return p.cfg.GeocodeFailurePenalty
+	}
+	return defaultGeocodeFailurePenalty
+}
+
+// fetch fetches alerts from src in a span recording the attempt
+func (p *Pipeline) fetch(ctx context.Context, src Source, attempt int) (alerts []models.Alert, err error) {
+	ctx, span := p.tracer.Start(ctx, "pipeline.fetch", trace.WithAttributes(
+		attribute.String("source", src.Name()),
+		attribute.Int("attempt", attempt+1),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	alerts, err = src.Fetch(ctx)
+	span.SetAttributes(attribute.Int("alert_count", len(alerts)))
+	return alerts, err
+}
+
+// processBatch processes a batch of alerts
+func (p *Pipeline) processBatch(ctx context.Context, sourceName string, alerts []models.Alert) (err error) {
+	ctx, span := p.tracer.Start(ctx, "pipeline.process_batch", trace.WithAttributes(
+		attribute.String("source", sourceName),
+		attribute.Int("batch_size", len(alerts)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	// Enrich alerts in place on up to WorkerCount goroutines, so the batch
+	// keeps its order, and look up the stored alert each may escalate; the
+	// first error abandons the rest of the batch
+	stored := make([]*models.Alert, len(alerts))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(p.cfg.WorkerCount, 1))
+	for i := range alerts {
+		if gctx.Err() != nil {
+			break
 		}
+		g.Go(func() error {
+			if err := p.enrich(gctx, sourceName, &alerts[i]); err != nil {
+				return err
+			}
+			stored[i] = p.previousAlert(gctx, alerts[i])
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Flag rises in severity over the previous alert for the same place,
+	// which may be earlier in this batch
+	markEscalations(alerts, stored)
+
+	// Look up which alerts are new before the upsert makes them all exist
+	var fresh []models.Alert
+	if p.notifier != nil {
+		fresh = p.newAlerts(ctx, alerts)
 	}
 
 	// Store alerts
-	return p.store.UpsertAlerts(ctx, alerts)
+	storeCtx, storeSpan := p.tracer.Start(ctx, "pipeline.store", trace.WithAttributes(attribute.Int("batch_size", len(alerts))))
+	err = p.store.UpsertAlerts(storeCtx, alerts)
+	tracing.End(storeSpan, err)
+	if err != nil {
+		return err
+	}
+
+	if len(fresh) > 0 {
+		p.notifier.Notify(fresh)
+	}
+	return nil
+}
+
+// enrich fills in an alert's defaults, classification, location and impact.
+// It runs concurrently for the alerts of a batch, so the classifier and
+// geocoder must be safe for concurrent use.
+func (p *Pipeline) enrich(ctx context.Context, sourceName string, alert *models.Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Set source if not already set
+	if alert.Source == "" {
+		alert.Source = sourceName
+	}
+
+	// Set detection time
+	if alert.DetectedAt.IsZero() {
+		alert.DetectedAt = time.Now().UTC()
+	}
+
+	// Generate ID if not set
+	if alert.ID == "" {
+		alert.ID = alertID(*alert, p.cfg.DedupKey)
+	}
+
+	// Set disruption type
+	if alert.Disruption == "" {
+		alert.Disruption = utils.InferDisruption(alert.Title + " " + alert.Summary)
+	}
+
+	// Classify alert
+	classifyCtx, classifySpan := p.tracer.Start(ctx, "pipeline.classify", trace.WithAttributes(attribute.String("alert_id", alert.ID)))
+	err := p.classifier.Classify(classifyCtx, alert)
+	tracing.End(classifySpan, err)
+	if err != nil {
+		return fmt.Errorf("classify alert %s: %w", alert.ID, err)
+	}
+
+	// Geocode alert
+	geocodeCtx, geocodeSpan := p.tracer.Start(ctx, "pipeline.geocode", trace.WithAttributes(attribute.String("alert_id", alert.ID)))
+	err = p.geocoder.Geocode(geocodeCtx, alert)
+	tracing.End(geocodeSpan, err)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("geocode alert %s: %w", alert.ID, err)
+	}
+	if err != nil {
+		logger.Warn("Geocoding failed",
+			"alert_id", alert.ID,
+			"error", err,
+		)
+		// Reduce confidence but continue processing
+		alert.Confidence *= p.geocodeFailurePenalty()
+	}
+
+	// Score impact once severity, disruption and region are known
+	alert.Impact = impactScore(*alert, p.cfg.Impact)
+	return nil
+}
+
+// newAlerts returns the alerts whose IDs are not yet stored. If the store
+// cannot be queried none are returned, so a lookup failure never re-sends
+// alerts that were already notified.
+func (p *Pipeline) newAlerts(ctx context.Context, alerts []models.Alert) []models.Alert {
+	querier, ok := p.store.(Querier)
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+
+	existing, err := querier.QueryAlerts(ctx, models.AlertQuery{IDs: ids, IncludeArchived: true})
+	if err != nil {
+		logger.Warn("Failed to look up existing alerts, skipping notifications", "error", err)
+		return nil
+	}
+
+	stored := make(map[string]bool, len(existing))
+	for _, alert := range existing {
+		stored[alert.ID] = true
+	}
+
+	var fresh []models.Alert
+	for _, alert := range alerts {
+		if !stored[alert.ID] {
+			stored[alert.ID] = true // a batch may repeat an ID
+			fresh = append(fresh, alert)
+		}
+	}
+	return fresh
+}
+
+// alertID derives an alert's ID from the fields selected by dedupKey,
+// defaulting to URL and title
+func alertID(alert models.Alert, dedupKey string) string {
+	switch dedupKey {
+	case config.DedupURL:
+		return utils.HashString(alert.URL)
+	case config.DedupURLTitlePubDate:
+		return utils.HashString(alert.URL + alert.Title + alert.PublishedAt.String())
+	default:
+		return utils.HashString(alert.URL + alert.Title)
+	}
 }
 
 // IsRunning returns whether the pipeline is currently running
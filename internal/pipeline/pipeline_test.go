@@ -3,12 +3,24 @@ package pipeline
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/rajasatyajit/SupplyChain/config"
+	"github.com/rajasatyajit/SupplyChain/internal/geocoder"
 	"github.com/rajasatyajit/SupplyChain/internal/logger"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // MockStore for testing
@@ -25,13 +37,34 @@ func (m *MockStore) UpsertAlerts(ctx context.Context, alerts []models.Alert) err
 	return nil
 }
 
-// MockClassifier for testing
-type MockClassifier struct{}
+// MockArchiver records archive requests
+type MockArchiver struct {
+	MockStore
+	cutoffs chan time.Time
+}
+
+func (m *MockArchiver) ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	m.cutoffs <- cutoff
+	return 1, nil
+}
+
+// MockClassifier for testing; severity defaults to medium
+type MockClassifier struct {
+	severity   string
+	severities map[string]string // by title, overriding severity
+}
 
-func (m *MockClassifier) Classify(alert *models.Alert) {
+func (m *MockClassifier) Classify(ctx context.Context, alert *models.Alert) error {
 	alert.Severity = "medium"
+	if m.severity != "" {
+		alert.Severity = m.severity
+	}
+	if severity, ok := m.severities[alert.Title]; ok {
+		alert.Severity = severity
+	}
 	alert.Sentiment = "neutral"
 	alert.Confidence = 0.8
+	return nil
 }
 
 // MockGeocoder for testing
@@ -39,7 +72,7 @@ type MockGeocoder struct {
 	err error
 }
 
-func (m *MockGeocoder) Geocode(alert *models.Alert) error {
+func (m *MockGeocoder) Geocode(ctx context.Context, alert *models.Alert) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -49,12 +82,43 @@ func (m *MockGeocoder) Geocode(alert *models.Alert) error {
 	return nil
 }
 
+// slowGeocoder simulates a network geocoder, recording the most lookups
+// in flight at once
+type slowGeocoder struct {
+	latency time.Duration
+
+	mu          sync.Mutex
+	inflight    int
+	maxInflight int
+}
+
+func (g *slowGeocoder) Geocode(ctx context.Context, alert *models.Alert) error {
+	g.mu.Lock()
+	g.inflight++
+	g.maxInflight = max(g.maxInflight, g.inflight)
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		g.inflight--
+		g.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(g.latency):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	alert.Location = "Location of " + alert.Title
+	return nil
+}
+
 // MockSource for testing
 type MockSource struct {
 	name     string
 	alerts   []models.Alert
 	err      error
 	interval time.Duration
+	fetches  int
 }
 
 func (m *MockSource) Name() string {
@@ -62,6 +126,7 @@ func (m *MockSource) Name() string {
 }
 
 func (m *MockSource) Fetch(ctx context.Context) ([]models.Alert, error) {
+	m.fetches++
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -113,6 +178,44 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_ConfiguredSources(t *testing.T) {
+	logger.Init("error", "text")
+
+	cfg := config.PipelineConfig{
+		RateLimit:   5.0,
+		WorkerCount: 1,
+		Sources: []config.SourceConfig{
+			{Name: "Ports", Type: "rss", URLs: []string{"http://example.com/a"}, Interval: 2 * time.Minute, RateLimit: 0.5},
+			{Name: "Unknown", Type: "carrier-pigeon", URLs: []string{"http://example.com/b"}},
+		},
+	}
+
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, cfg)
+
+	if len(pipeline.sources) != 1 {
+		t.Fatalf("Expected 1 source (unknown type skipped), got %d", len(pipeline.sources))
+	}
+	if pipeline.sources[0].Name() != "Ports" {
+		t.Errorf("Expected source 'Ports', got %s", pipeline.sources[0].Name())
+	}
+	if pipeline.sources[0].Interval() != 2*time.Minute {
+		t.Errorf("Expected interval 2m, got %v", pipeline.sources[0].Interval())
+	}
+
+	limiter := pipeline.limiterFor("Ports")
+	if limiter == pipeline.limiter || limiter.Limit() != 0.5 || limiter.Burst() != 1 {
+		t.Errorf("Expected dedicated 0.5 rps limiter, got limit %v burst %d", limiter.Limit(), limiter.Burst())
+	}
+	if pipeline.limiterFor("Other") != pipeline.limiter {
+		t.Error("Expected sources without an override to share the global limiter")
+	}
+
+	want := []SourceInfo{{Name: "Ports", Type: "rss", Interval: "2m0s"}}
+	if got := pipeline.Sources(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected sources %v, got %v", want, got)
+	}
+}
+
 func TestPipeline_ProcessBatch(t *testing.T) {
 	store := &MockStore{}
 	classifier := &MockClassifier{}
@@ -179,6 +282,173 @@ func TestPipeline_ProcessBatch(t *testing.T) {
 	}
 }
 
+func TestPipeline_ProcessBatch_Impact(t *testing.T) {
+	store := &MockStore{}
+	pipeline := New(store, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{
+		RateLimit:   5.0,
+		WorkerCount: 1,
+		Impact: config.ImpactWeights{
+			Disruptions: map[string]float64{"port_status": 0.9},
+			Regions:     map[string]float64{"Test Region": 0.5},
+		},
+	})
+
+	alerts := []models.Alert{
+		{Title: "Port congestion", URL: "http://example.com/1"},
+		{Title: "Storm warning", URL: "http://example.com/2"},
+	}
+	if err := pipeline.processBatch(context.Background(), "test-source", alerts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// medium (2/3) * 0.9 * 0.5, while weather has no weight
+	if got := store.alerts[0].Impact; got != 0.3 {
+		t.Errorf("Expected impact 0.3 for the port alert, got %v", got)
+	}
+	if got := store.alerts[1].Impact; got != 0 {
+		t.Errorf("Expected impact 0 for an unweighted disruption, got %v", got)
+	}
+}
+
+func TestPipeline_Spans(t *testing.T) {
+	logger.Init("error", "text")
+
+	recorder := tracetest.NewSpanRecorder()
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{
+		RateLimit:   100.0,
+		WorkerCount: 1,
+	}, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	source := &MockSource{name: "test-source", alerts: []models.Alert{
+		{URL: "http://example.com/1", Title: "Port Strike"},
+		{URL: "http://example.com/2", Title: "Canal Closure"},
+	}}
+	if err := pipeline.runOnce(context.Background(), source); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	counts := map[string]int{}
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		counts[span.Name()]++
+		byName[span.Name()] = span
+	}
+	want := map[string]int{
+		"pipeline.run":           1,
+		"pipeline.fetch":         1,
+		"pipeline.process_batch": 1,
+		"pipeline.classify":      2,
+		"pipeline.geocode":       2,
+		"pipeline.store":         1,
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Fatalf("Expected spans %v, got %v", want, counts)
+	}
+
+	batch := byName["pipeline.process_batch"]
+	for _, kv := range []attribute.KeyValue{attribute.String("source", "test-source"), attribute.Int("batch_size", 2)} {
+		found := false
+		for _, attr := range batch.Attributes() {
+			found = found || attr == kv
+		}
+		if !found {
+			t.Errorf("Expected batch span attribute %v, got %v", kv, batch.Attributes())
+		}
+	}
+	if batch.Parent().SpanID() != byName["pipeline.run"].SpanContext().SpanID() {
+		t.Error("Expected the batch span to be a child of the run span")
+	}
+	if byName["pipeline.store"].Parent().SpanID() != batch.SpanContext().SpanID() {
+		t.Error("Expected the store span to be a child of the batch span")
+	}
+}
+
+func TestPipeline_ProcessBatch_Escalation(t *testing.T) {
+	logger.Init("error", "text")
+
+	alertStore := store.NewInMemoryStore()
+	classifier := &MockClassifier{}
+	pipeline := New(alertStore, classifier, &MockGeocoder{}, config.PipelineConfig{RateLimit: 5.0, WorkerCount: 1})
+
+	detectedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	process := func(severity, url, disruption string) models.Alert {
+		t.Helper()
+		classifier.severity = severity
+		detectedAt = detectedAt.Add(time.Hour)
+		alerts := []models.Alert{{Title: "Port update", URL: url, Disruption: disruption, DetectedAt: detectedAt}}
+		if err := pipeline.processBatch(context.Background(), "test-source", alerts); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		stored, err := alertStore.GetAlert(context.Background(), alerts[0].ID)
+		if err != nil || stored == nil {
+			t.Fatalf("Expected alert %s to be stored, got %v", alerts[0].ID, err)
+		}
+		return *stored
+	}
+
+	if alert := process("medium", "http://example.com/1", "port_status"); alert.Escalated {
+		t.Error("Expected the first alert for a location not to escalate")
+	}
+	if alert := process("high", "http://example.com/2", "port_status"); !alert.Escalated {
+		t.Error("Expected a high alert after a medium one to escalate")
+	}
+	if alert := process("high", "http://example.com/3", "port_status"); alert.Escalated {
+		t.Error("Expected a repeat of the same severity not to escalate")
+	}
+	if alert := process("high", "http://example.com/4", "weather"); alert.Escalated {
+		t.Error("Expected a different disruption at the same location not to escalate")
+	}
+
+	escalated := true
+	found, err := alertStore.QueryAlerts(context.Background(), models.AlertQuery{Escalated: &escalated})
+	if err != nil || len(found) != 1 || found[0].URL != "http://example.com/2" {
+		t.Errorf("Expected only the second alert to be escalated, got %+v (err %v)", found, err)
+	}
+}
+
+func TestPipeline_ProcessBatch_EscalationWithinBatch(t *testing.T) {
+	logger.Init("error", "text")
+
+	alertStore := store.NewInMemoryStore()
+	classifier := &MockClassifier{severities: map[string]string{
+		"Port congested": "medium",
+		"Port closed":    "high",
+		"Port reopened":  "low",
+	}}
+	pipeline := New(alertStore, classifier, &MockGeocoder{}, config.PipelineConfig{RateLimit: 5.0, WorkerCount: 4})
+
+	// Out of detection order, so escalation can't follow the batch order
+	detectedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alerts := []models.Alert{
+		{Title: "Port closed", URL: "http://example.com/2", Disruption: "port_status", DetectedAt: detectedAt.Add(time.Hour)},
+		{Title: "Port congested", URL: "http://example.com/1", Disruption: "port_status", DetectedAt: detectedAt},
+		{Title: "Port reopened", URL: "http://example.com/3", Disruption: "port_status", DetectedAt: detectedAt.Add(2 * time.Hour)},
+	}
+	if err := pipeline.processBatch(context.Background(), "test-source", alerts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[string]bool{"Port congested": false, "Port closed": true, "Port reopened": false}
+	for _, alert := range alerts {
+		stored, err := alertStore.GetAlert(context.Background(), alert.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("Expected alert %s to be stored, got %v", alert.ID, err)
+		}
+		if stored.Escalated != want[alert.Title] {
+			t.Errorf("Expected %q escalated=%v, got %v", alert.Title, want[alert.Title], stored.Escalated)
+		}
+	}
+
+	// A later batch compares against the latest stored alert
+	next := []models.Alert{{Title: "Port closed", URL: "http://example.com/4", Disruption: "port_status", DetectedAt: detectedAt.Add(3 * time.Hour)}}
+	if err := pipeline.processBatch(context.Background(), "test-source", next); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !next[0].Escalated {
+		t.Error("Expected a high alert after the stored low one to escalate")
+	}
+}
+
 func TestPipeline_ProcessBatch_GeocodingError(t *testing.T) {
 	store := &MockStore{}
 	classifier := &MockClassifier{}
@@ -218,6 +488,111 @@ func TestPipeline_ProcessBatch_GeocodingError(t *testing.T) {
 	}
 }
 
+func TestPipeline_ProcessBatch_GeocodeFailurePenalty(t *testing.T) {
+	// MockClassifier sets confidence to 0.8 before geocoding
+	tests := []struct {
+		name    string
+		penalty float64
+		want    float64
+	}{
+		{"Default penalty", 0, 0.64},
+		{"Configured penalty", 0.5, 0.4},
+		{"Penalty disabled", 1, 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &MockStore{}
+			cfg := config.PipelineConfig{WorkerCount: 1, GeocodeFailurePenalty: tt.penalty}
+			pipeline := New(store, &MockClassifier{}, &MockGeocoder{err: errors.New("geocoding failed")}, cfg)
+
+			alerts := []models.Alert{{Title: "Test Alert", URL: "http://example.com/1"}}
+			if err := pipeline.processBatch(context.Background(), "test-source", alerts); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(store.alerts) != 1 {
+				t.Fatalf("Expected 1 alert in store, got %d", len(store.alerts))
+			}
+			if got := store.alerts[0].Confidence; math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Expected confidence %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPipeline_ProcessBatch_CanceledDuringGeocoding(t *testing.T) {
+	logger.Init("error", "text")
+
+	// A Nominatim server that holds each lookup until the client gives up
+	requests := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	mockStore := &MockStore{}
+	geo := geocoder.NewWithProvider(geocoder.NewNominatimProvider(server.URL, time.Minute))
+	pipeline := New(mockStore, &MockClassifier{}, geo, config.PipelineConfig{RateLimit: 5.0, WorkerCount: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requests
+		cancel()
+	}()
+
+	alerts := []models.Alert{
+		{Title: "Delays in Seattle, WA", URL: "http://example.com/1"},
+		{Title: "Delays in Tacoma, WA", URL: "http://example.com/2"},
+	}
+	done := make(chan error, 1)
+	go func() { done <- pipeline.processBatch(ctx, "test-source", alerts) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected cancellation to abort the in-flight lookup")
+	}
+	if len(mockStore.alerts) != 0 {
+		t.Errorf("Expected an aborted batch to store nothing, got %d alerts", len(mockStore.alerts))
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no lookups after cancellation, got %d", len(requests))
+	}
+}
+
+func TestPipeline_ProcessBatch_Concurrent(t *testing.T) {
+	logger.Init("error", "text")
+
+	geo := &slowGeocoder{latency: 10 * time.Millisecond}
+	mockStore := &MockStore{}
+	pipeline := New(mockStore, &MockClassifier{}, geo, config.PipelineConfig{RateLimit: 5.0, WorkerCount: 3})
+
+	alerts := make([]models.Alert, 12)
+	for i := range alerts {
+		alerts[i] = models.Alert{Title: fmt.Sprintf("Alert %d", i), URL: fmt.Sprintf("http://example.com/%d", i)}
+	}
+	if err := pipeline.processBatch(context.Background(), "test-source", alerts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if geo.maxInflight != 3 {
+		t.Errorf("Expected up to 3 concurrent lookups, got %d", geo.maxInflight)
+	}
+	if len(mockStore.alerts) != len(alerts) {
+		t.Fatalf("Expected %d stored alerts, got %d", len(alerts), len(mockStore.alerts))
+	}
+	for i, alert := range mockStore.alerts {
+		if want := fmt.Sprintf("Location of Alert %d", i); alert.Location != want {
+			t.Errorf("Expected alert %d to be stored in order with location %q, got %q", i, want, alert.Location)
+		}
+	}
+}
+
 func TestPipeline_ProcessBatch_StoreError(t *testing.T) {
 	store := &MockStore{err: errors.New("store error")}
 	classifier := &MockClassifier{}
@@ -285,6 +660,390 @@ func TestPipeline_RunOnce(t *testing.T) {
 	}
 }
 
+func TestPipeline_RunSourceNow(t *testing.T) {
+	store := &MockStore{}
+	cfg := config.PipelineConfig{
+		RateLimit:   100.0,
+		WorkerCount: 1,
+		BatchSize:   10,
+	}
+
+	pipeline := New(store, &MockClassifier{}, &MockGeocoder{}, cfg)
+	pipeline.sources = []Source{&MockSource{
+		name: "test-source",
+		alerts: []models.Alert{
+			{Title: "Port Strike", URL: "http://example.com/1"},
+			{Title: "Canal Closure", URL: "http://example.com/2"},
+		},
+	}}
+
+	count, err := pipeline.RunSourceNow(context.Background(), "test-source")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 2 || len(store.alerts) != 2 {
+		t.Errorf("Expected 2 alerts processed and stored, got %d processed, %d stored", count, len(store.alerts))
+	}
+
+	if _, err := pipeline.RunSourceNow(context.Background(), "missing"); !errors.Is(err, ErrSourceNotFound) {
+		t.Errorf("Expected ErrSourceNotFound, got %v", err)
+	}
+
+	// Runs share the worker semaphore with the pollers
+	if !pipeline.sem.TryAcquire(1) {
+		t.Fatal("Expected semaphore to be released after the run")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pipeline.RunSourceNow(ctx, "test-source"); err == nil {
+		t.Error("Expected run to wait for the busy worker slot")
+	}
+	pipeline.sem.Release(1)
+}
+
+func TestPipeline_Stats(t *testing.T) {
+	cfg := config.PipelineConfig{
+		RateLimit:   100.0,
+		WorkerCount: 1,
+		BatchSize:   10,
+	}
+
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, cfg)
+	source := &MockSource{
+		name:   "test-source",
+		alerts: []models.Alert{{Title: "Port Strike", URL: "http://example.com/1"}},
+	}
+	pipeline.sources = []Source{source, &MockSource{name: "idle-source"}}
+
+	stats := pipeline.Stats()
+	if len(stats) != 2 || stats[0].LastRunAt != nil || stats[1].Name != "idle-source" {
+		t.Fatalf("Expected empty stats for both sources, got %+v", stats)
+	}
+
+	if err := pipeline.runOnce(context.Background(), source); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stats = pipeline.Stats()
+	if stats[0].LastRunAt == nil || stats[0].LastCount != 1 || stats[0].LastError != "" || stats[0].ConsecutiveFailures != 0 {
+		t.Errorf("Unexpected stats after success: %+v", stats[0])
+	}
+
+	source.err = errors.New("feed down")
+	for i := 0; i < 2; i++ {
+		pipeline.runOnce(context.Background(), source)
+	}
+	stats = pipeline.Stats()
+	if stats[0].ConsecutiveFailures != 2 || !strings.Contains(stats[0].LastError, "feed down") || stats[0].LastCount != 0 {
+		t.Errorf("Unexpected stats after failures: %+v", stats[0])
+	}
+
+	source.err = nil
+	pipeline.runOnce(context.Background(), source)
+	if stats = pipeline.Stats(); stats[0].ConsecutiveFailures != 0 || stats[0].LastError != "" {
+		t.Errorf("Expected success to reset failures, got %+v", stats[0])
+	}
+	if stats[1].LastRunAt != nil {
+		t.Errorf("Expected idle source to have no runs, got %+v", stats[1])
+	}
+}
+
+func TestPipeline_CircuitBreaker(t *testing.T) {
+	logger.Init("error", "text")
+
+	cfg := config.PipelineConfig{
+		RateLimit:        100.0,
+		WorkerCount:      1,
+		BatchSize:        10,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Hour,
+	}
+
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, cfg)
+	source := &MockSource{name: "test-source", err: errors.New("feed down")}
+	pipeline.sources = []Source{source}
+
+	for i := 0; i < cfg.BreakerThreshold; i++ {
+		if err := pipeline.runOnce(context.Background(), source); err == nil || errors.Is(err, ErrBreakerOpen) {
+			t.Fatalf("Expected fetch error on run %d, got %v", i+1, err)
+		}
+	}
+	if stats := pipeline.Stats(); stats[0].Breaker != BreakerOpen || stats[0].OpenUntil == nil {
+		t.Fatalf("Expected breaker open after %d failures, got %+v", cfg.BreakerThreshold, stats[0])
+	}
+
+	// Polls during the cooldown are skipped without fetching
+	fetches := source.fetches
+	for i := 0; i < 3; i++ {
+		if err := pipeline.runOnce(context.Background(), source); !errors.Is(err, ErrBreakerOpen) {
+			t.Errorf("Expected ErrBreakerOpen, got %v", err)
+		}
+	}
+	if source.fetches != fetches {
+		t.Errorf("Expected no fetches while open, got %d", source.fetches-fetches)
+	}
+
+	// Manual runs bypass the breaker
+	if _, err := pipeline.RunSourceNow(context.Background(), "test-source"); errors.Is(err, ErrBreakerOpen) {
+		t.Error("Expected manual run to bypass the breaker")
+	}
+
+	// Once the cooldown passes the breaker is half-open and the next poll is tried
+	past := time.Now().Add(-time.Second)
+	pipeline.stats["test-source"].OpenUntil = &past
+	if stats := pipeline.Stats(); stats[0].Breaker != BreakerHalfOpen {
+		t.Errorf("Expected half-open breaker, got %s", stats[0].Breaker)
+	}
+
+	source.err = nil
+	if err := pipeline.runOnce(context.Background(), source); err != nil {
+		t.Fatalf("Expected half-open poll to run, got %v", err)
+	}
+	if stats := pipeline.Stats(); stats[0].Breaker != BreakerClosed || stats[0].ConsecutiveFailures != 0 {
+		t.Errorf("Expected success to close the breaker, got %+v", stats[0])
+	}
+}
+
+func TestPipeline_CircuitBreaker_FeedDown(t *testing.T) {
+	logger.Init("error", "text")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := config.PipelineConfig{
+		RateLimit:        100.0,
+		WorkerCount:      1,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	}
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, cfg)
+	source := NewRSSSource("down-feed", []string{server.URL}, 0)
+	pipeline.sources = []Source{source}
+
+	for i := 0; i < cfg.BreakerThreshold; i++ {
+		if err := pipeline.runOnce(context.Background(), source); err == nil || !strings.Contains(err.Error(), "HTTP 500") {
+			t.Fatalf("Expected HTTP 500 fetch error on run %d, got %v", i+1, err)
+		}
+	}
+
+	stats := pipeline.Stats()
+	if stats[0].Breaker != BreakerOpen || stats[0].ConsecutiveFailures != cfg.BreakerThreshold {
+		t.Fatalf("Expected breaker open after %d failures, got %+v", cfg.BreakerThreshold, stats[0])
+	}
+	if !strings.Contains(stats[0].LastError, server.URL) {
+		t.Errorf("Expected last error to name the failed URL, got %q", stats[0].LastError)
+	}
+	if err := pipeline.runOnce(context.Background(), source); !errors.Is(err, ErrBreakerOpen) {
+		t.Errorf("Expected ErrBreakerOpen, got %v", err)
+	}
+}
+
+func TestPipeline_Run_PartialFetch(t *testing.T) {
+	logger.Init("error", "text")
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss version="2.0"><channel><item><title>Port closed</title><link>https://example.com/1</link></item></channel></rss>`)
+	}))
+	defer up.Close()
+
+	alertStore := &MockStore{}
+	pipeline := New(alertStore, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{RateLimit: 100.0, WorkerCount: 1})
+	source := NewRSSSource("half-feed", []string{down.URL, up.URL}, 0)
+	pipeline.sources = []Source{source}
+
+	// The healthy URL's alerts are stored, but the run still counts as failed
+	count, err := pipeline.RunSourceNow(context.Background(), "half-feed")
+	if err == nil || !strings.Contains(err.Error(), down.URL) {
+		t.Errorf("Expected the failed URL's error, got %v", err)
+	}
+	if count != 1 || len(alertStore.alerts) != 1 || alertStore.alerts[0].Title != "Port closed" {
+		t.Errorf("Expected the healthy feed's alert to be stored, got count %d and %+v", count, alertStore.alerts)
+	}
+	if stats := pipeline.Stats(); stats[0].ConsecutiveFailures != 1 || stats[0].LastCount != 1 {
+		t.Errorf("Expected a failed run with 1 alert, got %+v", stats[0])
+	}
+}
+
+func TestPipeline_BreakerCooldown(t *testing.T) {
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{
+		WorkerCount:      1,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Minute,
+	})
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{3, time.Minute},
+		{4, 2 * time.Minute},
+		{6, 8 * time.Minute},
+		{20, maxBreakerCooldown},
+	}
+
+	for _, tt := range tests {
+		if got := pipeline.breakerCooldown(tt.failures); got != tt.expected {
+			t.Errorf("breakerCooldown(%d) = %v, expected %v", tt.failures, got, tt.expected)
+		}
+	}
+}
+
+func TestAlertID(t *testing.T) {
+	published := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	original := models.Alert{URL: "http://example.com/1", Title: "Port Strike", PublishedAt: published}
+	republished := original
+	republished.PublishedAt = published.Add(6 * time.Hour)
+	retitled := original
+	retitled.Title = "Port Strike Ends"
+
+	tests := []struct {
+		name             string
+		dedupKey         string
+		samePubDateChurn bool
+		sameTitleChange  bool
+	}{
+		{"Default", "", true, false},
+		{"URL and title", config.DedupURLTitle, true, false},
+		{"URL only", config.DedupURL, true, true},
+		{"URL, title and pubdate", config.DedupURLTitlePubDate, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := alertID(original, tt.dedupKey)
+			if got := alertID(republished, tt.dedupKey) == id; got != tt.samePubDateChurn {
+				t.Errorf("Expected same ID after pubDate change to be %v", tt.samePubDateChurn)
+			}
+			if got := alertID(retitled, tt.dedupKey) == id; got != tt.sameTitleChange {
+				t.Errorf("Expected same ID after title change to be %v", tt.sameTitleChange)
+			}
+		})
+	}
+}
+
+func TestPipeline_DefaultDedupCollapsesRepublished(t *testing.T) {
+	st := store.NewInMemoryStore()
+	pipeline := New(st, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{
+		RateLimit:   100.0,
+		WorkerCount: 1,
+		BatchSize:   10,
+	})
+
+	published := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	source := &MockSource{name: "test-source", alerts: []models.Alert{
+		{URL: "http://example.com/1", Title: "Port Strike", PublishedAt: published},
+	}}
+	pipeline.runOnce(context.Background(), source)
+
+	source.alerts = []models.Alert{
+		{URL: "http://example.com/1", Title: "Port Strike", PublishedAt: published.Add(time.Hour)},
+	}
+	pipeline.runOnce(context.Background(), source)
+
+	if count, _ := st.CountAlerts(context.Background(), models.AlertQuery{}); count != 1 {
+		t.Errorf("Expected republished item to collapse into 1 alert, got %d", count)
+	}
+}
+
+func TestPipeline_SkipsItemsAtOrBeforeWatermark(t *testing.T) {
+	logger.Init("error", "text")
+
+	st := store.NewInMemoryStore()
+	pipeline := New(st, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{
+		RateLimit:   100.0,
+		WorkerCount: 1,
+		BatchSize:   10,
+	})
+	ctx := context.Background()
+
+	published := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	source := &MockSource{name: "test-source", alerts: []models.Alert{
+		{URL: "http://example.com/1", Title: "Port Strike", PublishedAt: published},
+		{URL: "http://example.com/2", Title: "Canal Closure", PublishedAt: published.Add(-time.Hour)},
+	}}
+	if count, err := pipeline.run(ctx, source); err != nil || count != 2 {
+		t.Fatalf("Expected first run to process 2 alerts, got %d (err %v)", count, err)
+	}
+	if wm, _ := st.SourceWatermark(ctx, "test-source"); !wm.Equal(published) {
+		t.Errorf("Expected watermark %v, got %v", published, wm)
+	}
+
+	if count, err := pipeline.run(ctx, source); err != nil || count != 0 {
+		t.Errorf("Expected refetch of the same feed to process nothing, got %d (err %v)", count, err)
+	}
+
+	// Newer and undated items are processed; items at the watermark are not
+	source.alerts = append(source.alerts,
+		models.Alert{URL: "http://example.com/3", Title: "Rail Strike", PublishedAt: published.Add(time.Minute)},
+		models.Alert{URL: "http://example.com/4", Title: "Storm Warning"},
+	)
+	if count, err := pipeline.run(ctx, source); err != nil || count != 2 {
+		t.Errorf("Expected newer and undated alerts to be processed, got %d (err %v)", count, err)
+	}
+	if wm, _ := st.SourceWatermark(ctx, "test-source"); !wm.Equal(published.Add(time.Minute)) {
+		t.Errorf("Expected watermark to advance to %v, got %v", published.Add(time.Minute), wm)
+	}
+}
+
+// recordingNotifier records notified alerts
+type recordingNotifier struct {
+	notified [][]models.Alert
+}
+
+func (n *recordingNotifier) Notify(alerts []models.Alert) {
+	n.notified = append(n.notified, alerts)
+}
+
+func TestPipeline_NotifiesNewAlertsOnly(t *testing.T) {
+	cfg := config.PipelineConfig{
+		RateLimit:   100.0,
+		WorkerCount: 1,
+		BatchSize:   10,
+	}
+
+	notifier := &recordingNotifier{}
+	pipeline := New(store.NewInMemoryStore(), &MockClassifier{}, &MockGeocoder{}, cfg, WithNotifier(notifier))
+
+	source := &MockSource{
+		name: "test-source",
+		alerts: []models.Alert{
+			{ID: "alert-1", Title: "Port Strike"},
+			{ID: "alert-1", Title: "Port Strike (updated)"},
+		},
+	}
+
+	if err := pipeline.runOnce(context.Background(), source); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(notifier.notified) != 1 || len(notifier.notified[0]) != 1 || notifier.notified[0][0].ID != "alert-1" {
+		t.Fatalf("Expected alert-1 notified once, got %+v", notifier.notified)
+	}
+
+	// A later poll returning the same alert plus a new one notifies only the new one
+	source.alerts = []models.Alert{
+		{ID: "alert-1", Title: "Port Strike"},
+		{ID: "alert-2", Title: "Canal Closure"},
+	}
+	if err := pipeline.runOnce(context.Background(), source); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(notifier.notified) != 2 || len(notifier.notified[1]) != 1 || notifier.notified[1][0].ID != "alert-2" {
+		t.Errorf("Expected only alert-2 notified, got %+v", notifier.notified)
+	}
+
+	// Re-polling unchanged alerts notifies nothing
+	source.alerts = source.alerts[:1]
+	pipeline.runOnce(context.Background(), source)
+	if len(notifier.notified) != 2 {
+		t.Errorf("Expected no notification for stored alerts, got %+v", notifier.notified[2:])
+	}
+}
+
 func TestPipeline_RunOnce_FetchError(t *testing.T) {
 	store := &MockStore{}
 	classifier := &MockClassifier{}
@@ -409,3 +1168,173 @@ func TestPipeline_Run_AlreadyRunning(t *testing.T) {
 		t.Error("Expected error when pipeline already running, got nil")
 	}
 }
+
+func TestPipeline_RetentionArchivesOnStart(t *testing.T) {
+	logger.Init("error", "text")
+
+	store := &MockArchiver{cutoffs: make(chan time.Time, 1)}
+	cfg := config.PipelineConfig{
+		RateLimit:     5.0,
+		WorkerCount:   1,
+		RetentionDays: 30,
+	}
+
+	pipeline := New(store, &MockClassifier{}, &MockGeocoder{}, cfg)
+	pipeline.sources = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pipeline.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case cutoff := <-store.cutoffs:
+		expected := time.Now().UTC().AddDate(0, 0, -30)
+		if diff := expected.Sub(cutoff); diff < 0 || diff > time.Minute {
+			t.Errorf("Expected cutoff near %v, got %v", expected, cutoff)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected retention job to archive on start")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected pipeline to stop after cancel")
+	}
+}
+
+// SlowStore blocks each upsert for delay, signalling when one starts and finishes
+type SlowStore struct {
+	MockStore
+	delay    time.Duration
+	started  chan struct{}
+	finished chan struct{}
+}
+
+func (m *SlowStore) UpsertAlerts(ctx context.Context, alerts []models.Alert) error {
+	m.started <- struct{}{}
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	close(m.finished)
+	return m.MockStore.UpsertAlerts(ctx, alerts)
+}
+
+func TestPipeline_Shutdown(t *testing.T) {
+	logger.Init("error", "text")
+
+	tests := []struct {
+		name          string
+		storeDelay    time.Duration
+		deadline      time.Duration
+		expectDrained bool
+	}{
+		{
+			name:          "Waits for in-flight batch",
+			storeDelay:    200 * time.Millisecond,
+			deadline:      2 * time.Second,
+			expectDrained: true,
+		},
+		{
+			name:          "Returns at deadline",
+			storeDelay:    2 * time.Second,
+			deadline:      100 * time.Millisecond,
+			expectDrained: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &SlowStore{
+				delay:    tt.storeDelay,
+				started:  make(chan struct{}, 1),
+				finished: make(chan struct{}),
+			}
+			cfg := config.PipelineConfig{RateLimit: 100, WorkerCount: 1, BatchSize: 10}
+
+			pipeline := New(store, &MockClassifier{}, &MockGeocoder{}, cfg)
+			pipeline.sources = []Source{&MockSource{
+				name:     "slow",
+				alerts:   []models.Alert{{ID: "alert-1", Title: "Test"}},
+				interval: time.Hour,
+			}}
+
+			go pipeline.Run(context.Background())
+
+			select {
+			case <-store.started:
+			case <-time.After(2 * time.Second):
+				t.Fatal("Timed out waiting for batch to start")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+			defer cancel()
+
+			start := time.Now()
+			err := pipeline.Shutdown(ctx)
+			elapsed := time.Since(start)
+
+			select {
+			case <-store.finished:
+				if !tt.expectDrained {
+					t.Error("Expected Shutdown to return before the batch finished")
+				}
+			default:
+				if tt.expectDrained {
+					t.Error("Expected Shutdown to block until the batch finished")
+				}
+			}
+
+			if tt.expectDrained {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(store.alerts) != 1 {
+					t.Errorf("Expected in-flight alert to be stored, got %d", len(store.alerts))
+				}
+			} else {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("Expected deadline exceeded, got %v", err)
+				}
+				if elapsed > time.Second {
+					t.Errorf("Expected Shutdown to return at the deadline, took %v", elapsed)
+				}
+			}
+		})
+	}
+}
+
+func TestPipeline_Shutdown_NotRunning(t *testing.T) {
+	pipeline := New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{WorkerCount: 1})
+
+	if err := pipeline.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func BenchmarkPipeline_ProcessBatch(b *testing.B) {
+	logger.Init("error", "text")
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			geo := &slowGeocoder{latency: time.Millisecond}
+			pipeline := New(&MockStore{}, &MockClassifier{}, geo, config.PipelineConfig{RateLimit: 5.0, WorkerCount: workers})
+
+			for i := 0; i < b.N; i++ {
+				alerts := make([]models.Alert, 100)
+				for j := range alerts {
+					alerts[j] = models.Alert{Title: fmt.Sprintf("Alert %d", j), URL: fmt.Sprintf("http://example.com/%d", j)}
+				}
+				if err := pipeline.processBatch(context.Background(), "bench-source", alerts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
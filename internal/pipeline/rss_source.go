@@ -1,35 +1,144 @@
 package pipeline
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"golang.org/x/sync/errgroup"
 )
 
 // RSSSource implements Source for RSS feeds
 type RSSSource struct {
-	name     string
-	urls     []string
-	interval time.Duration
-	client   *http.Client
+	name        string
+	urls        []string
+	interval    time.Duration
+	client      *http.Client
+	maxBodySize int64
+	headers     http.Header
+
+	mu         sync.Mutex
+	validators map[string]cacheValidators
+}
+
+// cacheValidators holds the HTTP cache validators last returned for a feed URL
+type cacheValidators struct {
+	etag         string
+	lastModified string
+}
+
+// DefaultRSSInterval is the polling interval used when none is configured
+const DefaultRSSInterval = 15 * time.Minute
+
+// DefaultMaxFeedSize caps how many bytes of a feed response are read
+const DefaultMaxFeedSize = 5 << 20
+
+// maxConcurrentFetches caps how many of a source's URLs are fetched at
+// once, matching the transport's idle connections per host
+const maxConcurrentFetches = 4
+
+// defaultUserAgent identifies feed requests unless a source overrides it
+const defaultUserAgent = "SupplyChain-Monitor/1.0"
+
+// feedAccept is the Accept header sent when fetching RSS and Atom feeds
+const feedAccept = "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.1"
+
+// feedTransport is shared by all feed sources so that connections to the
+// same host are pooled and reused across polls
+var feedTransport = &http.Transport{
+	Proxy:                 http.ProxyFromEnvironment,
+	DialContext:           (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   4,
+	MaxConnsPerHost:       8,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 20 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+// newFeedClient returns an HTTP client using the shared feed transport
+func newFeedClient() *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: feedTransport,
+	}
 }
 
-// NewRSSSource creates a new RSS source
-func NewRSSSource(name string, urls []string) *RSSSource {
-	return &RSSSource{
-		name:     name,
-		urls:     urls,
-		interval: 15 * time.Minute, // Default polling interval
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// RSSOption configures an RSSSource
+type RSSOption func(*RSSSource)
+
+// WithMaxBodySize caps the bytes read from each feed response; feeds larger
+// than n fail to fetch. A non-positive n keeps DefaultMaxFeedSize.
+func WithMaxBodySize(n int64) RSSOption {
+	return func(r *RSSSource) {
+		if n > 0 {
+			r.maxBodySize = n
+		}
 	}
 }
 
+// WithHeaders sends headers with every request, overriding the default
+// User-Agent and Accept headers
+func WithHeaders(headers map[string]string) RSSOption {
+	return func(r *RSSSource) {
+		r.headers = feedHeaders(headers)
+	}
+}
+
+// feedHeaders converts configured headers to canonical form
+func feedHeaders(headers map[string]string) http.Header {
+	h := make(http.Header, len(headers))
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+	return h
+}
+
+// setFeedHeaders sets the default User-Agent and accept on req, then the
+// source's own headers, which take precedence
+func setFeedHeaders(req *http.Request, accept string, custom http.Header) {
+	req.Header.Set("User-Agent", defaultUserAgent)
+	req.Header.Set("Accept", accept)
+	for name, values := range custom {
+		req.Header[name] = values
+	}
+}
+
+// NewRSSSource creates a new RSS source polled every interval.
+// A non-positive interval uses DefaultRSSInterval.
+func NewRSSSource(name string, urls []string, interval time.Duration, opts ...RSSOption) *RSSSource {
+	if interval <= 0 {
+		interval = DefaultRSSInterval
+	}
+
+	r := &RSSSource{
+		name:        name,
+		urls:        urls,
+		interval:    interval,
+		client:      newFeedClient(),
+		maxBodySize: DefaultMaxFeedSize,
+		validators:  make(map[string]cacheValidators),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
 // Name returns the source name
 func (r *RSSSource) Name() string {
 	return r.name
@@ -40,20 +149,90 @@ func (r *RSSSource) Interval() time.Duration {
 	return r.interval
 }
 
-// Fetch fetches alerts from RSS feeds
+// Fetch fetches alerts from RSS feeds, up to maxConcurrentFetches URLs at
+// a time. Alerts keep the order of r.urls. A failed URL doesn't stop the
+// others; its error is returned, joined with any others, alongside the
+// alerts that were fetched.
 func (r *RSSSource) Fetch(ctx context.Context) ([]models.Alert, error) {
+	results := make([][]models.Alert, len(r.urls))
+	errs := make([]error, len(r.urls))
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentFetches)
+	for i, url := range r.urls {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			results[i], errs[i] = r.fetchFromURL(ctx, url)
+			logURLError(r.name, url, errs[i])
+			return nil
+		})
+	}
+	g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var allAlerts []models.Alert
+	for _, alerts := range results {
+		allAlerts = append(allAlerts, alerts...)
+	}
+
+	return allAlerts, urlErrors(r.urls, errs)
+}
+
+// URLFetcher is implemented by sources that poll several URLs. FetchURL
+// fetches just one of them, returning its error instead of moving on.
+type URLFetcher interface {
+	Source
+	FetchURL(ctx context.Context, url string) ([]models.Alert, error)
+}
+
+// FeedFormatError reports a feed that was fetched but could not be parsed
+type FeedFormatError struct {
+	Err error
+}
 
-	for _, url := range r.urls {
-		alerts, err := r.fetchFromURL(ctx, url)
+func (e *FeedFormatError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FeedFormatError) Unwrap() error {
+	return e.Err
+}
+
+// logURLError logs a failed fetch of one of a source's URLs
+func logURLError(source, url string, err error) {
+	if err != nil {
+		logger.Warn("Feed URL fetch failed", "source", source, "url", url, "error", err)
+	}
+}
+
+// urlErrors combines the errors of a source's URLs, each prefixed with its
+// URL, into one error. It is nil when every URL succeeded.
+func urlErrors(urls []string, errs []error) error {
+	var failed []error
+	for i, err := range errs {
 		if err != nil {
-			// Log error but continue with other URLs
-			continue
+			failed = append(failed, fmt.Errorf("%s: %w", urls[i], err))
 		}
-		allAlerts = append(allAlerts, alerts...)
 	}
 
-	return allAlerts, nil
+	switch len(failed) {
+	case 0:
+		return nil
+	case len(urls):
+		return fmt.Errorf("all %d URLs failed: %w", len(urls), errors.Join(failed...))
+	default:
+		return fmt.Errorf("%d of %d URLs failed: %w", len(failed), len(urls), errors.Join(failed...))
+	}
+}
+
+// FetchURL fetches and parses one of the source's feed URLs
+func (r *RSSSource) FetchURL(ctx context.Context, url string) ([]models.Alert, error) {
+	return r.fetchFromURL(ctx, url)
 }
 
 // fetchFromURL fetches and parses RSS from a single URL
@@ -63,7 +242,18 @@ func (r *RSSSource) fetchFromURL(ctx context.Context, url string) ([]models.Aler
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "SupplyChain-Monitor/1.0")
+	setFeedHeaders(req, feedAccept, r.headers)
+
+	r.mu.Lock()
+	cached := r.validators[url]
+	r.mu.Unlock()
+
+	if cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
@@ -71,16 +261,88 @@ func (r *RSSSource) fetchFromURL(ctx context.Context, url string) ([]models.Aler
 	}
 	defer resp.Body.Close()
 
+	// Feed unchanged since the last fetch
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
 
-	var rss RSS
-	if err := xml.NewDecoder(resp.Body).Decode(&rss); err != nil {
-		return nil, fmt.Errorf("parse RSS: %w", err)
+	// Read one byte past the limit to tell a full-size feed from an oversized one
+	body, err := io.ReadAll(io.LimitReader(resp.Body, r.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read feed: %w", err)
+	}
+	if int64(len(body)) > r.maxBodySize {
+		return nil, fmt.Errorf("feed exceeds %d bytes", r.maxBodySize)
+	}
+
+	alerts, err := r.parseFeed(body)
+	if err != nil {
+		return nil, &FeedFormatError{Err: err}
+	}
+
+	// Only remember validators once the feed has been parsed successfully
+	r.mu.Lock()
+	r.validators[url] = cacheValidators{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
 	}
+	r.mu.Unlock()
 
-	return r.convertToAlerts(rss), nil
+	return alerts, nil
+}
+
+// parseFeed detects whether body is an RSS or Atom document and converts it to alerts
+func (r *RSSSource) parseFeed(body []byte) ([]models.Alert, error) {
+	root, err := feedRoot(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse feed: %w", err)
+	}
+
+	switch root {
+	case "rss":
+		var rss RSS
+		if err := xml.Unmarshal(body, &rss); err != nil {
+			return nil, fmt.Errorf("parse RSS: %w", err)
+		}
+		return r.convertToAlerts(rss), nil
+	case "feed":
+		var atom Atom
+		if err := xml.Unmarshal(body, &atom); err != nil {
+			return nil, fmt.Errorf("parse Atom: %w", err)
+		}
+		return r.convertAtomToAlerts(atom), nil
+	default:
+		return nil, fmt.Errorf("unsupported feed root element: %s", root)
+	}
+}
+
+// feedRoot returns the local name of the document's root element
+func feedRoot(body []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// rawJSON encodes a feed item as compact JSON for Alert.Raw, returning the
+// raw value and its format. Items that fail to encode are stored without
+// raw data rather than dropped.
+func rawJSON(item any) (string, string) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return "", ""
+	}
+	return string(data), models.RawFormatJSON
 }
 
 // convertToAlerts converts RSS items to Alert models
@@ -95,8 +357,8 @@ func (r *RSSSource) convertToAlerts(rss RSS) []models.Alert {
 			URL:        item.Link,
 			DetectedAt: time.Now().UTC(),
 			Confidence: 0.7, // Default confidence for RSS feeds
-			Raw:        fmt.Sprintf("%+v", item),
 		}
+		alert.Raw, alert.RawFormat = rawJSON(item)
 
 		// Parse published date
 		if item.PubDate != "" {
@@ -113,6 +375,43 @@ func (r *RSSSource) convertToAlerts(rss RSS) []models.Alert {
 	return alerts
 }
 
+// convertAtomToAlerts converts Atom entries to Alert models
+func (r *RSSSource) convertAtomToAlerts(feed Atom) []models.Alert {
+	var alerts []models.Alert
+
+	for _, entry := range feed.Entries {
+		summary := entry.Summary
+		if summary == "" {
+			summary = entry.Content
+		}
+
+		alert := models.Alert{
+			Source:     r.name,
+			Title:      entry.Title,
+			Summary:    summary,
+			URL:        entry.AlternateLink(),
+			DetectedAt: time.Now().UTC(),
+			Confidence: 0.7, // Default confidence for feeds
+		}
+		alert.Raw, alert.RawFormat = rawJSON(entry)
+
+		// Prefer published, fall back to updated
+		for _, ts := range []string{entry.Published, entry.Updated} {
+			if ts == "" {
+				continue
+			}
+			if pubDate, err := time.Parse(time.RFC3339, ts); err == nil {
+				alert.PublishedAt = pubDate
+				break
+			}
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts
+}
+
 // RSS represents the RSS feed structure
 type RSS struct {
 	XMLName xml.Name `xml:"rss"`
@@ -127,11 +426,45 @@ type Channel struct {
 	Items       []Item `xml:"item"`
 }
 
-// Item represents an RSS item
+// Item represents an RSS item. The JSON tags shape Alert.Raw.
 type Item struct {
-	Title       string `xml:"title"`
-	Description string `xml:"description"`
-	Link        string `xml:"link"`
-	PubDate     string `xml:"pubDate"`
-	GUID        string `xml:"guid"`
+	Title       string `xml:"title" json:"title"`
+	Description string `xml:"description" json:"description,omitempty"`
+	Link        string `xml:"link" json:"link,omitempty"`
+	PubDate     string `xml:"pubDate" json:"pub_date,omitempty"`
+	GUID        string `xml:"guid" json:"guid,omitempty"`
+}
+
+// Atom represents an Atom 1.0 feed
+type Atom struct {
+	XMLName xml.Name    `xml:"feed"`
+	Title   string      `xml:"title"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomEntry represents an Atom entry. The JSON tags shape Alert.Raw.
+type AtomEntry struct {
+	ID        string     `xml:"id" json:"id"`
+	Title     string     `xml:"title" json:"title"`
+	Summary   string     `xml:"summary" json:"summary,omitempty"`
+	Content   string     `xml:"content" json:"content,omitempty"`
+	Links     []AtomLink `xml:"link" json:"links,omitempty"`
+	Published string     `xml:"published" json:"published,omitempty"`
+	Updated   string     `xml:"updated" json:"updated,omitempty"`
+}
+
+// AtomLink represents an Atom link element
+type AtomLink struct {
+	Href string `xml:"href,attr" json:"href"`
+	Rel  string `xml:"rel,attr" json:"rel,omitempty"`
+}
+
+// AlternateLink returns the entry's alternate link; a link without rel is alternate
+func (e AtomEntry) AlternateLink() string {
+	for _, link := range e.Links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
 }
@@ -1,16 +1,24 @@
 package pipeline
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/rajasatyajit/SupplyChain/config"
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
 )
 
 func TestRSSSource_Name(t *testing.T) {
-	source := NewRSSSource("Test Source", []string{"http://example.com/rss"})
+	source := NewRSSSource("Test Source", []string{"http://example.com/rss"}, 0)
 
 	if source.Name() != "Test Source" {
 		t.Errorf("Expected name 'Test Source', got %s", source.Name())
@@ -18,7 +26,7 @@ func TestRSSSource_Name(t *testing.T) {
 }
 
 func TestRSSSource_Interval(t *testing.T) {
-	source := NewRSSSource("Test Source", []string{"http://example.com/rss"})
+	source := NewRSSSource("Test Source", []string{"http://example.com/rss"}, 0)
 
 	expected := 15 * time.Minute
 	if source.Interval() != expected {
@@ -26,6 +34,14 @@ func TestRSSSource_Interval(t *testing.T) {
 	}
 }
 
+func TestRSSSource_CustomInterval(t *testing.T) {
+	source := NewRSSSource("Test Source", []string{"http://example.com/rss"}, time.Minute)
+
+	if source.Interval() != time.Minute {
+		t.Errorf("Expected interval %v, got %v", time.Minute, source.Interval())
+	}
+}
+
 func TestRSSSource_Fetch(t *testing.T) {
 	// Mock RSS feed
 	rssContent := `<?xml version="1.0" encoding="UTF-8"?>
@@ -59,7 +75,7 @@ func TestRSSSource_Fetch(t *testing.T) {
 	}))
 	defer server.Close()
 
-	source := NewRSSSource("Test Source", []string{server.URL})
+	source := NewRSSSource("Test Source", []string{server.URL}, 0)
 	ctx := context.Background()
 
 	alerts, err := source.Fetch(ctx)
@@ -99,14 +115,121 @@ func TestRSSSource_Fetch(t *testing.T) {
 	}
 }
 
+func TestRSSSource_FetchAtom(t *testing.T) {
+	atomContent := `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Port Authority Updates</title>
+  <entry>
+    <id>urn:uuid:1</id>
+    <title>Terminal Closure</title>
+    <link rel="self" href="http://example.com/self/1"/>
+    <link rel="alternate" href="http://example.com/news/1"/>
+    <summary>Terminal closed due to weather</summary>
+    <published>2024-01-15T10:00:00Z</published>
+    <updated>2024-01-15T12:00:00Z</updated>
+  </entry>
+  <entry>
+    <id>urn:uuid:2</id>
+    <title>Berth Congestion</title>
+    <link href="http://example.com/news/2"/>
+    <content>Vessels waiting at anchor</content>
+    <updated>2024-01-15T11:00:00Z</updated>
+  </entry>
+</feed>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(atomContent))
+	}))
+	defer server.Close()
+
+	source := NewRSSSource("Test Source", []string{server.URL}, 0)
+
+	alerts, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatalf("Expected 2 alerts, got %d", len(alerts))
+	}
+
+	if alerts[0].Title != "Terminal Closure" || alerts[0].URL != "http://example.com/news/1" {
+		t.Errorf("Unexpected first alert: %+v", alerts[0])
+	}
+	if alerts[0].Summary != "Terminal closed due to weather" {
+		t.Errorf("Expected summary from <summary>, got %s", alerts[0].Summary)
+	}
+	if !alerts[0].PublishedAt.Equal(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected published date from <published>, got %v", alerts[0].PublishedAt)
+	}
+
+	if alerts[1].URL != "http://example.com/news/2" || alerts[1].Summary != "Vessels waiting at anchor" {
+		t.Errorf("Unexpected second alert: %+v", alerts[1])
+	}
+	if !alerts[1].PublishedAt.Equal(time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected published date from <updated>, got %v", alerts[1].PublishedAt)
+	}
+}
+
+func TestRSSSource_FetchNotModified(t *testing.T) {
+	rssContent := `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Feed</title>
+  <item><title>Port Strike</title><link>http://example.com/1</link></item>
+</channel></rss>`
+
+	var requests int
+	var gotETag, gotModifiedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			gotETag = r.Header.Get("If-None-Match")
+			gotModifiedSince = r.Header.Get("If-Modified-Since")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 15 Jan 2024 10:00:00 GMT")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(rssContent))
+	}))
+	defer server.Close()
+
+	source := NewRSSSource("Test Source", []string{server.URL}, 0)
+	ctx := context.Background()
+
+	alerts, err := source.Fetch(ctx)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert on first fetch, got %d (err %v)", len(alerts), err)
+	}
+
+	alerts, err = source.Fetch(ctx)
+	if err != nil {
+		t.Errorf("Expected no error on 304, got %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("Expected 0 alerts on 304, got %d", len(alerts))
+	}
+
+	if gotETag != `"v1"` {
+		t.Errorf("Expected If-None-Match \"v1\", got %q", gotETag)
+	}
+	if gotModifiedSince != "Mon, 15 Jan 2024 10:00:00 GMT" {
+		t.Errorf("Expected If-Modified-Since to echo Last-Modified, got %q", gotModifiedSince)
+	}
+}
+
 func TestRSSSource_FetchError(t *testing.T) {
+	logger.Init("error", "text")
+
 	// Test with invalid URL
-	source := NewRSSSource("Test Source", []string{"http://invalid-url-that-does-not-exist.com/rss"})
+	source := NewRSSSource("Test Source", []string{"http://invalid-url-that-does-not-exist.com/rss"}, 0)
 	ctx := context.Background()
 
 	alerts, err := source.Fetch(ctx)
-	if err != nil {
-		t.Errorf("Expected no error (should continue with other URLs), got %v", err)
+	if err == nil || !strings.Contains(err.Error(), "all 1 URLs failed") {
+		t.Errorf("Expected error when every URL fails, got %v", err)
 	}
 
 	// Should return empty slice when all URLs fail
@@ -115,19 +238,92 @@ func TestRSSSource_FetchError(t *testing.T) {
 	}
 }
 
+func TestRSSSource_FetchConcurrent(t *testing.T) {
+	logger.Init("error", "text")
+
+	const delay = 200 * time.Millisecond
+
+	var urls []string
+	for i := 0; i < maxConcurrentFetches; i++ {
+		i := i
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			fmt.Fprintf(w, `<rss version="2.0"><channel><item><title>Feed %d</title><link>https://example.com/%d</link></item></channel></rss>`, i, i)
+		}))
+		defer server.Close()
+		urls = append(urls, server.URL)
+	}
+
+	// A failing URL is skipped without holding up the others
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	urls = append([]string{broken.URL}, urls...)
+
+	source := NewRSSSource("Test Source", urls, 0)
+	start := time.Now()
+	alerts, err := source.Fetch(context.Background())
+	elapsed := time.Since(start)
+	if err == nil || !strings.Contains(err.Error(), "1 of 5 URLs failed") || !strings.Contains(err.Error(), broken.URL) {
+		t.Errorf("Expected the broken URL's error, got %v", err)
+	}
+
+	if len(alerts) != maxConcurrentFetches {
+		t.Fatalf("Expected %d alerts, got %d", maxConcurrentFetches, len(alerts))
+	}
+	for i, alert := range alerts {
+		if want := fmt.Sprintf("Feed %d", i); alert.Title != want {
+			t.Errorf("Expected alerts in URL order, got %q at %d", alert.Title, i)
+		}
+	}
+
+	// Sequential fetches would take maxConcurrentFetches * delay
+	if elapsed >= 2*delay {
+		t.Errorf("Expected concurrent fetches to take about %v, took %v", delay, elapsed)
+	}
+}
+
+func TestRSSSource_FetchCanceled(t *testing.T) {
+	logger.Init("error", "text")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	source := NewRSSSource("Test Source", []string{server.URL, server.URL + "/other"}, 0)
+	start := time.Now()
+	_, err := source.Fetch(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected fetch to stop on cancellation, took %v", elapsed)
+	}
+}
+
 func TestRSSSource_FetchHTTPError(t *testing.T) {
+	logger.Init("error", "text")
+
 	// Create test server that returns error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer server.Close()
 
-	source := NewRSSSource("Test Source", []string{server.URL})
+	source := NewRSSSource("Test Source", []string{server.URL}, 0)
 	ctx := context.Background()
 
 	alerts, err := source.Fetch(ctx)
-	if err != nil {
-		t.Errorf("Expected no error (should continue with other URLs), got %v", err)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 500") {
+		t.Errorf("Expected HTTP error, got %v", err)
 	}
 
 	if len(alerts) != 0 {
@@ -135,7 +331,34 @@ func TestRSSSource_FetchHTTPError(t *testing.T) {
 	}
 }
 
+func TestRSSSource_FetchLogsFailedURL(t *testing.T) {
+	var buf bytes.Buffer
+	logger.InitWriter(&buf, "warn", "text")
+	defer logger.Init("error", "text")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	for _, src := range []Source{
+		NewRSSSource("Broken RSS", []string{server.URL}, 0),
+		NewJSONFeedSource("Broken JSON", []string{server.URL}, 0),
+	} {
+		buf.Reset()
+		if _, err := src.Fetch(context.Background()); err == nil {
+			t.Errorf("%s: expected fetch error", src.Name())
+		}
+		out := buf.String()
+		if !strings.Contains(out, "Feed URL fetch failed") || !strings.Contains(out, src.Name()) || !strings.Contains(out, server.URL) || !strings.Contains(out, "HTTP 503") {
+			t.Errorf("%s: expected failed URL to be logged with its source, got %q", src.Name(), out)
+		}
+	}
+}
+
 func TestRSSSource_FetchInvalidXML(t *testing.T) {
+	logger.Init("error", "text")
+
 	// Create test server with invalid XML
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/rss+xml")
@@ -144,12 +367,12 @@ func TestRSSSource_FetchInvalidXML(t *testing.T) {
 	}))
 	defer server.Close()
 
-	source := NewRSSSource("Test Source", []string{server.URL})
+	source := NewRSSSource("Test Source", []string{server.URL}, 0)
 	ctx := context.Background()
 
 	alerts, err := source.Fetch(ctx)
-	if err != nil {
-		t.Errorf("Expected no error (should continue with other URLs), got %v", err)
+	if err == nil || !strings.Contains(err.Error(), "parse feed") {
+		t.Errorf("Expected parse error, got %v", err)
 	}
 
 	if len(alerts) != 0 {
@@ -157,8 +380,44 @@ func TestRSSSource_FetchInvalidXML(t *testing.T) {
 	}
 }
 
+func TestRSSSource_FetchOversized(t *testing.T) {
+	logger.Init("error", "text")
+
+	var gotAccept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>`))
+		// Stream far more than the limit; the reader must stop early
+		chunk := []byte(strings.Repeat("x", 1024))
+		for i := 0; i < 1024; i++ {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+		w.Write([]byte(`</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	source := NewRSSSource("Test Source", []string{server.URL}, 0, WithMaxBodySize(64<<10))
+
+	_, err := source.fetchFromURL(context.Background(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Expected oversized feed error, got %v", err)
+	}
+
+	alerts, err := source.Fetch(context.Background())
+	if err == nil || len(alerts) != 0 {
+		t.Errorf("Expected oversized feed to fail, got %d alerts (err %v)", len(alerts), err)
+	}
+
+	if !strings.Contains(gotAccept, "application/rss+xml") {
+		t.Errorf("Expected feed Accept header, got %q", gotAccept)
+	}
+}
+
 func TestRSSSource_ConvertToAlerts(t *testing.T) {
-	source := NewRSSSource("Test Source", []string{})
+	source := NewRSSSource("Test Source", []string{}, 0)
 
 	rss := RSS{
 		Channel: Channel{
@@ -210,8 +469,77 @@ func TestRSSSource_ConvertToAlerts(t *testing.T) {
 		t.Error("Expected published date to be zero for invalid date")
 	}
 
-	// Check that raw field contains item data
-	if !strings.Contains(alert1.Raw, "Test Item 1") {
-		t.Error("Expected raw field to contain item data")
+	// Check that raw field holds the item as JSON
+	if alert1.RawFormat != models.RawFormatJSON {
+		t.Errorf("Expected raw format %q, got %q", models.RawFormatJSON, alert1.RawFormat)
+	}
+	var raw Item
+	if err := json.Unmarshal([]byte(alert1.Raw), &raw); err != nil {
+		t.Fatalf("Expected raw field to be JSON, got %q: %v", alert1.Raw, err)
+	}
+	if raw.Title != "Test Item 1" {
+		t.Errorf("Expected raw item title 'Test Item 1', got %q", raw.Title)
+	}
+}
+
+func TestNewSource_Headers(t *testing.T) {
+	for _, typ := range []string{"rss", "jsonfeed"} {
+		t.Run(typ, func(t *testing.T) {
+			var got http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				w.WriteHeader(http.StatusNotModified)
+			}))
+			defer server.Close()
+
+			src, err := NewSource(config.SourceConfig{
+				Name: "Feed",
+				Type: typ,
+				URLs: []string{server.URL},
+				Headers: map[string]string{
+					"user-agent":    "Acme-Feeds/2.0",
+					"Authorization": "Bearer s3cret",
+				},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := src.Fetch(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if got.Get("User-Agent") != "Acme-Feeds/2.0" {
+				t.Errorf("Expected configured User-Agent, got %q", got.Get("User-Agent"))
+			}
+			if got.Get("Authorization") != "Bearer s3cret" {
+				t.Errorf("Expected configured Authorization, got %q", got.Get("Authorization"))
+			}
+			if got.Get("Accept") == "" {
+				t.Error("Expected default Accept header to be kept")
+			}
+		})
+	}
+}
+
+func TestNew_RedactsSourceHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	logger.InitWriter(&buf, "debug", "text")
+	defer logger.Init("error", "text")
+
+	New(&MockStore{}, &MockClassifier{}, &MockGeocoder{}, config.PipelineConfig{
+		WorkerCount: 1,
+		Sources: []config.SourceConfig{{
+			Name:    "Feed",
+			Type:    "rss",
+			URLs:    []string{"http://example.com/rss"},
+			Headers: map[string]string{"Authorization": "Bearer s3cret"},
+		}},
+	})
+
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("Expected header secret to be redacted, got %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Authorization") {
+		t.Errorf("Expected header names to be logged, got %s", buf.String())
 	}
 }
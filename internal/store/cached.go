@@ -0,0 +1,200 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// CachedStore wraps a Store with an in-memory LRU cache of GetAlert results
+// keyed by alert ID. Entries expire after a TTL and are dropped when the
+// alert is upserted or overridden through the CachedStore; archiving clears
+// the whole cache. Alerts that are not found are never cached.
+type CachedStore struct {
+	Store
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+
+	// reads tracks the GetAlert misses in flight per ID, and epoch counts
+	// cache clears, so a read that raced an invalidation isn't cached
+	reads map[string]*pendingRead
+	epoch uint64
+}
+
+// pendingRead counts the misses reading an alert from the wrapped store and
+// the invalidations of that alert since the first of them started
+type pendingRead struct {
+	readers    int
+	generation uint64
+}
+
+type alertCacheEntry struct {
+	id      string
+	alert   models.Alert
+	expires time.Time
+}
+
+// watermarkStore is the part of Store the pipeline's watermarking needs,
+// forwarded by CachedStore so wrapping a store doesn't disable it
+type watermarkStore interface {
+	SourceWatermark(ctx context.Context, source string) (time.Time, error)
+	SetSourceWatermark(ctx context.Context, source string, at time.Time) error
+}
+
+// NewCachedStore wraps s with a cache holding up to size alerts for ttl.
+// A size below 1 or a non-positive ttl disables caching and returns s.
+func NewCachedStore(s Store, size int, ttl time.Duration) Store {
+	if size < 1 || ttl <= 0 {
+		return s
+	}
+	return &CachedStore{
+		Store:   s,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		reads:   make(map[string]*pendingRead),
+	}
+}
+
+// GetAlert returns the cached alert for id or reads it from the wrapped
+// store. A read that an invalidation of id overlapped may have returned the
+// old alert, so it is returned but not cached.
+func (c *CachedStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[id]; ok {
+		e := elem.Value.(*alertCacheEntry)
+		if c.now().Before(e.expires) {
+			c.order.MoveToFront(elem)
+			alert := e.alert
+			c.mu.Unlock()
+			return &alert, nil
+		}
+		c.remove(elem)
+	}
+	read, ok := c.reads[id]
+	if !ok {
+		read = &pendingRead{}
+		c.reads[id] = read
+	}
+	read.readers++
+	generation, epoch := read.generation, c.epoch
+	c.mu.Unlock()
+
+	alert, err := c.Store.GetAlert(ctx, id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if read.readers--; read.readers == 0 {
+		delete(c.reads, id)
+	}
+	if err != nil || alert == nil {
+		return alert, err
+	}
+	if read.generation != generation || c.epoch != epoch {
+		return alert, nil
+	}
+
+	if elem, ok := c.entries[id]; ok {
+		c.remove(elem)
+	}
+	c.entries[id] = c.order.PushFront(&alertCacheEntry{id: id, alert: *alert, expires: c.now().Add(c.ttl)})
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	result := *alert
+	return &result, nil
+}
+
+// UpsertAlerts stores alerts and drops their cached copies
+func (c *CachedStore) UpsertAlerts(ctx context.Context, alerts []models.Alert) error {
+	err := c.Store.UpsertAlerts(ctx, alerts)
+
+	// Invalidate even on error, as a partial write may have changed some rows
+	ids := make([]string, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	c.invalidate(ids...)
+
+	return err
+}
+
+// OverrideAlert stores a correction and drops the alert's cached copy
+func (c *CachedStore) OverrideAlert(ctx context.Context, o models.AlertOverride) (*models.Alert, error) {
+	alert, err := c.Store.OverrideAlert(ctx, o)
+	c.invalidate(o.AlertID)
+	return alert, err
+}
+
+// ArchiveOlderThan archives old alerts and clears the cache, since any
+// cached alert may have been archived
+func (c *CachedStore) ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	n, err := c.Store.ArchiveOlderThan(ctx, cutoff)
+
+	c.mu.Lock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.epoch++
+	c.mu.Unlock()
+
+	return n, err
+}
+
+// SourceWatermark forwards to the wrapped store, reporting no watermark
+// when it doesn't keep them
+func (c *CachedStore) SourceWatermark(ctx context.Context, source string) (time.Time, error) {
+	if wm, ok := c.Store.(watermarkStore); ok {
+		return wm.SourceWatermark(ctx, source)
+	}
+	return time.Time{}, nil
+}
+
+// SetSourceWatermark forwards to the wrapped store, if it keeps watermarks
+func (c *CachedStore) SetSourceWatermark(ctx context.Context, source string, at time.Time) error {
+	if wm, ok := c.Store.(watermarkStore); ok {
+		return wm.SetSourceWatermark(ctx, source, at)
+	}
+	return nil
+}
+
+// Len returns the number of cached alerts, including expired ones not yet
+// evicted
+func (c *CachedStore) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// invalidate drops the cached copies of ids and keeps reads of them already
+// in flight from being cached
+func (c *CachedStore) invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		if elem, ok := c.entries[id]; ok {
+			c.remove(elem)
+		}
+		if read, ok := c.reads[id]; ok {
+			read.generation++
+		}
+	}
+}
+
+// remove evicts elem; c.mu must be held
+func (c *CachedStore) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*alertCacheEntry).id)
+}
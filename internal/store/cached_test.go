@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// countingStore counts GetAlert calls reaching the wrapped store
+type countingStore struct {
+	*InMemoryStore
+	gets int
+}
+
+func (s *countingStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
+	s.gets++
+	return s.InMemoryStore.GetAlert(ctx, id)
+}
+
+func newCachedTestStore(t *testing.T, size int) (*CachedStore, *countingStore, *time.Time) {
+	t.Helper()
+	inner := &countingStore{InMemoryStore: NewInMemoryStore()}
+	alerts := []models.Alert{
+		{ID: "alert-1", Title: "Port Strike", Severity: "high"},
+		{ID: "alert-2", Title: "Canal Closure", Severity: "medium"},
+		{ID: "alert-3", Title: "Storm Warning", Severity: "low"},
+	}
+	if err := inner.UpsertAlerts(context.Background(), alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	cached := NewCachedStore(inner, size, time.Minute).(*CachedStore)
+	cached.now = func() time.Time { return now }
+	return cached, inner, &now
+}
+
+func TestCachedStore_GetAlert(t *testing.T) {
+	ctx := context.Background()
+	cached, inner, now := newCachedTestStore(t, 10)
+
+	for i := 0; i < 3; i++ {
+		alert, err := cached.GetAlert(ctx, "alert-1")
+		if err != nil || alert == nil || alert.Title != "Port Strike" {
+			t.Fatalf("Expected alert-1, got %+v (err %v)", alert, err)
+		}
+	}
+	if inner.gets != 1 {
+		t.Errorf("Expected reads within the TTL to hit the store once, got %d", inner.gets)
+	}
+
+	// Callers mutating the result must not change the cached copy
+	alert, _ := cached.GetAlert(ctx, "alert-1")
+	alert.Title = "changed"
+	if alert, _ := cached.GetAlert(ctx, "alert-1"); alert.Title != "Port Strike" {
+		t.Errorf("Expected cached copy to be unchanged, got %q", alert.Title)
+	}
+
+	*now = now.Add(time.Minute)
+	if _, err := cached.GetAlert(ctx, "alert-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if inner.gets != 2 {
+		t.Errorf("Expected an expired entry to be re-read, got %d store reads", inner.gets)
+	}
+
+	for i := 0; i < 2; i++ {
+		if alert, err := cached.GetAlert(ctx, "missing"); alert != nil || err != nil {
+			t.Fatalf("Expected nil alert for a missing ID, got %+v (err %v)", alert, err)
+		}
+	}
+	if inner.gets != 4 {
+		t.Errorf("Expected missing alerts not to be cached, got %d store reads", inner.gets)
+	}
+}
+
+func TestCachedStore_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	cached, inner, _ := newCachedTestStore(t, 10)
+
+	cached.GetAlert(ctx, "alert-1")
+	cached.GetAlert(ctx, "alert-2")
+
+	if err := cached.UpsertAlerts(ctx, []models.Alert{{ID: "alert-1", Title: "Port Strike Ends"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	alert, _ := cached.GetAlert(ctx, "alert-1")
+	if alert.Title != "Port Strike Ends" {
+		t.Errorf("Expected upsert to invalidate alert-1, got title %q", alert.Title)
+	}
+	cached.GetAlert(ctx, "alert-2")
+	if inner.gets != 3 {
+		t.Errorf("Expected only the upserted alert to be re-read, got %d store reads", inner.gets)
+	}
+
+	if _, err := cached.OverrideAlert(ctx, models.AlertOverride{AlertID: "alert-2", Severity: "high", SubmittedBy: "ops"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if alert, _ := cached.GetAlert(ctx, "alert-2"); alert.Severity != "high" {
+		t.Errorf("Expected override to invalidate alert-2, got severity %q", alert.Severity)
+	}
+
+	if _, err := cached.ArchiveOlderThan(ctx, time.Now()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cached.Len() != 0 {
+		t.Errorf("Expected archiving to clear the cache, got %d entries", cached.Len())
+	}
+}
+
+// stallingStore reads an alert, then waits for release before returning it,
+// so a write can land while the read is in flight
+type stallingStore struct {
+	*InMemoryStore
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (s *stallingStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
+	alert, err := s.InMemoryStore.GetAlert(ctx, id)
+	s.read <- struct{}{}
+	<-s.release
+	return alert, err
+}
+
+func TestCachedStore_InvalidationDuringRead(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		write func(c *CachedStore) error
+	}{
+		{"upsert", func(c *CachedStore) error {
+			return c.UpsertAlerts(ctx, []models.Alert{{ID: "alert-1", Title: "Port Strike Ends"}})
+		}},
+		{"archive", func(c *CachedStore) error {
+			_, err := c.ArchiveOlderThan(ctx, time.Now().Add(time.Hour))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &stallingStore{
+				InMemoryStore: NewInMemoryStore(),
+				read:          make(chan struct{}),
+				release:       make(chan struct{}),
+			}
+			if err := inner.InMemoryStore.UpsertAlerts(ctx, []models.Alert{{ID: "alert-1", Title: "Port Strike"}}); err != nil {
+				t.Fatalf("Failed to setup test data: %v", err)
+			}
+			cached := NewCachedStore(inner, 10, time.Minute).(*CachedStore)
+
+			done := make(chan *models.Alert)
+			go func() {
+				alert, _ := cached.GetAlert(ctx, "alert-1")
+				done <- alert
+			}()
+
+			// The read has seen the old alert; change it before the read returns
+			<-inner.read
+			if err := tt.write(cached); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			close(inner.release)
+			if stale := <-done; stale == nil || stale.Title != "Port Strike" {
+				t.Fatalf("Expected the in-flight read to return the old alert, got %+v", stale)
+			}
+
+			if cached.Len() != 0 {
+				t.Errorf("Expected the overlapped read not to be cached, got %d entries", cached.Len())
+			}
+			go func() { <-inner.read }()
+			alert, err := cached.GetAlert(ctx, "alert-1")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			want, _ := inner.InMemoryStore.GetAlert(ctx, "alert-1")
+			if alert.Title != want.Title || (alert.ArchivedAt == nil) != (want.ArchivedAt == nil) {
+				t.Errorf("Expected the current alert %+v, got %+v", want, alert)
+			}
+			if len(cached.reads) != 0 {
+				t.Errorf("Expected no reads left in flight, got %d", len(cached.reads))
+			}
+		})
+	}
+}
+
+func TestCachedStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cached, inner, _ := newCachedTestStore(t, 2)
+
+	cached.GetAlert(ctx, "alert-1")
+	cached.GetAlert(ctx, "alert-2")
+	cached.GetAlert(ctx, "alert-1") // alert-2 is now least recently used
+	cached.GetAlert(ctx, "alert-3")
+
+	if cached.Len() != 2 {
+		t.Fatalf("Expected 2 cached alerts, got %d", cached.Len())
+	}
+	gets := inner.gets
+	cached.GetAlert(ctx, "alert-1")
+	if inner.gets != gets {
+		t.Error("Expected alert-1 to stay cached")
+	}
+	cached.GetAlert(ctx, "alert-2")
+	if inner.gets != gets+1 {
+		t.Error("Expected alert-2 to be evicted")
+	}
+}
+
+func TestCachedStore_Watermarks(t *testing.T) {
+	ctx := context.Background()
+	cached, inner, _ := newCachedTestStore(t, 10)
+
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	if err := cached.SetSourceWatermark(ctx, "feed", at); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got, _ := inner.SourceWatermark(ctx, "feed"); !got.Equal(at) {
+		t.Errorf("Expected watermark to reach the wrapped store, got %v", got)
+	}
+	if got, _ := cached.SourceWatermark(ctx, "feed"); !got.Equal(at) {
+		t.Errorf("Expected watermark %v, got %v", at, got)
+	}
+}
+
+func TestNewCachedStore_Disabled(t *testing.T) {
+	inner := NewInMemoryStore()
+	for _, tt := range []struct {
+		size int
+		ttl  time.Duration
+	}{{0, time.Minute}, {10, 0}, {-1, -time.Second}} {
+		if got := NewCachedStore(inner, tt.size, tt.ttl); got != Store(inner) {
+			t.Errorf("Expected size %d, ttl %s to return the wrapped store, got %T", tt.size, tt.ttl, got)
+		}
+	}
+}
@@ -2,37 +2,130 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 )
 
 // InMemoryStore implements Store using in-memory storage
 type InMemoryStore struct {
-	mu     sync.RWMutex
-	alerts map[string]models.Alert
+	mu         sync.RWMutex
+	alerts     map[string]models.Alert
+	overrides  map[string]models.AlertOverride
+	history    map[string][]models.Alert
+	watermarks map[string]time.Time
+
+	maxAlerts int           // 0 is unbounded
+	maxAge    time.Duration // 0 is unbounded
+	now       func() time.Time
+}
+
+// MemoryOption configures an InMemoryStore
+type MemoryOption func(*InMemoryStore)
+
+// WithMaxAlerts keeps at most n alerts, evicting those detected longest ago
+// when an upsert goes over. A non-positive n is unbounded.
+func WithMaxAlerts(n int) MemoryOption {
+	return func(s *InMemoryStore) {
+		s.maxAlerts = max(n, 0)
+	}
 }
 
-// NewInMemoryStore creates a new in-memory store
-func NewInMemoryStore() *InMemoryStore {
-	return &InMemoryStore{
-		alerts: make(map[string]models.Alert),
+// WithMaxAge evicts alerts detected more than d ago whenever alerts are
+// upserted. A non-positive d is unbounded.
+func WithMaxAge(d time.Duration) MemoryOption {
+	return func(s *InMemoryStore) {
+		s.maxAge = max(d, 0)
 	}
 }
 
-// UpsertAlerts stores alerts in memory
+// NewInMemoryStore creates a new in-memory store. It keeps every alert
+// unless bounded by WithMaxAlerts or WithMaxAge.
+func NewInMemoryStore(opts ...MemoryOption) *InMemoryStore {
+	s := &InMemoryStore{
+		alerts:     make(map[string]models.Alert),
+		overrides:  make(map[string]models.AlertOverride),
+		history:    make(map[string][]models.Alert),
+		watermarks: make(map[string]time.Time),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// UpsertAlerts stores alerts in memory, keeping the prior version of any
+// alert whose content changed, then evicts alerts beyond the store's age
+// and count limits
 func (s *InMemoryStore) UpsertAlerts(ctx context.Context, alerts []models.Alert) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := s.now().UTC()
 	for _, alert := range alerts {
+		alert.CreatedAt, alert.UpdatedAt = now, now
+		if old, ok := s.alerts[alert.ID]; ok {
+			alert.CreatedAt, alert.ArchivedAt = old.CreatedAt, old.ArchivedAt
+			if contentChanged(old, alert) {
+				old.Raw, old.RawFormat, old.ArchivedAt = "", "", nil
+				s.history[alert.ID] = append(s.history[alert.ID], old)
+			}
+		}
 		s.alerts[alert.ID] = alert
 	}
+	s.evict()
 
 	return nil
 }
 
+// evict drops alerts detected before the max age, then the oldest alerts
+// beyond the max count, along with their overrides; callers hold s.mu
+func (s *InMemoryStore) evict() {
+	if s.maxAge > 0 {
+		cutoff := s.now().Add(-s.maxAge)
+		for id, alert := range s.alerts {
+			if alert.DetectedAt.Before(cutoff) {
+				s.remove(id)
+			}
+		}
+	}
+
+	if s.maxAlerts == 0 || len(s.alerts) <= s.maxAlerts {
+		return
+	}
+	all := make([]models.Alert, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		all = append(all, alert)
+	}
+	sortAlerts(all, models.SortDetectedAt, models.SortDesc)
+	for _, alert := range all[s.maxAlerts:] {
+		s.remove(alert.ID)
+	}
+}
+
+// contentChanged reports whether an upsert of b over a changes the alert,
+// matching the columns the alert_history trigger compares
+func contentChanged(a, b models.Alert) bool {
+	return a.Title != b.Title || a.Summary != b.Summary || a.URL != b.URL ||
+		!a.PublishedAt.Equal(b.PublishedAt) || a.Region != b.Region || a.Country != b.Country ||
+		a.Location != b.Location || a.Latitude != b.Latitude || a.Longitude != b.Longitude ||
+		a.Disruption != b.Disruption || a.Severity != b.Severity || a.Sentiment != b.Sentiment ||
+		a.Confidence != b.Confidence || a.Language != b.Language || a.Impact != b.Impact ||
+		a.Escalated != b.Escalated
+}
+
+// remove deletes an alert, its override and its history; callers hold s.mu
+func (s *InMemoryStore) remove(id string) {
+	delete(s.alerts, id)
+	delete(s.overrides, id)
+	delete(s.history, id)
+}
+
 // QueryAlerts retrieves alerts from memory based on query parameters
 func (s *InMemoryStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error) {
 	s.mu.RLock()
@@ -40,15 +133,13 @@ func (s *InMemoryStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([
 
 	var result []models.Alert
 	for _, alert := range s.alerts {
+		alert = s.effective(alert)
 		if q.Matches(alert) {
 			result = append(result, alert)
 		}
 	}
 
-	// Sort by DetectedAt descending
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].DetectedAt.After(result[j].DetectedAt)
-	})
+	sortAlerts(result, q.SortBy, q.SortOrder)
 
 	// Apply limit and offset
 	if q.Offset > 0 && q.Offset < len(result) {
@@ -64,18 +155,234 @@ func (s *InMemoryStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([
 	return result, nil
 }
 
+// CountAlerts counts alerts in memory matching the query filters
+func (s *InMemoryStore) CountAlerts(ctx context.Context, q models.AlertQuery) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, alert := range s.alerts {
+		if q.Matches(s.effective(alert)) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// DistinctSources returns the sorted source names of all stored alerts,
+// archived or not
+func (s *InMemoryStore) DistinctSources(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	sources := []string{}
+	for _, alert := range s.alerts {
+		if !seen[alert.Source] {
+			seen[alert.Source] = true
+			sources = append(sources, alert.Source)
+		}
+	}
+	sort.Strings(sources)
+
+	return sources, nil
+}
+
+// AggregateAlerts counts alerts in memory matching the query filters,
+// grouped by the groupBy field
+func (s *InMemoryStore) AggregateAlerts(ctx context.Context, q models.AlertQuery, groupBy string) ([]models.GroupCount, error) {
+	if !models.ValidGroupBy(groupBy) {
+		return nil, fmt.Errorf("unsupported group by field: %q", groupBy)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totals := make(map[string]int)
+	for _, alert := range s.alerts {
+		alert = s.effective(alert)
+		if q.Matches(alert) {
+			totals[alert.GroupKey(groupBy)]++
+		}
+	}
+
+	counts := make([]models.GroupCount, 0, len(totals))
+	for key, count := range totals {
+		counts = append(counts, models.GroupCount{Key: key, Count: count})
+	}
+	models.SortGroupCounts(counts)
+
+	return counts, nil
+}
+
+// sortAlerts orders alerts by field and order, matching PostgresStore.
+// Ties fall back to DetectedAt then ID, both descending.
+func sortAlerts(alerts []models.Alert, field, order string) {
+	sort.Slice(alerts, func(i, j int) bool {
+		a, b := alerts[i], alerts[j]
+
+		var cmp int
+		switch field {
+		case models.SortPublishedAt:
+			cmp = compareTimes(a.PublishedAt, b.PublishedAt)
+		case models.SortSeverity:
+			cmp = models.SeverityRank(a.Severity) - models.SeverityRank(b.Severity)
+		case models.SortConfidence:
+			cmp = compareFloats(a.Confidence, b.Confidence)
+		case models.SortImpact:
+			cmp = compareFloats(a.Impact, b.Impact)
+		}
+
+		if cmp != 0 {
+			if order == models.SortAsc {
+				return cmp < 0
+			}
+			return cmp > 0
+		}
+
+		// Default and tie-break ordering
+		tie := compareTimes(a.DetectedAt, b.DetectedAt)
+		if tie == 0 {
+			tie = strings.Compare(a.ID, b.ID)
+		}
+		if order == models.SortAsc && (field == "" || field == models.SortDetectedAt) {
+			return tie < 0
+		}
+		return tie > 0
+	})
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
 // GetAlert retrieves a single alert by ID
 func (s *InMemoryStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	if alert, exists := s.alerts[id]; exists {
+		alert = s.effective(alert)
 		return &alert, nil
 	}
 
 	return nil, nil
 }
 
+// AlertHistory returns the prior versions of an alert, oldest first
+func (s *InMemoryStore) AlertHistory(ctx context.Context, id string) ([]models.Alert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.Alert(nil), s.history[id]...), nil
+}
+
+// ChangesSince returns alerts updated after token, oldest change first, and
+// the token for the next call
+func (s *InMemoryStore) ChangesSince(ctx context.Context, token string) ([]models.Alert, string, error) {
+	var since *models.ChangeToken
+	if token != "" {
+		t, err := models.DecodeChangeToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+		since = t
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var changed []models.Alert
+	for _, alert := range s.alerts {
+		alert = s.effective(alert)
+		if since == nil || since.Before(alert) {
+			changed = append(changed, alert)
+		}
+	}
+
+	sort.Slice(changed, func(i, j int) bool {
+		if !changed[i].UpdatedAt.Equal(changed[j].UpdatedAt) {
+			return changed[i].UpdatedAt.Before(changed[j].UpdatedAt)
+		}
+		return changed[i].ID < changed[j].ID
+	})
+	if len(changed) > ChangesPageSize {
+		changed = changed[:ChangesPageSize]
+	}
+
+	if len(changed) == 0 {
+		return changed, token, nil
+	}
+	return changed, models.NewChangeToken(changed[len(changed)-1]).Encode(), nil
+}
+
+// OverrideAlert stores a correction to an alert's classification and
+// returns the corrected alert
+func (s *InMemoryStore) OverrideAlert(ctx context.Context, o models.AlertOverride) (*models.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, exists := s.alerts[o.AlertID]
+	if !exists {
+		return nil, ErrAlertNotFound
+	}
+
+	if o.UpdatedAt.IsZero() {
+		o.UpdatedAt = s.now().UTC()
+	}
+	if existing, ok := s.overrides[o.AlertID]; ok {
+		o = existing.Merge(o)
+	}
+	s.overrides[o.AlertID] = o
+
+	alert = o.Apply(alert)
+	return &alert, nil
+}
+
+// effective returns alert with any override applied; callers hold s.mu
+func (s *InMemoryStore) effective(alert models.Alert) models.Alert {
+	if o, ok := s.overrides[alert.ID]; ok {
+		return o.Apply(alert)
+	}
+	return alert
+}
+
+// ArchiveOlderThan marks alerts detected before cutoff as archived
+func (s *InMemoryStore) ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	count := 0
+	for id, alert := range s.alerts {
+		if alert.ArchivedAt == nil && alert.DetectedAt.Before(cutoff) {
+			alert.ArchivedAt = &now
+			alert.UpdatedAt = now
+			s.alerts[id] = alert
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 // Health always returns nil for in-memory store
 func (s *InMemoryStore) Health(ctx context.Context) error {
 	return nil
@@ -2,6 +2,10 @@ package store
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -57,6 +61,89 @@ func TestInMemoryStore_UpsertAlerts(t *testing.T) {
 	}
 }
 
+func TestInMemoryStore_MaxAlerts(t *testing.T) {
+	store := NewInMemoryStore(WithMaxAlerts(3))
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alert := func(id string, hours int) models.Alert {
+		return models.Alert{ID: id, Title: id, DetectedAt: base.Add(time.Duration(hours) * time.Hour)}
+	}
+
+	if err := store.UpsertAlerts(ctx, []models.Alert{alert("b", 2), alert("a", 1), alert("c", 3)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.OverrideAlert(ctx, models.AlertOverride{AlertID: "a", Severity: "high", SubmittedBy: "ops"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Going over capacity evicts the alerts detected longest ago, even when
+	// they were inserted later
+	if err := store.UpsertAlerts(ctx, []models.Alert{alert("d", 4), alert("e", 0)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	results, _ := store.QueryAlerts(ctx, models.AlertQuery{})
+	var ids []string
+	for _, a := range results {
+		ids = append(ids, a.ID)
+	}
+	if want := []string{"d", "c", "b"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected alerts %v after eviction, got %v", want, ids)
+	}
+	if _, ok := store.overrides["a"]; ok {
+		t.Error("Expected the evicted alert's override to be dropped")
+	}
+
+	// Updating a kept alert doesn't evict anything
+	if err := store.UpsertAlerts(ctx, []models.Alert{alert("b", 2)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count, _ := store.CountAlerts(ctx, models.AlertQuery{}); count != 3 {
+		t.Errorf("Expected 3 alerts, got %d", count)
+	}
+}
+
+func TestInMemoryStore_MaxAge(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	store := NewInMemoryStore(WithMaxAge(24 * time.Hour))
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	err := store.UpsertAlerts(ctx, []models.Alert{
+		{ID: "fresh", DetectedAt: now.Add(-time.Hour)},
+		{ID: "stale", DetectedAt: now.Add(-25 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if alert, _ := store.GetAlert(ctx, "stale"); alert != nil {
+		t.Error("Expected an alert older than the max age to be evicted")
+	}
+
+	now = now.Add(24 * time.Hour)
+	store.UpsertAlerts(ctx, []models.Alert{{ID: "new", DetectedAt: now}})
+	if alert, _ := store.GetAlert(ctx, "fresh"); alert != nil {
+		t.Error("Expected an alert to be evicted once it ages past the max age")
+	}
+	if count, _ := store.CountAlerts(ctx, models.AlertQuery{}); count != 1 {
+		t.Errorf("Expected 1 alert, got %d", count)
+	}
+}
+
+func TestNewInMemoryStore_Unbounded(t *testing.T) {
+	store := NewInMemoryStore(WithMaxAlerts(-1), WithMaxAge(-time.Hour))
+	ctx := context.Background()
+
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		store.UpsertAlerts(ctx, []models.Alert{{ID: string(rune('a' + i)), DetectedAt: old}})
+	}
+	if count, _ := store.CountAlerts(ctx, models.AlertQuery{}); count != 100 {
+		t.Errorf("Expected every alert to be kept, got %d", count)
+	}
+}
+
 func TestInMemoryStore_QueryAlerts(t *testing.T) {
 	store := NewInMemoryStore()
 	ctx := context.Background()
@@ -174,6 +261,280 @@ func TestInMemoryStore_QueryAlerts(t *testing.T) {
 	}
 }
 
+func TestInMemoryStore_QueryAlerts_Cursor(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alerts := []models.Alert{
+		{ID: "alert-a", DetectedAt: at},
+		{ID: "alert-b", DetectedAt: at},
+		{ID: "alert-c", DetectedAt: at.Add(time.Hour)},
+		{ID: "alert-d", DetectedAt: at.Add(-time.Hour)},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	// Walk every page of size 1 and ensure each alert is seen exactly once
+	var seen []string
+	q := models.AlertQuery{Limit: 1}
+	for i := 0; i < len(alerts)+1; i++ {
+		page, err := store.QueryAlerts(ctx, q)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		seen = append(seen, page[0].ID)
+		q.Cursor = models.NewCursor(page[0])
+	}
+
+	expected := []string{"alert-c", "alert-b", "alert-a", "alert-d"}
+	if len(seen) != len(expected) {
+		t.Fatalf("Expected pages %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Errorf("Expected pages %v, got %v", expected, seen)
+			break
+		}
+	}
+}
+
+func TestInMemoryStore_QueryAlerts_Sort(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alerts := []models.Alert{
+		{ID: "alert-1", Severity: "medium", Confidence: 0.9, Impact: 0.3, DetectedAt: at, PublishedAt: at.Add(2 * time.Hour)},
+		{ID: "alert-2", Severity: "high", Confidence: 0.5, Impact: 0.1, DetectedAt: at.Add(time.Hour), PublishedAt: at},
+		{ID: "alert-3", Severity: "low", Confidence: 0.7, Impact: 0.6, DetectedAt: at.Add(2 * time.Hour), PublishedAt: at.Add(time.Hour)},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	tests := []struct {
+		sortBy   string
+		order    string
+		expected []string
+	}{
+		{"", "", []string{"alert-3", "alert-2", "alert-1"}},
+		{"detected_at", "asc", []string{"alert-1", "alert-2", "alert-3"}},
+		{"published_at", "desc", []string{"alert-1", "alert-3", "alert-2"}},
+		{"severity", "desc", []string{"alert-2", "alert-1", "alert-3"}},
+		{"severity", "asc", []string{"alert-3", "alert-1", "alert-2"}},
+		{"confidence", "asc", []string{"alert-2", "alert-3", "alert-1"}},
+		{"impact", "desc", []string{"alert-3", "alert-1", "alert-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy+" "+tt.order, func(t *testing.T) {
+			result, err := store.QueryAlerts(ctx, models.AlertQuery{SortBy: tt.sortBy, SortOrder: tt.order})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for i, id := range tt.expected {
+				if result[i].ID != id {
+					t.Errorf("Expected order %v, got %s at position %d", tt.expected, result[i].ID, i)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryStore_CountAlerts(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	alerts := []models.Alert{
+		{ID: "alert-1", Severity: "high"},
+		{ID: "alert-2", Severity: "high"},
+		{ID: "alert-3", Severity: "low"},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	count, err := store.CountAlerts(ctx, models.AlertQuery{Severities: []string{"high"}, Limit: 1})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected count 2, got %d", count)
+	}
+}
+
+func TestInMemoryStore_QueryAlerts_MinSeverity(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	alerts := []models.Alert{
+		{ID: "alert-low", Severity: "low"},
+		{ID: "alert-medium", Severity: "medium"},
+		{ID: "alert-high", Severity: "high"},
+		{ID: "alert-unknown", Severity: "unknown"},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    models.AlertQuery
+		expected []string
+	}{
+		{"Medium and above", models.AlertQuery{MinSeverity: "medium"}, []string{"alert-high", "alert-medium"}},
+		{"High only", models.AlertQuery{MinSeverity: "high"}, []string{"alert-high"}},
+		{"Combined with exact match", models.AlertQuery{MinSeverity: "low", Severities: []string{"low", "unknown"}}, []string{"alert-low"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.QueryAlerts(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			var ids []string
+			for _, alert := range result {
+				ids = append(ids, alert.ID)
+			}
+			sort.Strings(ids)
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestInMemoryStore_AggregateAlerts(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	alerts := []models.Alert{
+		{ID: "alert-1", Severity: "high", DetectedAt: base},
+		{ID: "alert-2", Severity: "high", DetectedAt: base},
+		{ID: "alert-3", Severity: "low", DetectedAt: base},
+		{ID: "alert-4", Severity: "medium", DetectedAt: base},
+		{ID: "alert-5", Severity: "low", DetectedAt: base.Add(-48 * time.Hour)},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	counts, err := store.AggregateAlerts(ctx, models.AlertQuery{Since: base.Add(-time.Hour)}, models.GroupBySeverity)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []models.GroupCount{{Key: "high", Count: 2}, {Key: "low", Count: 1}, {Key: "medium", Count: 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("Expected %v, got %v", want, counts)
+	}
+
+	if _, err := store.AggregateAlerts(ctx, models.AlertQuery{}, "title"); err == nil {
+		t.Error("Expected error for unsupported group by field")
+	}
+}
+
+func TestInMemoryStore_DistinctSources(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	sources, err := store.DistinctSources(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sources) != 0 {
+		t.Errorf("Expected no sources in an empty store, got %v", sources)
+	}
+
+	archivedAt := time.Now()
+	alerts := []models.Alert{
+		{ID: "alert-1", Source: "Ports"},
+		{ID: "alert-2", Source: "Carriers"},
+		{ID: "alert-3", Source: "Ports"},
+		{ID: "alert-4", Source: "Weather", ArchivedAt: &archivedAt},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	sources, err = store.DistinctSources(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []string{"Carriers", "Ports", "Weather"}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("Expected %v, got %v", want, sources)
+	}
+}
+
+func TestInMemoryStore_ArchiveOlderThan(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	cutoff := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	alerts := []models.Alert{
+		{ID: "old-1", DetectedAt: cutoff.Add(-48 * time.Hour)},
+		{ID: "old-2", DetectedAt: cutoff.Add(-time.Hour)},
+		{ID: "new", DetectedAt: cutoff.Add(time.Hour)},
+	}
+	if err := store.UpsertAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	count, err := store.ArchiveOlderThan(ctx, cutoff)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 archived, got %d", count)
+	}
+
+	// Already archived alerts are not counted again
+	count, _ = store.ArchiveOlderThan(ctx, cutoff)
+	if count != 0 {
+		t.Errorf("Expected 0 newly archived, got %d", count)
+	}
+
+	results, _ := store.QueryAlerts(ctx, models.AlertQuery{})
+	if len(results) != 1 || results[0].ID != "new" {
+		t.Errorf("Expected only the unarchived alert by default, got %v", results)
+	}
+
+	results, _ = store.QueryAlerts(ctx, models.AlertQuery{IncludeArchived: true})
+	if len(results) != 3 {
+		t.Errorf("Expected 3 alerts with include_archived, got %d", len(results))
+	}
+
+	n, _ := store.CountAlerts(ctx, models.AlertQuery{})
+	if n != 1 {
+		t.Errorf("Expected count 1 excluding archived, got %d", n)
+	}
+
+	archived, _ := store.GetAlert(ctx, "old-1")
+	if archived == nil || archived.ArchivedAt == nil {
+		t.Error("Expected GetAlert to return the archived alert with ArchivedAt set")
+	}
+
+	// Re-ingesting an archived alert keeps it archived
+	if err := store.UpsertAlerts(ctx, []models.Alert{{ID: "old-1", Title: "Updated", DetectedAt: cutoff.Add(-48 * time.Hour)}}); err != nil {
+		t.Fatalf("Failed to re-upsert alert: %v", err)
+	}
+	reingested, _ := store.GetAlert(ctx, "old-1")
+	if reingested == nil || reingested.ArchivedAt == nil || !reingested.ArchivedAt.Equal(*archived.ArchivedAt) {
+		t.Errorf("Expected re-upserted alert to stay archived, got %+v", reingested)
+	}
+	results, _ = store.QueryAlerts(ctx, models.AlertQuery{})
+	if len(results) != 1 || results[0].ID != "new" {
+		t.Errorf("Expected re-upserted alert to stay out of default queries, got %v", results)
+	}
+}
+
 func TestInMemoryStore_GetAlert(t *testing.T) {
 	store := NewInMemoryStore()
 	ctx := context.Background()
@@ -214,6 +575,167 @@ func TestInMemoryStore_GetAlert(t *testing.T) {
 	})
 }
 
+func TestInMemoryStore_OverrideAlert(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	alert := models.Alert{ID: "alert-1", Source: "test-source", Severity: "low", Disruption: "port_status", DetectedAt: time.Now().UTC()}
+	if err := store.UpsertAlerts(ctx, []models.Alert{alert}); err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	if _, err := store.OverrideAlert(ctx, models.AlertOverride{AlertID: "missing", Severity: "high"}); !errors.Is(err, ErrAlertNotFound) {
+		t.Errorf("Expected ErrAlertNotFound, got %v", err)
+	}
+
+	result, err := store.OverrideAlert(ctx, models.AlertOverride{AlertID: "alert-1", Severity: "high", SubmittedBy: "ops"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Severity != "high" || result.Disruption != "port_status" {
+		t.Errorf("Expected corrected severity only, got %s/%s", result.Severity, result.Disruption)
+	}
+
+	// A later correction keeps the earlier one's fields
+	if _, err := store.OverrideAlert(ctx, models.AlertOverride{AlertID: "alert-1", Disruption: "labor_strike", SubmittedBy: "ops"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Re-ingesting the alert does not undo the correction
+	if err := store.UpsertAlerts(ctx, []models.Alert{alert}); err != nil {
+		t.Fatalf("Failed to re-upsert: %v", err)
+	}
+
+	got, _ := store.GetAlert(ctx, "alert-1")
+	if got.Severity != "high" || got.Disruption != "labor_strike" {
+		t.Errorf("Expected high/labor_strike from GetAlert, got %s/%s", got.Severity, got.Disruption)
+	}
+
+	alerts, _ := store.QueryAlerts(ctx, models.AlertQuery{Severities: []string{"high"}})
+	if len(alerts) != 1 || alerts[0].Severity != "high" {
+		t.Errorf("Expected the corrected alert to match a high severity filter, got %+v", alerts)
+	}
+	if n, _ := store.CountAlerts(ctx, models.AlertQuery{Severities: []string{"low"}}); n != 0 {
+		t.Errorf("Expected no low severity alerts, got %d", n)
+	}
+}
+
+func TestInMemoryStore_AlertHistory(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	first := models.Alert{ID: "alert-1", Title: "Port Strike", Severity: "medium", Raw: "<item/>", DetectedAt: time.Now().UTC()}
+	second := first
+	second.Severity = "high"
+
+	for _, alert := range []models.Alert{first, second} {
+		if err := store.UpsertAlerts(ctx, []models.Alert{alert}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	history, err := store.AlertHistory(ctx, "alert-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Expected two upserts to record 1 prior version, got %d", len(history))
+	}
+	if history[0].Severity != "medium" || history[0].Raw != "" {
+		t.Errorf("Expected the medium severity version without raw, got %+v", history[0])
+	}
+
+	// Re-ingesting an unchanged alert doesn't add a version
+	store.UpsertAlerts(ctx, []models.Alert{second})
+	if history, _ := store.AlertHistory(ctx, "alert-1"); len(history) != 1 {
+		t.Errorf("Expected an unchanged upsert not to be recorded, got %d versions", len(history))
+	}
+
+	if history, _ := store.AlertHistory(ctx, "missing"); len(history) != 0 {
+		t.Errorf("Expected no history for an unknown alert, got %v", history)
+	}
+}
+
+func TestInMemoryStore_ChangesSince(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	store := NewInMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.UpsertAlerts(ctx, []models.Alert{
+		{ID: "alert-2", Severity: "low", DetectedAt: now.Add(-48 * time.Hour)},
+		{ID: "alert-1", Severity: "low", DetectedAt: now},
+	})
+
+	changes, token, err := store.ChangesSince(ctx, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ids := alertIDs(changes); !reflect.DeepEqual(ids, []string{"alert-1", "alert-2"}) {
+		t.Fatalf("Expected every alert from an empty token, got %v", ids)
+	}
+
+	changes, next, err := store.ChangesSince(ctx, token)
+	if err != nil || len(changes) != 0 || next != token {
+		t.Fatalf("Expected no changes and the same token, got %v, %q (err %v)", alertIDs(changes), next, err)
+	}
+
+	// A correction and an archive are changes, though neither alert is new
+	now = now.Add(time.Minute)
+	store.OverrideAlert(ctx, models.AlertOverride{AlertID: "alert-1", Severity: "high", SubmittedBy: "ops"})
+	now = now.Add(time.Minute)
+	store.ArchiveOlderThan(ctx, now.Add(-24*time.Hour))
+
+	changes, token, err = store.ChangesSince(ctx, token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ids := alertIDs(changes); !reflect.DeepEqual(ids, []string{"alert-1", "alert-2"}) {
+		t.Fatalf("Expected the corrected then the archived alert, got %v", ids)
+	}
+	if changes[0].Severity != "high" || changes[1].ArchivedAt == nil {
+		t.Errorf("Expected the corrected severity and archived_at, got %+v", changes)
+	}
+
+	// Re-ingesting reports only the re-ingested alert
+	now = now.Add(time.Minute)
+	store.UpsertAlerts(ctx, []models.Alert{{ID: "alert-1", Severity: "medium", DetectedAt: now}})
+	changes, _, _ = store.ChangesSince(ctx, token)
+	if ids := alertIDs(changes); !reflect.DeepEqual(ids, []string{"alert-1"}) {
+		t.Errorf("Expected only the re-ingested alert, got %v", ids)
+	}
+
+	if _, _, err := store.ChangesSince(ctx, "!!!"); !errors.Is(err, models.ErrInvalidChangeToken) {
+		t.Errorf("Expected ErrInvalidChangeToken, got %v", err)
+	}
+}
+
+func TestInMemoryStore_ChangesSince_Pages(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	alerts := make([]models.Alert, ChangesPageSize+1)
+	for i := range alerts {
+		alerts[i] = models.Alert{ID: fmt.Sprintf("alert-%04d", i)}
+	}
+	store.UpsertAlerts(ctx, alerts)
+
+	first, token, _ := store.ChangesSince(ctx, "")
+	second, _, _ := store.ChangesSince(ctx, token)
+	if len(first) != ChangesPageSize || len(second) != 1 || second[0].ID != alerts[ChangesPageSize].ID {
+		t.Errorf("Expected pages of %d and 1, got %d and %v", ChangesPageSize, len(first), alertIDs(second))
+	}
+}
+
+// alertIDs returns the IDs of alerts in order
+func alertIDs(alerts []models.Alert) []string {
+	ids := make([]string, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	return ids
+}
+
 func TestInMemoryStore_Health(t *testing.T) {
 	store := NewInMemoryStore()
 	ctx := context.Background()
@@ -2,13 +2,17 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 )
 
-// PostgresStore implements Store using PostgreSQL
+// PostgresStore implements Store using PostgreSQL. Alert reads go to the
+// read replica when one is configured; writes always go to the primary.
 type PostgresStore struct {
 	db Database
 }
@@ -18,21 +22,71 @@ func NewPostgresStore(db Database) *PostgresStore {
 	return &PostgresStore{db: db}
 }
 
-// UpsertAlerts inserts or updates alerts in the database
+// upsertColumns is the number of parameters bound per alert in UpsertAlerts
+const upsertColumns = 21
+
+// maxUpsertRows keeps each INSERT under PostgreSQL's 65535 bind parameter limit
+const maxUpsertRows = 65535 / upsertColumns
+
+// UpsertAlerts inserts or updates alerts in the database using multi-row
+// INSERT statements, one per chunk of at most maxUpsertRows alerts
 func (s *PostgresStore) UpsertAlerts(ctx context.Context, alerts []models.Alert) error {
 	if len(alerts) == 0 {
 		return nil
 	}
 
-	// Use UPSERT (INSERT ... ON CONFLICT DO UPDATE)
-	query := `
+	alerts = dedupeAlerts(alerts)
+
+	for start := 0; start < len(alerts); start += maxUpsertRows {
+		end := start + maxUpsertRows
+		if end > len(alerts) {
+			end = len(alerts)
+		}
+
+		query, args := buildUpsertQuery(alerts[start:end])
+		if err := s.db.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("upsert alerts %d-%d: %w", start, end-1, err)
+		}
+	}
+
+	return nil
+}
+
+// buildUpsertQuery builds a single multi-row UPSERT (INSERT ... ON CONFLICT DO UPDATE)
+func buildUpsertQuery(alerts []models.Alert) (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString(`
 		INSERT INTO alerts (
 			id, source, title, summary, url, detected_at, published_at,
 			region, country, location, latitude, longitude, disruption,
-			severity, sentiment, confidence, raw
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+			severity, sentiment, confidence, language, raw, raw_format, impact,
+			escalated
+		) VALUES `)
+
+	args := make([]interface{}, 0, len(alerts)*upsertColumns)
+	for i, alert := range alerts {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for col := 0; col < upsertColumns; col++ {
+			if col > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "$%d", i*upsertColumns+col+1)
+		}
+		sb.WriteString(")")
+
+		args = append(args,
+			alert.ID, alert.Source, alert.Title, alert.Summary, alert.URL,
+			alert.DetectedAt, alert.PublishedAt, alert.Region, alert.Country,
+			alert.Location, alert.Latitude, alert.Longitude, alert.Disruption,
+			alert.Severity, alert.Sentiment, alert.Confidence, alert.Language,
+			alert.Raw, alert.RawFormat, alert.Impact, alert.Escalated,
 		)
+	}
+
+	sb.WriteString(`
 		ON CONFLICT (id) DO UPDATE SET
 			title = EXCLUDED.title,
 			summary = EXCLUDED.summary,
@@ -48,105 +102,498 @@ func (s *PostgresStore) UpsertAlerts(ctx context.Context, alerts []models.Alert)
 			severity = EXCLUDED.severity,
 			sentiment = EXCLUDED.sentiment,
 			confidence = EXCLUDED.confidence,
+			language = EXCLUDED.language,
 			raw = EXCLUDED.raw,
+			raw_format = EXCLUDED.raw_format,
+			impact = EXCLUDED.impact,
+			escalated = EXCLUDED.escalated,
 			updated_at = NOW()
-	`
+	`)
+
+	return sb.String(), args
+}
 
+// dedupeAlerts keeps the last occurrence of each ID, since a single
+// INSERT ... ON CONFLICT cannot update the same row twice
+func dedupeAlerts(alerts []models.Alert) []models.Alert {
+	index := make(map[string]int, len(alerts))
+	result := make([]models.Alert, 0, len(alerts))
 	for _, alert := range alerts {
-		err := s.db.Exec(ctx, query,
-			alert.ID, alert.Source, alert.Title, alert.Summary, alert.URL,
-			alert.DetectedAt, alert.PublishedAt, alert.Region, alert.Country,
-			alert.Location, alert.Latitude, alert.Longitude, alert.Disruption,
-			alert.Severity, alert.Sentiment, alert.Confidence, alert.Raw,
+		if i, ok := index[alert.ID]; ok {
+			result[i] = alert
+			continue
+		}
+		index[alert.ID] = len(result)
+		result = append(result, alert)
+	}
+	return result
+}
+
+// QueryAlerts retrieves alerts based on query parameters
+func (s *PostgresStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error) {
+	where, args := buildAlertWhere(q)
+	argIndex := len(args) + 1
+
+	query := `
+		SELECT id, source, title, summary, url, detected_at, published_at,
+			   region, country, location, latitude, longitude, disruption,
+			   severity, sentiment, confidence, language, raw, raw_format, impact, escalated,
+			   created_at, updated_at, archived_at
+		FROM alerts_effective
+	` + where
+
+	// Add ordering
+	query += " ORDER BY " + buildAlertOrderBy(q.SortBy, q.SortOrder)
+
+	// Add limit and offset
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, q.Limit)
+		argIndex++
+	}
+
+	if q.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, q.Offset)
+	}
+
+	rowsInterface, err := s.db.QueryReplica(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query alerts: %w", err)
+	}
+
+	return scanAlertRows(rowsInterface)
+}
+
+// scanAlertRows scans rows selecting the columns of alertByIDQuery
+func scanAlertRows(rowsInterface interface{}) ([]models.Alert, error) {
+	rows, ok := rowsInterface.(pgx.Rows)
+	if !ok {
+		return nil, fmt.Errorf("invalid rows type")
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		var alert models.Alert
+		err := rows.Scan(
+			&alert.ID, &alert.Source, &alert.Title, &alert.Summary, &alert.URL,
+			&alert.DetectedAt, &alert.PublishedAt, &alert.Region, &alert.Country,
+			&alert.Location, &alert.Latitude, &alert.Longitude, &alert.Disruption,
+			&alert.Severity, &alert.Sentiment, &alert.Confidence, &alert.Language,
+			&alert.Raw, &alert.RawFormat, &alert.Impact, &alert.Escalated, &alert.CreatedAt, &alert.UpdatedAt, &alert.ArchivedAt,
 		)
 		if err != nil {
-			return fmt.Errorf("upsert alert %s: %w", alert.ID, err)
+			return nil, fmt.Errorf("scan alert: %w", err)
 		}
+		alerts = append(alerts, alert)
+	}
+	// A query cancelled mid-read ends the loop early; report it rather
+	// than a truncated result
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read alerts: %w", err)
 	}
 
-	return nil
+	return alerts, nil
 }
 
-// QueryAlerts retrieves alerts based on query parameters
-func (s *PostgresStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error) {
+// changesSafetyLag holds back changes this recent from ChangesSince.
+// updated_at is set to NOW(), the start of the writing transaction, so a
+// transaction that commits late can add rows behind a token already handed
+// out; waiting out the lag lets such transactions, and replication to the
+// replica the feed reads from, finish first.
+const changesSafetyLag = time.Minute
+
+// ChangesSince returns alerts updated after token, oldest change first, and
+// the token for the next call. An alert's updated_at moves forward when it
+// is re-ingested, corrected or archived, so all three are reported. Changes
+// appear once changesSafetyLag has passed, and none are missed as long as
+// writes commit, and reach the replica, within that lag.
+func (s *PostgresStore) ChangesSince(ctx context.Context, token string) ([]models.Alert, string, error) {
+	query, args, err := buildChangesQuery(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rowsInterface, err := s.db.QueryReplica(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("query changes: %w", err)
+	}
+
+	alerts, err := scanAlertRows(rowsInterface)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(alerts) == 0 {
+		return alerts, token, nil
+	}
+	return alerts, models.NewChangeToken(alerts[len(alerts)-1]).Encode(), nil
+}
+
+// buildChangesQuery selects the page of alerts updated after token and
+// before changesSafetyLag ago. The effective updated_at is the later of the
+// alert's and its override's, so the candidates come from both tables'
+// updated_at indexes.
+func buildChangesQuery(token string) (string, []interface{}, error) {
 	query := `
 		SELECT id, source, title, summary, url, detected_at, published_at,
 			   region, country, location, latitude, longitude, disruption,
-			   severity, sentiment, confidence, raw, created_at, updated_at
-		FROM alerts
-		WHERE 1=1
+			   severity, sentiment, confidence, language, raw, raw_format, impact, escalated,
+			   created_at, updated_at, archived_at
+		FROM alerts_effective
+	`
+	var args []interface{}
+	if token != "" {
+		since, err := models.DecodeChangeToken(token)
+		if err != nil {
+			return "", nil, err
+		}
+		query += `
+		WHERE id IN (
+			SELECT id FROM alerts WHERE updated_at >= $1
+			UNION
+			SELECT alert_id FROM alert_overrides WHERE updated_at >= $1
+		) AND (updated_at, id) > ($1, $2)
+	`
+		args = append(args, since.UpdatedAt, since.ID)
+		query += fmt.Sprintf(" AND updated_at < NOW() - $%d::interval", len(args)+1)
+	} else {
+		query += " WHERE updated_at < NOW() - $1::interval"
+	}
+	args = append(args, changesSafetyLag)
+	query += fmt.Sprintf(" ORDER BY updated_at, id LIMIT %d", ChangesPageSize)
+
+	return query, args, nil
+}
+
+// CountAlerts returns the number of alerts matching the query filters.
+// Limit and offset are ignored.
+func (s *PostgresStore) CountAlerts(ctx context.Context, q models.AlertQuery) (int, error) {
+	where, args := buildAlertWhere(q)
+	query := "SELECT count(*) FROM alerts_effective " + where
+
+	rowInterface := s.db.QueryRowReplica(ctx, query, args...)
+	row, ok := rowInterface.(pgx.Row)
+	if !ok {
+		return 0, fmt.Errorf("invalid row type")
+	}
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("count alerts: %w", err)
+	}
+
+	return count, nil
+}
+
+// DistinctSources returns the sorted source names of all stored alerts,
+// archived or not
+func (s *PostgresStore) DistinctSources(ctx context.Context) ([]string, error) {
+	rowsInterface, err := s.db.QueryReplica(ctx, "SELECT DISTINCT source FROM alerts ORDER BY source")
+	if err != nil {
+		return nil, fmt.Errorf("query sources: %w", err)
+	}
+
+	rows, ok := rowsInterface.(pgx.Rows)
+	if !ok {
+		return nil, fmt.Errorf("invalid rows type")
+	}
+	defer rows.Close()
+
+	sources := []string{}
+	for rows.Next() {
+		var source string
+		if err := rows.Scan(&source); err != nil {
+			return nil, fmt.Errorf("scan source: %w", err)
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// groupByColumns maps allowed group-by fields to columns. Only these fixed
+// strings reach GROUP BY; user input is never interpolated.
+var groupByColumns = map[string]string{
+	models.GroupBySeverity:   "severity",
+	models.GroupByDisruption: "disruption",
+	models.GroupByRegion:     "region",
+	models.GroupByCountry:    "country",
+}
+
+// AggregateAlerts counts alerts matching the query filters, grouped by the
+// groupBy field. Limit and offset are ignored.
+func (s *PostgresStore) AggregateAlerts(ctx context.Context, q models.AlertQuery, groupBy string) ([]models.GroupCount, error) {
+	column, ok := groupByColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported group by field: %q", groupBy)
+	}
+
+	where, args := buildAlertWhere(q)
+	query := "SELECT COALESCE(" + column + ", ''), count(*) FROM alerts_effective " + where +
+		" GROUP BY 1 ORDER BY 2 DESC, 1 ASC"
+
+	rowsInterface, err := s.db.QueryReplica(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate alerts: %w", err)
+	}
+
+	rows, ok := rowsInterface.(pgx.Rows)
+	if !ok {
+		return nil, fmt.Errorf("invalid rows type")
+	}
+	defer rows.Close()
+
+	counts := []models.GroupCount{}
+	for rows.Next() {
+		var gc models.GroupCount
+		if err := rows.Scan(&gc.Key, &gc.Count); err != nil {
+			return nil, fmt.Errorf("scan alert group: %w", err)
+		}
+		counts = append(counts, gc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("aggregate alerts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// severityRankSQL ranks severity as models.SeverityRank does
+const severityRankSQL = "CASE severity WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END"
+
+// sortExpressions maps allowed sort fields to SQL expressions. Only these
+// fixed strings reach ORDER BY; user input is never interpolated.
+var sortExpressions = map[string]string{
+	models.SortDetectedAt:  "detected_at",
+	models.SortPublishedAt: "published_at",
+	models.SortConfidence:  "confidence",
+	models.SortImpact:      "impact",
+	models.SortSeverity:    severityRankSQL,
+}
+
+// buildAlertOrderBy returns the ORDER BY expression for a sort field and order,
+// falling back to detected_at descending. Ties are broken by detected_at and id.
+func buildAlertOrderBy(field, order string) string {
+	direction := "DESC"
+	if order == models.SortAsc {
+		direction = "ASC"
+	}
+
+	expr, ok := sortExpressions[field]
+	if !ok || field == models.SortDetectedAt {
+		return "detected_at " + direction + ", id " + direction
+	}
+
+	return expr + " " + direction + ", detected_at DESC, id DESC"
+}
+
+// ArchiveOlderThan marks alerts detected before cutoff as archived and
+// returns how many were newly archived
+func (s *PostgresStore) ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	query := `
+		WITH archived AS (
+			UPDATE alerts SET archived_at = NOW()
+			WHERE archived_at IS NULL AND detected_at < $1
+			RETURNING 1
+		)
+		SELECT count(*) FROM archived
 	`
 
+	rowInterface := s.db.QueryRow(ctx, query, cutoff)
+	row, ok := rowInterface.(pgx.Row)
+	if !ok {
+		return 0, fmt.Errorf("invalid row type")
+	}
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("archive alerts: %w", err)
+	}
+
+	return count, nil
+}
+
+// buildAlertWhere builds the WHERE clause and positional arguments for the
+// filters in q. It is shared by QueryAlerts and CountAlerts so they stay in sync.
+func buildAlertWhere(q models.AlertQuery) (string, []interface{}) {
+	where := "WHERE 1=1"
+
 	var args []interface{}
 	argIndex := 1
 
 	// Build WHERE conditions
+	if !q.IncludeArchived {
+		where += " AND archived_at IS NULL"
+	}
+
 	if len(q.IDs) > 0 {
-		query += fmt.Sprintf(" AND id = ANY($%d)", argIndex)
+		where += fmt.Sprintf(" AND id = ANY($%d)", argIndex)
 		args = append(args, q.IDs)
 		argIndex++
 	}
 
 	if len(q.Sources) > 0 {
-		query += fmt.Sprintf(" AND source = ANY($%d)", argIndex)
+		where += fmt.Sprintf(" AND source = ANY($%d)", argIndex)
 		args = append(args, q.Sources)
 		argIndex++
 	}
 
 	if len(q.Severities) > 0 {
-		query += fmt.Sprintf(" AND severity = ANY($%d)", argIndex)
+		where += fmt.Sprintf(" AND severity = ANY($%d)", argIndex)
 		args = append(args, q.Severities)
 		argIndex++
 	}
 
 	if len(q.Disruptions) > 0 {
-		query += fmt.Sprintf(" AND disruption = ANY($%d)", argIndex)
+		where += fmt.Sprintf(" AND disruption = ANY($%d)", argIndex)
 		args = append(args, q.Disruptions)
 		argIndex++
 	}
 
 	if len(q.Regions) > 0 {
-		query += fmt.Sprintf(" AND region = ANY($%d)", argIndex)
+		where += fmt.Sprintf(" AND region = ANY($%d)", argIndex)
 		args = append(args, q.Regions)
 		argIndex++
 	}
 
 	if len(q.Countries) > 0 {
-		query += fmt.Sprintf(" AND country = ANY($%d)", argIndex)
+		where += fmt.Sprintf(" AND country = ANY($%d)", argIndex)
 		args = append(args, q.Countries)
 		argIndex++
 	}
 
+	if len(q.Locations) > 0 {
+		where += fmt.Sprintf(" AND location = ANY($%d)", argIndex)
+		args = append(args, q.Locations)
+		argIndex++
+	}
+
+	if q.Text != "" {
+		where += fmt.Sprintf(" AND to_tsvector('english', title || ' ' || coalesce(summary, '')) @@ plainto_tsquery('english', $%d)", argIndex)
+		args = append(args, q.Text)
+		argIndex++
+	}
+
+	if q.BBox != nil {
+		where += fmt.Sprintf(" AND longitude BETWEEN $%d AND $%d AND latitude BETWEEN $%d AND $%d AND NOT (latitude = 0 AND longitude = 0)",
+			argIndex, argIndex+1, argIndex+2, argIndex+3)
+		args = append(args, q.BBox.MinLon, q.BBox.MaxLon, q.BBox.MinLat, q.BBox.MaxLat)
+		argIndex += 4
+	}
+
+	if q.MinSeverity != "" {
+		where += fmt.Sprintf(" AND %s >= $%d", severityRankSQL, argIndex)
+		args = append(args, models.SeverityRank(q.MinSeverity))
+		argIndex++
+	}
+
+	if q.MinConfidence > 0 {
+		where += fmt.Sprintf(" AND confidence >= $%d", argIndex)
+		args = append(args, q.MinConfidence)
+		argIndex++
+	}
+
+	if q.MinImpact > 0 {
+		where += fmt.Sprintf(" AND impact >= $%d", argIndex)
+		args = append(args, q.MinImpact)
+		argIndex++
+	}
+
+	if q.Escalated != nil {
+		where += fmt.Sprintf(" AND escalated = $%d", argIndex)
+		args = append(args, *q.Escalated)
+		argIndex++
+	}
+
+	if q.HasCoordinates != nil {
+		if *q.HasCoordinates {
+			where += " AND NOT (coalesce(latitude, 0) = 0 AND coalesce(longitude, 0) = 0)"
+		} else {
+			where += " AND coalesce(latitude, 0) = 0 AND coalesce(longitude, 0) = 0"
+		}
+	}
+
 	if !q.Since.IsZero() {
-		query += fmt.Sprintf(" AND detected_at >= $%d", argIndex)
+		where += fmt.Sprintf(" AND detected_at >= $%d", argIndex)
 		args = append(args, q.Since)
 		argIndex++
 	}
 
 	if !q.Until.IsZero() {
-		query += fmt.Sprintf(" AND detected_at <= $%d", argIndex)
+		where += fmt.Sprintf(" AND detected_at <= $%d", argIndex)
 		args = append(args, q.Until)
 		argIndex++
 	}
 
-	// Add ordering
-	query += " ORDER BY detected_at DESC"
+	// Keyset pagination
+	if q.Cursor != nil {
+		where += fmt.Sprintf(" AND (detected_at, id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, q.Cursor.DetectedAt, q.Cursor.ID)
+	}
 
-	// Add limit and offset
-	if q.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, q.Limit)
-		argIndex++
+	return where, args
+}
+
+// alertByIDQuery selects one alert, with any override applied
+const alertByIDQuery = `
+	SELECT id, source, title, summary, url, detected_at, published_at,
+		   region, country, location, latitude, longitude, disruption,
+		   severity, sentiment, confidence, language, raw, raw_format, impact, escalated,
+		   created_at, updated_at, archived_at
+	FROM alerts_effective
+	WHERE id = $1
+`
+
+// GetAlert retrieves a single alert by ID
+func (s *PostgresStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
+	return scanAlertRow(s.db.QueryRowReplica(ctx, alertByIDQuery, id))
+}
+
+// scanAlertRow scans an alertByIDQuery row, returning nil if there was none
+func scanAlertRow(rowInterface interface{}) (*models.Alert, error) {
+	row, ok := rowInterface.(pgx.Row)
+	if !ok {
+		return nil, fmt.Errorf("invalid row type")
 	}
 
-	if q.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argIndex)
-		args = append(args, q.Offset)
+	var alert models.Alert
+	err := row.Scan(
+		&alert.ID, &alert.Source, &alert.Title, &alert.Summary, &alert.URL,
+		&alert.DetectedAt, &alert.PublishedAt, &alert.Region, &alert.Country,
+		&alert.Location, &alert.Latitude, &alert.Longitude, &alert.Disruption,
+		&alert.Severity, &alert.Sentiment, &alert.Confidence, &alert.Language,
+		&alert.Raw, &alert.RawFormat, &alert.Impact, &alert.Escalated, &alert.CreatedAt, &alert.UpdatedAt, &alert.ArchivedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan alert: %w", err)
 	}
 
-	rowsInterface, err := s.db.Query(ctx, query, args...)
+	return &alert, nil
+}
+
+// AlertHistory returns the prior versions of an alert recorded by the
+// alert_history trigger, oldest first
+func (s *PostgresStore) AlertHistory(ctx context.Context, id string) ([]models.Alert, error) {
+	query := `
+		SELECT alert_id, source, title, summary, url, detected_at, published_at,
+			   region, country, location, latitude, longitude, disruption,
+			   severity, sentiment, confidence, language, impact, escalated,
+			   created_at, updated_at
+		FROM alert_history
+		WHERE alert_id = $1
+		ORDER BY updated_at, id
+	`
+
+	rowsInterface, err := s.db.QueryReplica(ctx, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("query alerts: %w", err)
+		return nil, fmt.Errorf("query alert history: %w", err)
 	}
 
 	rows, ok := rowsInterface.(pgx.Rows)
@@ -155,57 +602,65 @@ func (s *PostgresStore) QueryAlerts(ctx context.Context, q models.AlertQuery) ([
 	}
 	defer rows.Close()
 
-	var alerts []models.Alert
+	var versions []models.Alert
 	for rows.Next() {
 		var alert models.Alert
 		err := rows.Scan(
 			&alert.ID, &alert.Source, &alert.Title, &alert.Summary, &alert.URL,
 			&alert.DetectedAt, &alert.PublishedAt, &alert.Region, &alert.Country,
 			&alert.Location, &alert.Latitude, &alert.Longitude, &alert.Disruption,
-			&alert.Severity, &alert.Sentiment, &alert.Confidence, &alert.Raw,
-			&alert.CreatedAt, &alert.UpdatedAt,
+			&alert.Severity, &alert.Sentiment, &alert.Confidence, &alert.Language,
+			&alert.Impact, &alert.Escalated, &alert.CreatedAt, &alert.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("scan alert: %w", err)
+			return nil, fmt.Errorf("scan alert history: %w", err)
 		}
-		alerts = append(alerts, alert)
+		versions = append(versions, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query alert history: %w", err)
 	}
 
-	return alerts, nil
+	return versions, nil
 }
 
-// GetAlert retrieves a single alert by ID
-func (s *PostgresStore) GetAlert(ctx context.Context, id string) (*models.Alert, error) {
+// OverrideAlert stores a correction to an alert's classification and
+// returns the corrected alert, read from the primary so it reflects the
+// write. Fields left empty keep any earlier correction.
+func (s *PostgresStore) OverrideAlert(ctx context.Context, o models.AlertOverride) (*models.Alert, error) {
 	query := `
-		SELECT id, source, title, summary, url, detected_at, published_at,
-			   region, country, location, latitude, longitude, disruption,
-			   severity, sentiment, confidence, raw, created_at, updated_at
-		FROM alerts
-		WHERE id = $1
+		INSERT INTO alert_overrides (alert_id, severity, disruption, submitted_by, updated_at)
+		SELECT id, NULLIF($2, ''), NULLIF($3, ''), $4, NOW() FROM alerts WHERE id = $1
+		ON CONFLICT (alert_id) DO UPDATE SET
+			severity = COALESCE(EXCLUDED.severity, alert_overrides.severity),
+			disruption = COALESCE(EXCLUDED.disruption, alert_overrides.disruption),
+			submitted_by = EXCLUDED.submitted_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING alert_id
 	`
 
-	rowInterface := s.db.QueryRow(ctx, query, id)
+	rowInterface := s.db.QueryRow(ctx, query, o.AlertID, o.Severity, o.Disruption, o.SubmittedBy)
 	row, ok := rowInterface.(pgx.Row)
 	if !ok {
 		return nil, fmt.Errorf("invalid row type")
 	}
 
-	var alert models.Alert
-	err := row.Scan(
-		&alert.ID, &alert.Source, &alert.Title, &alert.Summary, &alert.URL,
-		&alert.DetectedAt, &alert.PublishedAt, &alert.Region, &alert.Country,
-		&alert.Location, &alert.Latitude, &alert.Longitude, &alert.Disruption,
-		&alert.Severity, &alert.Sentiment, &alert.Confidence, &alert.Raw,
-		&alert.CreatedAt, &alert.UpdatedAt,
-	)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil
+	var id string
+	if err := row.Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertNotFound
 		}
-		return nil, fmt.Errorf("scan alert: %w", err)
+		return nil, fmt.Errorf("override alert: %w", err)
 	}
 
-	return &alert, nil
+	alert, err := scanAlertRow(s.db.QueryRow(ctx, alertByIDQuery, id))
+	if err != nil {
+		return nil, err
+	}
+	if alert == nil {
+		return nil, ErrAlertNotFound
+	}
+	return alert, nil
 }
 
 // Health checks the database connection
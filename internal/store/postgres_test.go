@@ -3,19 +3,24 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 )
 
 type mockDB struct {
-	ExecFn         func(ctx context.Context, sql string, args ...any) error
-	QueryFn        func(ctx context.Context, sql string, args ...any) (interface{}, error)
-	QueryRowFn     func(ctx context.Context, sql string, args ...any) interface{}
-	HealthFn       func(ctx context.Context) error
-	IsConfiguredFn func() bool
+	ExecFn            func(ctx context.Context, sql string, args ...any) error
+	QueryFn           func(ctx context.Context, sql string, args ...any) (interface{}, error)
+	QueryRowFn        func(ctx context.Context, sql string, args ...any) interface{}
+	QueryReplicaFn    func(ctx context.Context, sql string, args ...any) (interface{}, error)
+	QueryRowReplicaFn func(ctx context.Context, sql string, args ...any) interface{}
+	HealthFn          func(ctx context.Context) error
+	IsConfiguredFn    func() bool
 }
 
 func (m *mockDB) Exec(ctx context.Context, sql string, args ...any) error {
@@ -36,6 +41,20 @@ func (m *mockDB) QueryRow(ctx context.Context, sql string, args ...any) interfac
 	}
 	return nil
 }
+
+// QueryReplica falls back to QueryFn, as DB falls back to the primary
+func (m *mockDB) QueryReplica(ctx context.Context, sql string, args ...any) (interface{}, error) {
+	if m.QueryReplicaFn != nil {
+		return m.QueryReplicaFn(ctx, sql, args...)
+	}
+	return m.Query(ctx, sql, args...)
+}
+func (m *mockDB) QueryRowReplica(ctx context.Context, sql string, args ...any) interface{} {
+	if m.QueryRowReplicaFn != nil {
+		return m.QueryRowReplicaFn(ctx, sql, args...)
+	}
+	return m.QueryRow(ctx, sql, args...)
+}
 func (m *mockDB) Health(ctx context.Context) error {
 	if m.HealthFn != nil {
 		return m.HealthFn(ctx)
@@ -79,6 +98,86 @@ func TestPostgresStore_UpsertAlerts_BuildsQueryAndPropagatesError(t *testing.T)
 	}
 }
 
+func TestPostgresStore_UpsertAlerts_SingleStatementPerChunk(t *testing.T) {
+	var calls int
+	var lastArgs int
+	db := &mockDB{ExecFn: func(ctx context.Context, sql string, args ...any) error {
+		calls++
+		lastArgs = len(args)
+		return nil
+	}}
+	s := NewPostgresStore(db)
+
+	alerts := make([]models.Alert, maxUpsertRows+10)
+	for i := range alerts {
+		alerts[i] = models.Alert{ID: fmt.Sprintf("id-%d", i)}
+	}
+
+	if err := s.UpsertAlerts(context.Background(), alerts); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 Exec calls, got %d", calls)
+	}
+	if lastArgs != 10*upsertColumns {
+		t.Errorf("expected %d args in last chunk, got %d", 10*upsertColumns, lastArgs)
+	}
+}
+
+func TestPostgresStore_UpsertAlerts_DedupesIDs(t *testing.T) {
+	var gotArgs []any
+	db := &mockDB{ExecFn: func(ctx context.Context, sql string, args ...any) error {
+		gotArgs = args
+		return nil
+	}}
+	s := NewPostgresStore(db)
+
+	alerts := []models.Alert{{ID: "a", Title: "old"}, {ID: "b"}, {ID: "a", Title: "new"}}
+	if err := s.UpsertAlerts(context.Background(), alerts); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(gotArgs) != 2*upsertColumns {
+		t.Fatalf("expected 2 rows, got %d args", len(gotArgs))
+	}
+	if gotArgs[0] != "a" || gotArgs[2] != "new" {
+		t.Errorf("expected last occurrence of duplicate ID to win, got %v", gotArgs[:3])
+	}
+}
+
+func BenchmarkPostgresStore_UpsertAlerts(b *testing.B) {
+	alerts := make([]models.Alert, 100)
+	for i := range alerts {
+		alerts[i] = models.Alert{ID: fmt.Sprintf("id-%d", i), Title: "t", DetectedAt: time.Now()}
+	}
+
+	// Single-row issues one Exec per alert, as UpsertAlerts did before batching
+	b.Run("SingleRow", func(b *testing.B) {
+		var calls int
+		s := NewPostgresStore(&mockDB{ExecFn: func(ctx context.Context, sql string, args ...any) error {
+			calls++
+			return nil
+		}})
+		for i := 0; i < b.N; i++ {
+			for _, alert := range alerts {
+				_ = s.UpsertAlerts(context.Background(), []models.Alert{alert})
+			}
+		}
+		b.ReportMetric(float64(calls)/float64(b.N), "execs/op")
+	})
+
+	b.Run("Batched", func(b *testing.B) {
+		var calls int
+		s := NewPostgresStore(&mockDB{ExecFn: func(ctx context.Context, sql string, args ...any) error {
+			calls++
+			return nil
+		}})
+		for i := 0; i < b.N; i++ {
+			_ = s.UpsertAlerts(context.Background(), alerts)
+		}
+		b.ReportMetric(float64(calls)/float64(b.N), "execs/op")
+	})
+}
+
 func TestPostgresStore_QueryAlerts_ErrorFromDB(t *testing.T) {
 	db := &mockDB{QueryFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
 		return nil, errors.New("db error")
@@ -132,3 +231,399 @@ func TestPostgresStore_GetAlert_NoRows(t *testing.T) {
 		t.Fatalf("expected nil, got %+v", res)
 	}
 }
+
+func TestPostgresStore_QueryAlerts_CursorPredicate(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &mockDB{QueryFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+		gotSQL = sql
+		gotArgs = args
+		return nil, errors.New("stop")
+	}}
+	s := NewPostgresStore(db)
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	_, _ = s.QueryAlerts(context.Background(), models.AlertQuery{
+		Sources: []string{"src"},
+		Cursor:  &models.Cursor{DetectedAt: at, ID: "abc"},
+		Limit:   10,
+	})
+	if !strings.Contains(gotSQL, "AND (detected_at, id) < ($2, $3)") {
+		t.Errorf("missing keyset predicate: %s", gotSQL)
+	}
+	if !strings.Contains(gotSQL, "ORDER BY detected_at DESC, id DESC") {
+		t.Errorf("missing tie-break ordering: %s", gotSQL)
+	}
+	if len(gotArgs) != 4 || gotArgs[1] != at || gotArgs[2] != "abc" || gotArgs[3] != 10 {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestBuildAlertOrderBy(t *testing.T) {
+	tests := []struct {
+		field, order, expected string
+	}{
+		{"", "", "detected_at DESC, id DESC"},
+		{"detected_at", "asc", "detected_at ASC, id ASC"},
+		{"published_at", "desc", "published_at DESC, detected_at DESC, id DESC"},
+		{"confidence", "asc", "confidence ASC, detected_at DESC, id DESC"},
+		{"severity", "desc", "CASE severity WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END DESC, detected_at DESC, id DESC"},
+		{"id; DROP TABLE alerts", "asc; --", "detected_at DESC, id DESC"},
+	}
+	for _, tt := range tests {
+		if got := buildAlertOrderBy(tt.field, tt.order); got != tt.expected {
+			t.Errorf("buildAlertOrderBy(%q, %q) = %q, want %q", tt.field, tt.order, got, tt.expected)
+		}
+	}
+}
+
+func TestPostgresStore_QueryAlerts_TextSearch(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &mockDB{QueryFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+		gotSQL = sql
+		gotArgs = args
+		return nil, errors.New("stop")
+	}}
+	s := NewPostgresStore(db)
+	_, _ = s.QueryAlerts(context.Background(), models.AlertQuery{Text: "typhoon"})
+	if !strings.Contains(gotSQL, "@@ plainto_tsquery('english', $1)") {
+		t.Errorf("missing full-text predicate: %s", gotSQL)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "typhoon" {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+
+	_, _ = s.QueryAlerts(context.Background(), models.AlertQuery{})
+	if strings.Contains(gotSQL, "plainto_tsquery") {
+		t.Errorf("empty text should not add a predicate: %s", gotSQL)
+	}
+}
+
+type countRow struct{ n int }
+
+func (r countRow) Scan(dest ...any) error {
+	*dest[0].(*int) = r.n
+	return nil
+}
+
+func TestPostgresStore_CountAlerts_SharesWhereClause(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &mockDB{QueryRowFn: func(ctx context.Context, sql string, args ...any) interface{} {
+		gotSQL = sql
+		gotArgs = args
+		return countRow{n: 7}
+	}}
+	s := NewPostgresStore(db)
+	q := models.AlertQuery{Severities: []string{"high"}, Disruptions: []string{"port_status"}, Limit: 5, Offset: 2}
+	count, err := s.CountAlerts(context.Background(), q)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if count != 7 {
+		t.Errorf("expected 7, got %d", count)
+	}
+	where, args := buildAlertWhere(q)
+	if !strings.HasPrefix(gotSQL, "SELECT count(*) FROM alerts") || !strings.HasSuffix(gotSQL, where) {
+		t.Errorf("unexpected SQL: %s", gotSQL)
+	}
+	if strings.Contains(gotSQL, "LIMIT") || strings.Contains(gotSQL, "OFFSET") {
+		t.Errorf("count should ignore limit and offset: %s", gotSQL)
+	}
+	if len(gotArgs) != len(args) {
+		t.Errorf("expected %d args, got %d", len(args), len(gotArgs))
+	}
+}
+
+func TestPostgresStore_CountAlerts_InvalidRowType(t *testing.T) {
+	db := &mockDB{QueryRowFn: func(ctx context.Context, sql string, args ...any) interface{} { return 123 }}
+	s := NewPostgresStore(db)
+	if _, err := s.CountAlerts(context.Background(), models.AlertQuery{}); err == nil || !strings.Contains(err.Error(), "invalid row type") {
+		t.Errorf("expected invalid row type error, got %v", err)
+	}
+}
+
+func TestPostgresStore_AggregateAlerts_Query(t *testing.T) {
+	var gotSQL string
+	db := &mockDB{QueryFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+		gotSQL = sql
+		return nil, errors.New("boom")
+	}}
+	s := NewPostgresStore(db)
+	q := models.AlertQuery{Severities: []string{"high"}, Limit: 5}
+
+	if _, err := s.AggregateAlerts(context.Background(), q, models.GroupByRegion); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected query error to propagate, got %v", err)
+	}
+	where, _ := buildAlertWhere(q)
+	if !strings.HasPrefix(gotSQL, "SELECT COALESCE(region, ''), count(*) FROM alerts_effective "+where) || !strings.Contains(gotSQL, "GROUP BY 1") {
+		t.Errorf("unexpected SQL: %s", gotSQL)
+	}
+	if strings.Contains(gotSQL, "LIMIT") {
+		t.Errorf("aggregate should ignore limit: %s", gotSQL)
+	}
+}
+
+func TestPostgresStore_AggregateAlerts_RejectsUnknownField(t *testing.T) {
+	db := &mockDB{QueryFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+		t.Fatalf("unexpected query: %s", sql)
+		return nil, nil
+	}}
+	s := NewPostgresStore(db)
+	if _, err := s.AggregateAlerts(context.Background(), models.AlertQuery{}, "severity; DROP TABLE alerts"); err == nil {
+		t.Error("expected error for unsupported group by field")
+	}
+}
+
+func TestPostgresStore_DistinctSources(t *testing.T) {
+	var gotSQL string
+	db := &mockDB{QueryReplicaFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+		gotSQL = sql
+		return nil, errors.New("boom")
+	}}
+	s := NewPostgresStore(db)
+
+	if _, err := s.DistinctSources(context.Background()); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected query error to propagate, got %v", err)
+	}
+	if gotSQL != "SELECT DISTINCT source FROM alerts ORDER BY source" {
+		t.Errorf("unexpected SQL: %s", gotSQL)
+	}
+
+	db = &mockDB{QueryReplicaFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) { return 123, nil }}
+	if _, err := NewPostgresStore(db).DistinctSources(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid rows type") {
+		t.Errorf("expected invalid rows type error, got %v", err)
+	}
+}
+
+func TestPostgresStore_ReadsUseReplica(t *testing.T) {
+	var primary, replica []string
+	db := &mockDB{
+		QueryFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+			primary = append(primary, sql)
+			return nil, errors.New("primary")
+		},
+		QueryRowFn: func(ctx context.Context, sql string, args ...any) interface{} {
+			primary = append(primary, sql)
+			return fakeRow{err: errors.New("row")}
+		},
+		QueryReplicaFn: func(ctx context.Context, sql string, args ...any) (interface{}, error) {
+			replica = append(replica, sql)
+			return nil, errors.New("replica")
+		},
+		QueryRowReplicaFn: func(ctx context.Context, sql string, args ...any) interface{} {
+			replica = append(replica, sql)
+			return fakeRow{err: errors.New("row")}
+		},
+	}
+	s := NewPostgresStore(db)
+	ctx := context.Background()
+
+	_, _ = s.QueryAlerts(ctx, models.AlertQuery{})
+	_, _ = s.CountAlerts(ctx, models.AlertQuery{})
+	_, _ = s.AggregateAlerts(ctx, models.AlertQuery{}, models.GroupBySeverity)
+	_, _ = s.GetAlert(ctx, "alert-1")
+	if len(replica) != 4 {
+		t.Errorf("expected 4 reads on the replica, got %d", len(replica))
+	}
+
+	_, _ = s.ArchiveOlderThan(ctx, time.Now())
+	if len(primary) != 1 || !strings.Contains(primary[0], "UPDATE alerts") {
+		t.Errorf("expected only the archive on the primary, got %v", primary)
+	}
+}
+
+func TestPostgresStore_OverrideAlert(t *testing.T) {
+	var primary []string
+	var args []any
+	db := &mockDB{QueryRowFn: func(ctx context.Context, sql string, a ...any) interface{} {
+		primary = append(primary, sql)
+		if args == nil {
+			args = a
+		}
+		return fakeRow{err: pgx.ErrNoRows}
+	}}
+	s := NewPostgresStore(db)
+
+	o := models.AlertOverride{AlertID: "alert-1", Severity: "high", SubmittedBy: "ops"}
+	if _, err := s.OverrideAlert(context.Background(), o); !errors.Is(err, ErrAlertNotFound) {
+		t.Fatalf("expected ErrAlertNotFound for a missing alert, got %v", err)
+	}
+	if len(primary) != 1 || !strings.Contains(primary[0], "INSERT INTO alert_overrides") || !strings.Contains(primary[0], "ON CONFLICT (alert_id)") {
+		t.Errorf("unexpected SQL: %v", primary)
+	}
+	if !reflect.DeepEqual(args, []any{"alert-1", "high", "", "ops"}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	db = &mockDB{QueryRowFn: func(ctx context.Context, sql string, a ...any) interface{} {
+		return fakeRow{err: errors.New("boom")}
+	}}
+	if _, err := NewPostgresStore(db).OverrideAlert(context.Background(), o); err == nil || !strings.Contains(err.Error(), "override alert: boom") {
+		t.Errorf("expected wrapped error, got %v", err)
+	}
+}
+
+func TestBuildAlertWhere_MinSeverity(t *testing.T) {
+	where, args := buildAlertWhere(models.AlertQuery{Severities: []string{"medium", "high"}, MinSeverity: "medium"})
+	if !strings.Contains(where, "severity = ANY($1)") || !strings.Contains(where, "AND "+severityRankSQL+" >= $2") {
+		t.Errorf("missing severity predicates: %s", where)
+	}
+	if len(args) != 2 || args[1] != 2 {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestBuildAlertWhere_MinConfidence(t *testing.T) {
+	where, args := buildAlertWhere(models.AlertQuery{Sources: []string{"a"}, MinConfidence: 0.6})
+	if !strings.Contains(where, "AND confidence >= $2") {
+		t.Errorf("missing confidence predicate: %s", where)
+	}
+	if len(args) != 2 || args[1] != 0.6 {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if where, _ := buildAlertWhere(models.AlertQuery{}); strings.Contains(where, "confidence") {
+		t.Errorf("zero min confidence should not filter: %s", where)
+	}
+}
+
+func TestBuildAlertWhere_MinImpact(t *testing.T) {
+	where, args := buildAlertWhere(models.AlertQuery{MinConfidence: 0.6, MinImpact: 0.4})
+	if !strings.Contains(where, "AND confidence >= $1 AND impact >= $2") {
+		t.Errorf("missing impact predicate: %s", where)
+	}
+	if len(args) != 2 || args[1] != 0.4 {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if where, _ := buildAlertWhere(models.AlertQuery{}); strings.Contains(where, "impact") {
+		t.Errorf("zero min impact should not filter: %s", where)
+	}
+}
+
+func TestBuildAlertWhere_Escalation(t *testing.T) {
+	escalated := true
+	where, args := buildAlertWhere(models.AlertQuery{Locations: []string{"Port of Oakland"}, Escalated: &escalated})
+	if !strings.Contains(where, "AND location = ANY($1)") || !strings.Contains(where, "AND escalated = $2") {
+		t.Errorf("missing location or escalation predicate: %s", where)
+	}
+	if len(args) != 2 || args[1] != true {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if where, _ := buildAlertWhere(models.AlertQuery{}); strings.Contains(where, "escalated") {
+		t.Errorf("nil escalated should not filter: %s", where)
+	}
+}
+
+func TestBuildAlertWhere_HasCoordinates(t *testing.T) {
+	tests := []struct {
+		hasCoords bool
+		want      string
+	}{
+		{true, "AND NOT (coalesce(latitude, 0) = 0 AND coalesce(longitude, 0) = 0)"},
+		{false, "AND coalesce(latitude, 0) = 0 AND coalesce(longitude, 0) = 0"},
+	}
+	for _, tt := range tests {
+		hasCoords := tt.hasCoords
+		where, args := buildAlertWhere(models.AlertQuery{HasCoordinates: &hasCoords})
+		if !strings.Contains(where, tt.want) {
+			t.Errorf("has_coordinates=%v: missing predicate %q: %s", tt.hasCoords, tt.want, where)
+		}
+		if len(args) != 0 {
+			t.Errorf("has_coordinates=%v: unexpected args: %v", tt.hasCoords, args)
+		}
+	}
+
+	if where, _ := buildAlertWhere(models.AlertQuery{}); strings.Contains(where, "latitude") {
+		t.Errorf("nil has_coordinates should not filter: %s", where)
+	}
+}
+
+func TestBuildChangesQuery(t *testing.T) {
+	query, args, err := buildChangesQuery("")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !strings.Contains(query, "WHERE updated_at < NOW() - $1::interval") || strings.Contains(query, "(updated_at, id) >") {
+		t.Errorf("empty token should only hold back recent changes: %s", query)
+	}
+	if len(args) != 1 || args[0] != changesSafetyLag {
+		t.Errorf("unexpected args: %v", args)
+	}
+	if !strings.Contains(query, fmt.Sprintf("ORDER BY updated_at, id LIMIT %d", ChangesPageSize)) {
+		t.Errorf("missing keyset order and limit: %s", query)
+	}
+
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	query, args, err = buildChangesQuery(models.ChangeToken{UpdatedAt: at, ID: "alert-1"}.Encode())
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !strings.Contains(query, "AND (updated_at, id) > ($1, $2)") || !strings.Contains(query, "FROM alert_overrides WHERE updated_at >= $1") {
+		t.Errorf("missing keyset predicate: %s", query)
+	}
+	if !strings.Contains(query, "AND updated_at < NOW() - $3::interval") {
+		t.Errorf("missing safety lag predicate: %s", query)
+	}
+	if len(args) != 3 || !args[0].(time.Time).Equal(at) || args[1] != "alert-1" || args[2] != changesSafetyLag {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if _, _, err := buildChangesQuery("!!!"); !errors.Is(err, models.ErrInvalidChangeToken) {
+		t.Errorf("expected ErrInvalidChangeToken, got %v", err)
+	}
+}
+
+func TestBuildAlertWhere_BBox(t *testing.T) {
+	where, args := buildAlertWhere(models.AlertQuery{
+		Severities: []string{"high"},
+		BBox:       &models.BBox{MinLon: -120, MinLat: 30, MaxLon: -115, MaxLat: 35},
+	})
+	if !strings.Contains(where, "longitude BETWEEN $2 AND $3 AND latitude BETWEEN $4 AND $5") {
+		t.Errorf("missing bbox predicate: %s", where)
+	}
+	if !strings.Contains(where, "NOT (latitude = 0 AND longitude = 0)") {
+		t.Errorf("bbox should exclude 0/0 coordinates: %s", where)
+	}
+	if len(args) != 5 || args[1] != -120.0 || args[2] != -115.0 || args[3] != 30.0 || args[4] != 35.0 {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestPostgresStore_ArchiveOlderThan(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &mockDB{QueryRowFn: func(ctx context.Context, sql string, args ...any) interface{} {
+		gotSQL = sql
+		gotArgs = args
+		return countRow{n: 3}
+	}}
+	s := NewPostgresStore(db)
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	count, err := s.ArchiveOlderThan(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3, got %d", count)
+	}
+	if !strings.Contains(gotSQL, "SET archived_at = NOW()") || !strings.Contains(gotSQL, "archived_at IS NULL AND detected_at < $1") {
+		t.Errorf("unexpected SQL: %s", gotSQL)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != cutoff {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestBuildAlertWhere_Archived(t *testing.T) {
+	where, _ := buildAlertWhere(models.AlertQuery{})
+	if !strings.Contains(where, "archived_at IS NULL") {
+		t.Errorf("expected archived rows to be excluded by default: %s", where)
+	}
+	where, _ = buildAlertWhere(models.AlertQuery{IncludeArchived: true})
+	if strings.Contains(where, "archived_at") {
+		t.Errorf("expected no archived predicate with IncludeArchived: %s", where)
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SourceWatermark returns the newest published time recorded for source,
+// or the zero time if none has been
+func (s *InMemoryStore) SourceWatermark(ctx context.Context, source string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watermarks[source], nil
+}
+
+// SetSourceWatermark records at as source's watermark unless a newer one
+// is already stored
+func (s *InMemoryStore) SetSourceWatermark(ctx context.Context, source string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if at.After(s.watermarks[source]) {
+		s.watermarks[source] = at
+	}
+	return nil
+}
+
+// SourceWatermark returns the newest published time recorded for source,
+// or the zero time if none has been. It reads the primary, since the
+// pipeline sets the watermark moments before the next poll reads it.
+func (s *PostgresStore) SourceWatermark(ctx context.Context, source string) (time.Time, error) {
+	rowInterface := s.db.QueryRow(ctx, "SELECT watermark FROM source_state WHERE source = $1", source)
+	row, ok := rowInterface.(pgx.Row)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid row type")
+	}
+
+	var watermark time.Time
+	if err := row.Scan(&watermark); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("get source watermark: %w", err)
+	}
+	return watermark, nil
+}
+
+// SetSourceWatermark records at as source's watermark unless a newer one
+// is already stored
+func (s *PostgresStore) SetSourceWatermark(ctx context.Context, source string, at time.Time) error {
+	query := `
+		INSERT INTO source_state (source, watermark, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (source) DO UPDATE SET
+			watermark = GREATEST(source_state.watermark, EXCLUDED.watermark),
+			updated_at = EXCLUDED.updated_at
+	`
+	if err := s.db.Exec(ctx, query, source, at); err != nil {
+		return fmt.Errorf("set source watermark: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestInMemoryStore_SourceWatermark(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, err := s.SourceWatermark(ctx, "feed"); err != nil || !got.IsZero() {
+		t.Fatalf("Expected zero watermark for an unseen source, got %v, %v", got, err)
+	}
+
+	s.SetSourceWatermark(ctx, "feed", t1)
+	s.SetSourceWatermark(ctx, "feed", t1.Add(-time.Hour)) // older marks never win
+	if got, _ := s.SourceWatermark(ctx, "feed"); !got.Equal(t1) {
+		t.Errorf("Expected watermark %v, got %v", t1, got)
+	}
+	if got, _ := s.SourceWatermark(ctx, "other"); !got.IsZero() {
+		t.Errorf("Expected watermarks to be per source, got %v", got)
+	}
+}
+
+func TestPostgresStore_SourceWatermark(t *testing.T) {
+	db := &mockDB{QueryRowFn: func(ctx context.Context, sql string, args ...any) interface{} { return fakeRow{err: pgx.ErrNoRows} }}
+	got, err := NewPostgresStore(db).SourceWatermark(context.Background(), "feed")
+	if err != nil || !got.IsZero() {
+		t.Errorf("Expected zero watermark without a row, got %v, %v", got, err)
+	}
+
+	db = &mockDB{QueryRowFn: func(ctx context.Context, sql string, args ...any) interface{} {
+		return fakeRow{err: errors.New("boom")}
+	}}
+	if _, err := NewPostgresStore(db).SourceWatermark(context.Background(), "feed"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected wrapped error, got %v", err)
+	}
+}
+
+func TestPostgresStore_SetSourceWatermark(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &mockDB{ExecFn: func(ctx context.Context, sql string, args ...any) error {
+		gotSQL, gotArgs = sql, args
+		return nil
+	}}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := NewPostgresStore(db).SetSourceWatermark(context.Background(), "feed", at); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(gotSQL, "INSERT INTO source_state") || !strings.Contains(gotSQL, "GREATEST(source_state.watermark, EXCLUDED.watermark)") {
+		t.Errorf("Unexpected SQL: %s", gotSQL)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != "feed" || gotArgs[1] != at {
+		t.Errorf("Unexpected args: %v", gotArgs)
+	}
+}
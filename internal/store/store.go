@@ -2,32 +2,55 @@ package store
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 )
 
-// Store defines the interface for alert storage
+// ErrAlertNotFound is returned when writing to an alert that does not exist
+var ErrAlertNotFound = errors.New("alert not found")
+
+// ChangesPageSize is the most alerts ChangesSince returns per call
+const ChangesPageSize = 500
+
+// Store defines the interface for alert storage. ChangesSince returns up to
+// ChangesPageSize alerts, archived or not, updated after the position an
+// opaque token from an earlier call marks, oldest change first, along with
+// the token to pass next. An empty token starts from the beginning.
 type Store interface {
 	UpsertAlerts(ctx context.Context, alerts []models.Alert) error
 	QueryAlerts(ctx context.Context, q models.AlertQuery) ([]models.Alert, error)
+	CountAlerts(ctx context.Context, q models.AlertQuery) (int, error)
+	AggregateAlerts(ctx context.Context, q models.AlertQuery, groupBy string) ([]models.GroupCount, error)
 	GetAlert(ctx context.Context, id string) (*models.Alert, error)
+	AlertHistory(ctx context.Context, id string) ([]models.Alert, error)
+	ChangesSince(ctx context.Context, token string) ([]models.Alert, string, error)
+	DistinctSources(ctx context.Context) ([]string, error)
+	OverrideAlert(ctx context.Context, o models.AlertOverride) (*models.Alert, error)
+	ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int, error)
 	Health(ctx context.Context) error
 }
 
-// Database interface for dependency injection
+// Database interface for dependency injection. The Replica variants serve
+// reads that tolerate replication lag and fall back to the primary when no
+// replica is configured.
 type Database interface {
 	Exec(ctx context.Context, sql string, args ...any) error
 	Query(ctx context.Context, sql string, args ...any) (interface{}, error)
 	QueryRow(ctx context.Context, sql string, args ...any) interface{}
+	QueryReplica(ctx context.Context, sql string, args ...any) (interface{}, error)
+	QueryRowReplica(ctx context.Context, sql string, args ...any) interface{}
 	Health(ctx context.Context) error
 	IsConfigured() bool
 }
 
-// New creates a new store instance
-func New(db Database) Store {
+// New creates a new store instance. Without a database it falls back to an
+// in-memory store configured by opts.
+func New(db Database, opts ...MemoryOption) Store {
 	if db.IsConfigured() {
 		return NewPostgresStore(db)
 	}
 	// Fallback to in-memory store if no database
-	return NewInMemoryStore()
+	return NewInMemoryStore(opts...)
 }
@@ -12,8 +12,12 @@ func (d *cfgDB) Query(ctx context.Context, sql string, args ...any) (interface{}
 	return nil, nil
 }
 func (d *cfgDB) QueryRow(ctx context.Context, sql string, args ...any) interface{} { return nil }
-func (d *cfgDB) Health(ctx context.Context) error                                  { return nil }
-func (d *cfgDB) IsConfigured() bool                                                { return d.configured }
+func (d *cfgDB) QueryReplica(ctx context.Context, sql string, args ...any) (interface{}, error) {
+	return nil, nil
+}
+func (d *cfgDB) QueryRowReplica(ctx context.Context, sql string, args ...any) interface{} { return nil }
+func (d *cfgDB) Health(ctx context.Context) error                                         { return nil }
+func (d *cfgDB) IsConfigured() bool                                                       { return d.configured }
 
 func TestNew_ReturnsPostgresWhenConfigured(t *testing.T) {
 	db := &cfgDB{configured: true}
@@ -0,0 +1,241 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// ErrWebhookNotFound is returned when deleting a webhook that does not exist
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookStore persists outbound webhook subscriptions
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, hook *models.Webhook) error
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	RecordWebhookDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error
+}
+
+// NewWebhookStore creates a webhook store backed by db, or in memory if no
+// database is configured
+func NewWebhookStore(db Database) WebhookStore {
+	if db.IsConfigured() {
+		return NewPostgresWebhookStore(db)
+	}
+	return NewInMemoryWebhookStore()
+}
+
+// newWebhookID returns a random webhook ID
+func newWebhookID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook id: %w", err)
+	}
+	return "wh_" + hex.EncodeToString(b), nil
+}
+
+// InMemoryWebhookStore implements WebhookStore using in-memory storage
+type InMemoryWebhookStore struct {
+	mu       sync.RWMutex
+	webhooks map[string]models.Webhook
+}
+
+// NewInMemoryWebhookStore creates a new in-memory webhook store
+func NewInMemoryWebhookStore() *InMemoryWebhookStore {
+	return &InMemoryWebhookStore{
+		webhooks: make(map[string]models.Webhook),
+	}
+}
+
+// CreateWebhook stores hook, assigning its ID and creation time
+func (s *InMemoryWebhookStore) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	id, err := newWebhookID()
+	if err != nil {
+		return err
+	}
+	hook.ID = id
+	hook.CreatedAt = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhooks[hook.ID] = *hook
+	return nil
+}
+
+// ListWebhooks returns all webhooks, oldest first
+func (s *InMemoryWebhookStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hooks := make([]models.Webhook, 0, len(s.webhooks))
+	for _, hook := range s.webhooks {
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		if hooks[i].CreatedAt.Equal(hooks[j].CreatedAt) {
+			return hooks[i].ID < hooks[j].ID
+		}
+		return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+	})
+	return hooks, nil
+}
+
+// DeleteWebhook removes a webhook
+func (s *InMemoryWebhookStore) DeleteWebhook(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return ErrWebhookNotFound
+	}
+	delete(s.webhooks, id)
+	return nil
+}
+
+// RecordWebhookDelivery records the outcome of a delivery attempt; a non-nil
+// deliveryErr counts as a failure
+func (s *InMemoryWebhookStore) RecordWebhookDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hook, ok := s.webhooks[id]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+
+	hook.LastDeliveryAt = &at
+	if deliveryErr != nil {
+		hook.FailureCount++
+		hook.LastError = deliveryErr.Error()
+	} else {
+		hook.LastError = ""
+	}
+	s.webhooks[id] = hook
+	return nil
+}
+
+// PostgresWebhookStore implements WebhookStore using PostgreSQL
+type PostgresWebhookStore struct {
+	db Database
+}
+
+// NewPostgresWebhookStore creates a new PostgreSQL webhook store
+func NewPostgresWebhookStore(db Database) *PostgresWebhookStore {
+	return &PostgresWebhookStore{db: db}
+}
+
+// CreateWebhook inserts hook, assigning its ID and creation time
+func (s *PostgresWebhookStore) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	id, err := newWebhookID()
+	if err != nil {
+		return err
+	}
+	filter, err := json.Marshal(hook.Filter)
+	if err != nil {
+		return fmt.Errorf("encode webhook filter: %w", err)
+	}
+
+	hook.ID = id
+	hook.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO outbound_webhooks (id, url, secret, filter, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if err := s.db.Exec(ctx, query, hook.ID, hook.URL, hook.Secret, filter, hook.CreatedAt); err != nil {
+		return fmt.Errorf("create webhook: %w", err)
+	}
+	return nil
+}
+
+// ListWebhooks returns all webhooks, oldest first
+func (s *PostgresWebhookStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	query := `
+		SELECT id, url, secret, filter, failure_count, coalesce(last_error, ''),
+			   last_delivery_at, created_at
+		FROM outbound_webhooks
+		ORDER BY created_at, id
+	`
+
+	rowsInterface, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query webhooks: %w", err)
+	}
+
+	rows, ok := rowsInterface.(pgx.Rows)
+	if !ok {
+		return nil, fmt.Errorf("invalid rows type")
+	}
+	defer rows.Close()
+
+	var hooks []models.Webhook
+	for rows.Next() {
+		var hook models.Webhook
+		var filter []byte
+		err := rows.Scan(
+			&hook.ID, &hook.URL, &hook.Secret, &filter, &hook.FailureCount,
+			&hook.LastError, &hook.LastDeliveryAt, &hook.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook: %w", err)
+		}
+		if err := json.Unmarshal(filter, &hook.Filter); err != nil {
+			return nil, fmt.Errorf("decode filter of webhook %s: %w", hook.ID, err)
+		}
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, rows.Err()
+}
+
+// DeleteWebhook removes a webhook
+func (s *PostgresWebhookStore) DeleteWebhook(ctx context.Context, id string) error {
+	rowInterface := s.db.QueryRow(ctx, "DELETE FROM outbound_webhooks WHERE id = $1 RETURNING id", id)
+	row, ok := rowInterface.(pgx.Row)
+	if !ok {
+		return fmt.Errorf("invalid row type")
+	}
+
+	var deleted string
+	if err := row.Scan(&deleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	return nil
+}
+
+// RecordWebhookDelivery records the outcome of a delivery attempt; a non-nil
+// deliveryErr counts as a failure
+func (s *PostgresWebhookStore) RecordWebhookDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error {
+	query := `
+		UPDATE outbound_webhooks
+		SET last_delivery_at = $2, last_error = NULL
+		WHERE id = $1
+	`
+	args := []interface{}{id, at}
+	if deliveryErr != nil {
+		query = `
+			UPDATE outbound_webhooks
+			SET last_delivery_at = $2, last_error = $3, failure_count = failure_count + 1
+			WHERE id = $1
+		`
+		args = append(args, deliveryErr.Error())
+	}
+
+	if err := s.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("record webhook delivery: %w", err)
+	}
+	return nil
+}
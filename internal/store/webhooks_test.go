@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestInMemoryWebhookStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryWebhookStore()
+
+	first := &models.Webhook{URL: "http://example.com/a", Secret: "a"}
+	second := &models.Webhook{URL: "http://example.com/b", Secret: "b", Filter: models.WebhookFilter{Severities: []string{"high"}}}
+	for _, hook := range []*models.Webhook{first, second} {
+		if err := s.CreateWebhook(ctx, hook); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if !strings.HasPrefix(first.ID, "wh_") || first.ID == second.ID || first.CreatedAt.IsZero() {
+		t.Errorf("Expected unique IDs and creation time, got %+v and %+v", first, second)
+	}
+
+	at := time.Now().UTC()
+	s.RecordWebhookDelivery(ctx, first.ID, at, errors.New("HTTP 500"))
+	s.RecordWebhookDelivery(ctx, first.ID, at, errors.New("HTTP 502"))
+	s.RecordWebhookDelivery(ctx, second.ID, at, nil)
+
+	hooks, err := s.ListWebhooks(ctx)
+	if err != nil || len(hooks) != 2 {
+		t.Fatalf("Expected 2 webhooks, got %d (err %v)", len(hooks), err)
+	}
+	if hooks[0].FailureCount != 2 || hooks[0].LastError != "HTTP 502" {
+		t.Errorf("Expected 2 failures recorded, got %+v", hooks[0])
+	}
+	if hooks[1].FailureCount != 0 || hooks[1].LastDeliveryAt == nil || hooks[1].Filter.Severities[0] != "high" {
+		t.Errorf("Unexpected second webhook: %+v", hooks[1])
+	}
+
+	if err := s.DeleteWebhook(ctx, first.ID); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := s.DeleteWebhook(ctx, first.ID); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("Expected ErrWebhookNotFound, got %v", err)
+	}
+	if hooks, _ := s.ListWebhooks(ctx); len(hooks) != 1 {
+		t.Errorf("Expected 1 webhook after delete, got %d", len(hooks))
+	}
+}
+
+func TestNewWebhookStore(t *testing.T) {
+	if _, ok := NewWebhookStore(&cfgDB{configured: true}).(*PostgresWebhookStore); !ok {
+		t.Error("Expected PostgresWebhookStore when db is configured")
+	}
+	if _, ok := NewWebhookStore(&cfgDB{configured: false}).(*InMemoryWebhookStore); !ok {
+		t.Error("Expected InMemoryWebhookStore when db is not configured")
+	}
+}
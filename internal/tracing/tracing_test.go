@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rajasatyajit/SupplyChain/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInit_Disabled(t *testing.T) {
+	prev := otel.GetTracerProvider()
+
+	shutdown, err := Init(context.Background(), config.TracingConfig{}, "test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if otel.GetTracerProvider() != prev {
+		t.Error("Expected the global tracer provider to be left alone")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Expected no-op shutdown, got %v", err)
+	}
+
+	// The global provider's spans record nothing until Init enables tracing
+	_, span := Tracer(nil).Start(context.Background(), "test")
+	if span.IsRecording() {
+		t.Error("Expected a non-recording span while tracing is disabled")
+	}
+	span.End()
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := Tracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 ended spans, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Unset || len(spans[0].Events()) != 0 {
+		t.Errorf("Expected a successful span to have no error, got %+v", spans[0].Status())
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "boom" || len(spans[1].Events()) != 1 {
+		t.Errorf("Expected the error recorded on the failed span, got %+v", spans[1].Status())
+	}
+}
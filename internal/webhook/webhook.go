@@ -0,0 +1,233 @@
+// Package webhook delivers new alerts to outbound webhook subscriptions
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/metrics"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+)
+
+// Delivery headers
+const (
+	SignatureHeader = "X-Signature"
+	WebhookIDHeader = "X-Webhook-ID"
+)
+
+// EventAlertCreated is the event name sent for newly stored alerts
+const EventAlertCreated = "alert.created"
+
+// Defaults used by NewDispatcher
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = time.Second
+	DefaultQueueSize  = 100
+)
+
+// Payload is the JSON body POSTed to a webhook
+type Payload struct {
+	Event     string       `json:"event"`
+	Alert     models.Alert `json:"alert"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// Sign returns the X-Signature value for body: "sha256=" followed by the
+// hex-encoded HMAC-SHA256 of body keyed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid X-Signature for body
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Dispatcher POSTs new alerts to the webhooks whose filters match them
+type Dispatcher struct {
+	store      store.WebhookStore
+	client     *http.Client
+	maxRetries int
+	retryDelay time.Duration
+	queue      chan []models.Alert
+}
+
+// Option configures a Dispatcher
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the HTTP client used for deliveries
+func WithHTTPClient(hc *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = hc
+	}
+}
+
+// WithRetry sets how many times a failed delivery is retried and the initial
+// backoff, which doubles after each attempt
+func WithRetry(maxRetries int, delay time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.maxRetries = maxRetries
+		d.retryDelay = delay
+	}
+}
+
+// NewDispatcher creates a dispatcher for the webhooks in ws
+func NewDispatcher(ws store.WebhookStore, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:      ws,
+		client:     &http.Client{Timeout: DefaultTimeout},
+		maxRetries: DefaultMaxRetries,
+		retryDelay: DefaultRetryDelay,
+		queue:      make(chan []models.Alert, DefaultQueueSize),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Notify queues newly stored alerts for delivery without blocking; alerts
+// are dropped if the queue is full
+func (d *Dispatcher) Notify(alerts []models.Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	select {
+	case d.queue <- alerts:
+	default:
+		logger.Warn("Webhook queue full, dropping alerts", "count", len(alerts))
+		metrics.RecordWebhookDelivery("dropped")
+	}
+}
+
+// Run delivers queued alerts until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	logger.Info("Starting webhook dispatcher")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Webhook dispatcher stopping")
+			return
+		case alerts := <-d.queue:
+			d.dispatch(ctx, alerts)
+		}
+	}
+}
+
+// dispatch delivers alerts to every matching webhook, one goroutine per webhook
+func (d *Dispatcher) dispatch(ctx context.Context, alerts []models.Alert) {
+	hooks, err := d.store.ListWebhooks(ctx)
+	if err != nil {
+		logger.Error("Failed to list webhooks", "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, hook := range hooks {
+		hook := hook
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, alert := range alerts {
+				if hook.Filter.Matches(alert) {
+					d.deliverAndRecord(ctx, hook, alert)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliverAndRecord delivers an alert and records the outcome on the webhook
+func (d *Dispatcher) deliverAndRecord(ctx context.Context, hook models.Webhook, alert models.Alert) {
+	err := d.deliver(ctx, hook, alert)
+	if err != nil {
+		logger.Warn("Webhook delivery failed", "webhook_id", hook.ID, "alert_id", alert.ID, "error", err)
+		metrics.RecordWebhookDelivery("failure")
+	} else {
+		metrics.RecordWebhookDelivery("success")
+	}
+
+	if recErr := d.store.RecordWebhookDelivery(ctx, hook.ID, time.Now().UTC(), err); recErr != nil {
+		logger.Error("Failed to record webhook delivery", "webhook_id", hook.ID, "error", recErr)
+	}
+}
+
+// deliver POSTs a signed payload for alert, retrying failures with
+// exponential backoff
+func (d *Dispatcher) deliver(ctx context.Context, hook models.Webhook, alert models.Alert) error {
+	body, err := json.Marshal(Payload{
+		Event:     EventAlertCreated,
+		Alert:     alert,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+	signature := Sign(hook.Secret, body)
+
+	for attempt := 0; ; attempt++ {
+		err = d.post(ctx, hook, body, signature)
+		if err == nil || attempt >= d.maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.retryDelay << attempt):
+		}
+	}
+}
+
+// post sends a single delivery attempt; any non-2xx response is an error
+func (d *Dispatcher) post(ctx context.Context, hook models.Webhook, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SupplyChain-Webhooks/1.0")
+	req.Header.Set(SignatureHeader, signature)
+	req.Header.Set(WebhookIDHeader, hook.ID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/logger"
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+	"github.com/rajasatyajit/SupplyChain/internal/store"
+)
+
+func TestSign(t *testing.T) {
+	// RFC-style HMAC-SHA256 test vector
+	got := Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	expected := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	body := []byte(`{"event":"alert.created"}`)
+	if !Verify("secret", body, Sign("secret", body)) {
+		t.Error("Expected signature to verify")
+	}
+	if Verify("other", body, Sign("secret", body)) {
+		t.Error("Expected signature with a different secret to fail")
+	}
+	if Verify("secret", []byte(`{"event":"tampered"}`), Sign("secret", body)) {
+		t.Error("Expected signature over a different body to fail")
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, _ := NewSecret()
+	if len(a) != 64 || a == b {
+		t.Errorf("Expected distinct 64-character secrets, got %q and %q", a, b)
+	}
+}
+
+// receiver records webhook deliveries
+type receiver struct {
+	mu       sync.Mutex
+	payloads []Payload
+	failures int // respond 500 to this many requests first
+}
+
+func (rc *receiver) handler(t *testing.T, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+
+		if rc.failures > 0 {
+			rc.failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if !Verify(secret, body, r.Header.Get(SignatureHeader)) {
+			t.Errorf("Invalid signature %q", r.Header.Get(SignatureHeader))
+		}
+		if r.Header.Get(WebhookIDHeader) == "" {
+			t.Error("Expected webhook ID header")
+		}
+
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		rc.payloads = append(rc.payloads, payload)
+	}
+}
+
+func TestDispatcher_DeliversMatchingAlerts(t *testing.T) {
+	logger.Init("error", "text")
+
+	rc := &receiver{}
+	srv := httptest.NewServer(rc.handler(t, "secret"))
+	defer srv.Close()
+
+	ws := store.NewInMemoryWebhookStore()
+	hook := &models.Webhook{URL: srv.URL, Secret: "secret", Filter: models.WebhookFilter{Severities: []string{"high"}}}
+	if err := ws.CreateWebhook(context.Background(), hook); err != nil {
+		t.Fatalf("Failed to create webhook: %v", err)
+	}
+
+	d := NewDispatcher(ws)
+	d.dispatch(context.Background(), []models.Alert{
+		{ID: "alert-1", Title: "Port Strike", Severity: "high"},
+		{ID: "alert-2", Title: "Minor Delay", Severity: "low"},
+	})
+
+	if len(rc.payloads) != 1 || rc.payloads[0].Alert.ID != "alert-1" || rc.payloads[0].Event != EventAlertCreated {
+		t.Fatalf("Expected only the high severity alert, got %+v", rc.payloads)
+	}
+
+	hooks, _ := ws.ListWebhooks(context.Background())
+	if hooks[0].LastDeliveryAt == nil || hooks[0].FailureCount != 0 {
+		t.Errorf("Expected successful delivery recorded, got %+v", hooks[0])
+	}
+}
+
+func TestDispatcher_Retries(t *testing.T) {
+	logger.Init("error", "text")
+
+	tests := []struct {
+		name             string
+		failures         int
+		expectDelivered  bool
+		expectedFailures int
+	}{
+		{"Recovers after retries", 2, true, 0},
+		{"Gives up", 10, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &receiver{failures: tt.failures}
+			srv := httptest.NewServer(rc.handler(t, "secret"))
+			defer srv.Close()
+
+			ws := store.NewInMemoryWebhookStore()
+			ws.CreateWebhook(context.Background(), &models.Webhook{URL: srv.URL, Secret: "secret"})
+
+			d := NewDispatcher(ws, WithRetry(2, time.Millisecond))
+			d.dispatch(context.Background(), []models.Alert{{ID: "alert-1", Title: "Port Strike"}})
+
+			if delivered := len(rc.payloads) == 1; delivered != tt.expectDelivered {
+				t.Errorf("Expected delivered=%v, got %d payloads", tt.expectDelivered, len(rc.payloads))
+			}
+
+			hooks, _ := ws.ListWebhooks(context.Background())
+			if hooks[0].FailureCount != tt.expectedFailures {
+				t.Errorf("Expected failure count %d, got %d", tt.expectedFailures, hooks[0].FailureCount)
+			}
+			if tt.expectedFailures > 0 && hooks[0].LastError == "" {
+				t.Error("Expected last error to be recorded")
+			}
+		})
+	}
+}
+
+func TestDispatcher_NotifyAndRun(t *testing.T) {
+	logger.Init("error", "text")
+
+	delivered := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer srv.Close()
+
+	ws := store.NewInMemoryWebhookStore()
+	ws.CreateWebhook(context.Background(), &models.Webhook{URL: srv.URL, Secret: "secret"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewDispatcher(ws)
+	go d.Run(ctx)
+	d.Notify([]models.Alert{{ID: "alert-1"}})
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected queued alert to be delivered")
+	}
+}
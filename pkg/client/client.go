@@ -0,0 +1,316 @@
+// Package client is a Go client for the SupplyChain alerts API
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults used by New
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = 500 * time.Millisecond
+)
+
+// Client calls the SupplyChain API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetry sets how many times a rate-limited (429) request is retried and
+// the initial backoff used when the response has no Retry-After header
+func WithRetry(maxRetries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = delay
+	}
+}
+
+// New creates a client for the API at baseURL, e.g. "https://api.example.com"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		maxRetries: DefaultMaxRetries,
+		retryDelay: DefaultRetryDelay,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RequestID  string `json:"request_id"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("api error: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api error: status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Alerts lists alerts matching q
+func (c *Client) Alerts(ctx context.Context, q AlertQuery) ([]Alert, error) {
+	alerts, _, _, err := c.alertsPage(ctx, q)
+	return alerts, err
+}
+
+// alertsPage fetches one page of alerts, the cursor for the next page, which
+// is empty on the last page or for non-default sorts, and whether more
+// alerts match
+func (c *Client) alertsPage(ctx context.Context, q AlertQuery) ([]Alert, string, bool, error) {
+	var page struct {
+		Data       []Alert `json:"data"`
+		HasMore    bool    `json:"has_more"`
+		NextCursor string  `json:"next_cursor"`
+	}
+	if err := c.get(ctx, "/v1/alerts", encodeQuery(q), &page); err != nil {
+		return nil, "", false, err
+	}
+	return page.Data, page.NextCursor, page.HasMore, nil
+}
+
+// Alert fetches a single alert by ID
+func (c *Client) Alert(ctx context.Context, id string) (*Alert, error) {
+	var alert Alert
+	if err := c.get(ctx, "/v1/alerts/"+url.PathEscape(id), nil, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Changes fetches alerts updated since version, a next_version from an
+// earlier call or empty to start from the beginning, and returns them with
+// the version to pass next
+func (c *Client) Changes(ctx context.Context, version string) ([]Alert, string, error) {
+	query := url.Values{}
+	if version != "" {
+		query.Set("since_version", version)
+	}
+
+	var page struct {
+		Data        []Alert `json:"data"`
+		NextVersion string  `json:"next_version"`
+	}
+	if err := c.get(ctx, "/v1/alerts/changes", query, &page); err != nil {
+		return nil, "", err
+	}
+	return page.Data, page.NextVersion, nil
+}
+
+// CountAlerts counts alerts matching q
+func (c *Client) CountAlerts(ctx context.Context, q AlertQuery) (int, error) {
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := c.get(ctx, "/v1/alerts/count", encodeQuery(q), &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// AlertStats counts alerts matching q grouped by groupBy, one of
+// severity, disruption, region or country
+func (c *Client) AlertStats(ctx context.Context, q AlertQuery, groupBy string) ([]GroupCount, error) {
+	query := encodeQuery(q)
+	query.Set("group_by", groupBy)
+
+	var result struct {
+		Data []GroupCount `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/alerts/stats", query, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// get performs a GET request and decodes a JSON response into v
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeAPIError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// Do sends a raw request, retrying 429 responses with backoff. It is the
+// escape hatch for endpoints without a typed method; the caller must close
+// the response body. Requests with a body are retried only if GetBody is set.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= c.maxRetries {
+			return resp, nil
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		delay := retryAfter(resp.Header.Get("Retry-After"), c.retryDelay<<attempt)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date, returning fallback when it is absent or invalid
+func retryAfter(header string, fallback time.Duration) time.Duration {
+	if header == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return fallback
+}
+
+// decodeAPIError reads an API error response
+func decodeAPIError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(apiErr)
+	return apiErr
+}
+
+// encodeQuery converts q into the query parameters accepted by GET /v1/alerts
+func encodeQuery(q AlertQuery) url.Values {
+	v := url.Values{}
+	for _, s := range q.Sources {
+		v.Add("source", s)
+	}
+	for _, s := range q.Severities {
+		v.Add("severity", s)
+	}
+	for _, s := range q.Disruptions {
+		v.Add("disruption", s)
+	}
+	for _, s := range q.Regions {
+		v.Add("region", s)
+	}
+	for _, s := range q.Countries {
+		v.Add("country", s)
+	}
+	for _, s := range q.Locations {
+		v.Add("location", s)
+	}
+	if q.Text != "" {
+		v.Set("q", q.Text)
+	}
+	if q.BBox != nil {
+		v.Set("bbox", fmt.Sprintf("%g,%g,%g,%g", q.BBox.MinLon, q.BBox.MinLat, q.BBox.MaxLon, q.BBox.MaxLat))
+	}
+	if q.MinSeverity != "" {
+		v.Set("min_severity", q.MinSeverity)
+	}
+	if q.MinConfidence > 0 {
+		v.Set("min_confidence", strconv.FormatFloat(q.MinConfidence, 'g', -1, 64))
+	}
+	if q.MinImpact > 0 {
+		v.Set("min_impact", strconv.FormatFloat(q.MinImpact, 'g', -1, 64))
+	}
+	if q.Escalated != nil {
+		v.Set("escalated", strconv.FormatBool(*q.Escalated))
+	}
+	if q.HasCoordinates != nil {
+		v.Set("has_coordinates", strconv.FormatBool(*q.HasCoordinates))
+	}
+	if !q.Since.IsZero() {
+		v.Set("since", q.Since.UTC().Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		v.Set("until", q.Until.UTC().Format(time.RFC3339))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.Cursor != "" {
+		v.Set("cursor", q.Cursor)
+	}
+	if q.SortBy != "" {
+		v.Set("sort", q.SortBy)
+	}
+	if q.SortOrder != "" {
+		v.Set("order", q.SortOrder)
+	}
+	if q.IncludeArchived {
+		v.Set("include_archived", "true")
+	}
+	return v
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestClient_Alerts(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/alerts" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		gotQuery = r.URL.RawQuery
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":  []models.Alert{{ID: "alert-1", Severity: "high"}, {ID: "alert-2"}},
+			"count": 2,
+		})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	alerts, err := c.Alerts(context.Background(), AlertQuery{
+		Severities:    []string{"high", "medium"},
+		Text:          "port strike",
+		MinConfidence: 0.5,
+		Limit:         2,
+		SortBy:        SortSeverity,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(alerts) != 2 || alerts[0].ID != "alert-1" || alerts[0].Severity != "high" {
+		t.Errorf("Unexpected alerts: %+v", alerts)
+	}
+	expected := "limit=2&min_confidence=0.5&q=port+strike&severity=high&severity=medium&sort=severity"
+	if gotQuery != expected {
+		t.Errorf("Expected query %s, got %s", expected, gotQuery)
+	}
+}
+
+func TestClient_Changes(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/alerts/changes" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		gotQuery = r.URL.RawQuery
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":         []models.Alert{{ID: "alert-1"}},
+			"count":        1,
+			"next_version": "v2",
+		})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	alerts, next, err := c.Changes(context.Background(), "v1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].ID != "alert-1" || next != "v2" {
+		t.Errorf("Unexpected changes %+v, next version %q", alerts, next)
+	}
+	if gotQuery != "since_version=v1" {
+		t.Errorf("Expected query since_version=v1, got %s", gotQuery)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error":   "Not Found",
+			"code":    "alert_not_found",
+			"message": "Alert not found",
+		})
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).Alert(context.Background(), "missing")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "alert_not_found" {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestClient_RetriesRateLimited(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]int{"count": 7})
+	}))
+	defer srv.Close()
+
+	count, err := New(srv.URL).CountAlerts(context.Background(), AlertQuery{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 7 || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("Expected count 7 after 3 calls, got %d after %d", count, calls)
+	}
+}
+
+func TestClient_RetryGivesUp(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetry(2, time.Millisecond))
+	_, err := c.Alerts(context.Background(), AlertQuery{})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 APIError, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("Expected 1 attempt and 2 retries, got %d calls", calls)
+	}
+}
+
+func TestClient_RetryHonorsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := New(srv.URL).Alerts(ctx, AlertQuery{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected retry wait to stop when the context expires")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	fallback := time.Second
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+	}{
+		{"Missing", "", fallback},
+		{"Seconds", "5", 5 * time.Second},
+		{"Invalid", "soon", fallback},
+		{"Past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, fallback); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("id\nalert-1\n"))
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/v1/alerts.csv", nil)
+	resp, err := New(srv.URL).Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "text/csv" {
+		t.Errorf("Expected raw CSV response, got %s", resp.Header.Get("Content-Type"))
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultPageSize is the page size AlertsIterator uses when the query has no limit
+const DefaultPageSize = 100
+
+// ErrUnsupportedSort is returned by AlertIterator.Err for queries with a
+// sort other than the default, which the API can't page with cursors
+var ErrUnsupportedSort = errors.New("alerts iterator requires the default sort order")
+
+// ErrMissingCursor is returned by AlertIterator.Err when the API reports
+// more alerts but sends no cursor to fetch them
+var ErrMissingCursor = errors.New("more alerts available but no next_cursor returned")
+
+// AlertIterator ranges over every alert matching a query, fetching pages
+// lazily by following next_cursor
+//
+//	it := c.AlertsIterator(ctx, q)
+//	for it.Next() {
+//		alert := it.Alert()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type AlertIterator struct {
+	ctx    context.Context
+	client *Client
+	query  AlertQuery
+
+	page []Alert
+	pos  int
+	cur  Alert
+	done bool
+	err  error
+}
+
+// AlertsIterator returns an iterator over all alerts matching q. q.Limit is
+// used as the page size; q.Offset is ignored since pages follow cursors.
+// Cursors follow the default order only, so for any other q.SortBy or
+// q.SortOrder the iterator yields nothing and Err returns
+// ErrUnsupportedSort.
+func (c *Client) AlertsIterator(ctx context.Context, q AlertQuery) *AlertIterator {
+	if !q.IsDefaultSort() {
+		return &AlertIterator{err: ErrUnsupportedSort}
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageSize
+	}
+	q.Offset = 0
+
+	return &AlertIterator{ctx: ctx, client: c, query: q}
+}
+
+// Next advances to the next alert, fetching a new page when needed. It
+// returns false when there are no more alerts or an error occurred.
+func (it *AlertIterator) Next() bool {
+	for it.pos >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		page, next, hasMore, err := it.client.alertsPage(it.ctx, it.query)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		// Stopping here would silently drop the remaining alerts
+		if hasMore && next == "" {
+			it.err = ErrMissingCursor
+			return false
+		}
+
+		it.page, it.pos = page, 0
+		it.query.Cursor = next
+		it.done = next == ""
+	}
+
+	it.cur = it.page[it.pos]
+	it.pos++
+	return true
+}
+
+// Alert returns the current alert
+func (it *AlertIterator) Alert() Alert {
+	return it.cur
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *AlertIterator) Err() error {
+	return it.err
+}
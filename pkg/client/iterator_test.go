@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+func TestAlertIterator(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	pages := [][]models.Alert{
+		{{ID: "alert-3", DetectedAt: at.Add(2 * time.Hour)}, {ID: "alert-2", DetectedAt: at.Add(time.Hour)}},
+		{{ID: "alert-1", DetectedAt: at}},
+	}
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+
+		page := pages[0]
+		response := map[string]interface{}{"data": page, "count": len(page)}
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			if cursor != models.NewCursor(pages[0][1]).Encode() {
+				t.Errorf("Unexpected cursor %s", cursor)
+			}
+			page = pages[1]
+			response = map[string]interface{}{"data": page, "count": len(page)}
+		} else {
+			response["next_cursor"] = models.NewCursor(page[len(page)-1]).Encode()
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL).AlertsIterator(context.Background(), AlertQuery{Limit: 2, Severities: []string{"high"}})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Alert().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"alert-3", "alert-2", "alert-1"}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, ids)
+			break
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 page requests, got %d", len(requests))
+	}
+
+	// Exhausted iterators make no further requests
+	if it.Next() || len(requests) != 2 {
+		t.Error("Expected exhausted iterator to stay exhausted")
+	}
+}
+
+func TestAlertIterator_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"code": "invalid_cursor", "message": "invalid cursor"})
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL).AlertsIterator(context.Background(), AlertQuery{})
+	if it.Next() {
+		t.Error("Expected no alerts")
+	}
+	if it.Err() == nil {
+		t.Error("Expected error from failed page request")
+	}
+}
+
+func TestAlertIterator_NonDefaultSort(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": []models.Alert{{ID: "alert-1"}}, "count": 1, "has_more": true})
+	}))
+	defer srv.Close()
+
+	for _, q := range []AlertQuery{
+		{SortBy: SortSeverity},
+		{SortOrder: SortAsc},
+	} {
+		it := New(srv.URL).AlertsIterator(context.Background(), q)
+		if it.Next() {
+			t.Errorf("Expected no alerts for sort %q order %q", q.SortBy, q.SortOrder)
+		}
+		if !errors.Is(it.Err(), ErrUnsupportedSort) {
+			t.Errorf("Expected ErrUnsupportedSort, got %v", it.Err())
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
+
+func TestAlertIterator_MissingCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": []models.Alert{{ID: "alert-1"}}, "count": 1, "has_more": true})
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL).AlertsIterator(context.Background(), AlertQuery{Limit: 1})
+	if it.Next() {
+		t.Error("Expected iteration to stop")
+	}
+	if !errors.Is(it.Err(), ErrMissingCursor) {
+		t.Errorf("Expected ErrMissingCursor, got %v", it.Err())
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import "time"
+
+// Alert is a supply chain disruption alert as returned by the API
+type Alert struct {
+	ID          string     `json:"id"`
+	Source      string     `json:"source"`
+	Title       string     `json:"title"`
+	Summary     string     `json:"summary"`
+	URL         string     `json:"url"`
+	DetectedAt  time.Time  `json:"detected_at"`
+	PublishedAt time.Time  `json:"published_at"`
+	Region      string     `json:"region"`
+	Country     string     `json:"country"`
+	Location    string     `json:"location"`
+	Latitude    float64    `json:"latitude"`
+	Longitude   float64    `json:"longitude"`
+	Disruption  string     `json:"disruption"`
+	Severity    string     `json:"severity"`
+	Sentiment   string     `json:"sentiment"`
+	Confidence  float64    `json:"confidence"`
+	Impact      float64    `json:"impact"`
+	Escalated   bool       `json:"escalated"`
+	Language    string     `json:"language"`
+	Raw         string     `json:"raw,omitempty"`
+	RawFormat   string     `json:"raw_format,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// AlertQuery holds the filters, sorting and paging of an alert listing.
+// Zero values leave a filter unset.
+type AlertQuery struct {
+	Sources     []string
+	Severities  []string
+	Disruptions []string
+	Regions     []string
+	Countries   []string
+	Locations   []string
+	Text        string
+	BBox        *BBox
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+	Offset      int
+	Cursor      string // next_cursor of an earlier page
+	SortBy      string
+	SortOrder   string
+
+	MinSeverity     string  // matches this severity and above
+	MinConfidence   float64 // 0 matches every alert
+	MinImpact       float64 // 0 matches every alert
+	Escalated       *bool   // nil matches every alert
+	HasCoordinates  *bool   // nil matches every alert
+	IncludeArchived bool
+}
+
+// Sort fields accepted by AlertQuery.SortBy
+const (
+	SortDetectedAt  = "detected_at"
+	SortPublishedAt = "published_at"
+	SortSeverity    = "severity"
+	SortConfidence  = "confidence"
+	SortImpact      = "impact"
+)
+
+// Sort orders accepted by AlertQuery.SortOrder
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// IsDefaultSort reports whether the query uses the default
+// detected_at descending order, the only order cursors support
+func (q AlertQuery) IsDefaultSort() bool {
+	return (q.SortBy == "" || q.SortBy == SortDetectedAt) && (q.SortOrder == "" || q.SortOrder == SortDesc)
+}
+
+// BBox is a bounding box in degrees
+type BBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// GroupCount is the number of alerts sharing a group-by key
+type GroupCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rajasatyajit/SupplyChain/internal/models"
+)
+
+// jsonFields returns the JSON names and types of t's fields
+func jsonFields(t reflect.Type) map[string]string {
+	fields := make(map[string]string, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		fields[name] = field.Type.String()
+	}
+	return fields
+}
+
+func TestTypes_MatchServerModels(t *testing.T) {
+	tests := []struct {
+		client, server reflect.Type
+	}{
+		{reflect.TypeOf(Alert{}), reflect.TypeOf(models.Alert{})},
+		{reflect.TypeOf(GroupCount{}), reflect.TypeOf(models.GroupCount{})},
+	}
+
+	for _, tt := range tests {
+		got, want := jsonFields(tt.client), jsonFields(tt.server)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s fields %v don't match %s fields %v", tt.client, got, tt.server, want)
+		}
+	}
+}
+
+func TestClient_NoInternalImports(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		for _, imp := range f.Imports {
+			if strings.Contains(imp.Path.Value, "/internal/") {
+				t.Errorf("%s imports %s, which code outside this module can't use", file, imp.Path.Value)
+			}
+		}
+	}
+}
@@ -12,21 +12,42 @@ func ContainsAny(text string, keywords []string) bool {
 	return false
 }
 
+// DisruptionGeneral is returned when no more specific category matches
+const DisruptionGeneral = "general"
+
+// disruptionKeywords lists disruption categories and their keywords,
+// most specific first; the first category with a matching keyword wins
+var disruptionKeywords = []struct {
+	category string
+	keywords []string
+}{
+	{"cyber", []string{"cyber", "ransomware", "malware", "hacker", "hacked", "data breach", "ddos"}},
+	{"labor_strike", []string{"strike", "walkout", "work stoppage", "industrial action", "lockout", "labor dispute", "labour dispute"}},
+	{"customs", []string{"customs", "tariff", "import duty", "export duty", "border inspection", "clearance delay"}},
+	{"weather", []string{"weather", "storm", "hurricane", "typhoon", "cyclone", "tornado", "flood", "blizzard", "snow", "fog", "heatwave", "drought", "wildfire"}},
+	{"regulatory", []string{"regulation", "regulatory", "sanction", "embargo", "export ban", "import ban", "legislation", "compliance"}},
+	{"air", []string{"airport", "air"}},
+	{"port_status", []string{"port"}},
+	{"rail", []string{"rail"}},
+	{"road", []string{"truck", "road"}},
+}
+
+// DisruptionCategories returns every category InferDisruption can return
+func DisruptionCategories() []string {
+	categories := make([]string, 0, len(disruptionKeywords)+1)
+	for _, d := range disruptionKeywords {
+		categories = append(categories, d.category)
+	}
+	return append(categories, DisruptionGeneral)
+}
+
 // InferDisruption infers the disruption type from text
 func InferDisruption(text string) string {
 	text = strings.ToLower(text)
-	switch {
-	case strings.Contains(text, "airport"):
-		return "air"
-	case strings.Contains(text, "air"):
-		return "air"
-	case strings.Contains(text, "port"):
-		return "port_status"
-	case strings.Contains(text, "rail"):
-		return "rail"
-	case strings.Contains(text, "truck") || strings.Contains(text, "road"):
-		return "road"
-	default:
-		return "general"
+	for _, d := range disruptionKeywords {
+		if ContainsAny(text, d.keywords) {
+			return d.category
+		}
 	}
+	return DisruptionGeneral
 }
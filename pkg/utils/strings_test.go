@@ -79,7 +79,7 @@ func TestInferDisruption(t *testing.T) {
 	}{
 		{
 			name:     "Port disruption",
-			text:     "Strike at the port facility",
+			text:     "Berth closures at the port facility",
 			expected: "port_status",
 		},
 		{
@@ -127,6 +127,56 @@ func TestInferDisruption(t *testing.T) {
 			text:     "Port and rail disruptions reported",
 			expected: "port_status",
 		},
+		{
+			name:     "Customs disruption",
+			text:     "Customs backlog holds containers at the border",
+			expected: "customs",
+		},
+		{
+			name:     "Customs disruption - tariff",
+			text:     "New tariff schedule takes effect",
+			expected: "customs",
+		},
+		{
+			name:     "Weather disruption",
+			text:     "Typhoon forces closure of the Port of Kaohsiung",
+			expected: "weather",
+		},
+		{
+			name:     "Weather disruption - snow",
+			text:     "Heavy snow halts trucking on the interstate",
+			expected: "weather",
+		},
+		{
+			name:     "Labor strike disruption",
+			text:     "Strike at the port facility",
+			expected: "labor_strike",
+		},
+		{
+			name:     "Labor strike disruption - walkout",
+			text:     "Rail workers stage a WALKOUT",
+			expected: "labor_strike",
+		},
+		{
+			name:     "Cyber disruption",
+			text:     "Ransomware attack hits terminal operator",
+			expected: "cyber",
+		},
+		{
+			name:     "Cyber disruption - before labor strike",
+			text:     "Cyber strike disables port systems",
+			expected: "cyber",
+		},
+		{
+			name:     "Regulatory disruption",
+			text:     "New sanctions restrict semiconductor shipments",
+			expected: "regulatory",
+		},
+		{
+			name:     "Regulatory disruption - embargo",
+			text:     "Embargo announced on grain shipments",
+			expected: "regulatory",
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,6 +189,24 @@ func TestInferDisruption(t *testing.T) {
 	}
 }
 
+func TestDisruptionCategories(t *testing.T) {
+	categories := DisruptionCategories()
+
+	seen := make(map[string]bool)
+	for _, c := range categories {
+		if seen[c] {
+			t.Errorf("Duplicate category %s", c)
+		}
+		seen[c] = true
+	}
+
+	for _, expected := range []string{"port_status", "rail", "road", "air", "general", "customs", "weather", "labor_strike", "cyber", "regulatory"} {
+		if !seen[expected] {
+			t.Errorf("Expected category %s in %v", expected, categories)
+		}
+	}
+}
+
 func BenchmarkContainsAny(b *testing.B) {
 	text := "This is a long text message that contains various keywords and phrases that we need to search through for performance testing"
 	keywords := []string{"error", "warning", "failure", "critical", "emergency", "alert", "issue", "problem"}
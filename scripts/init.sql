@@ -1,4 +1,7 @@
 -- Database initialization script for SupplyChain application
+--
+-- The application applies internal/database/migrations on startup; this
+-- script mirrors them for docker-compose and the integration tests.
 
 -- Create alerts table
 CREATE TABLE IF NOT EXISTS alerts (
@@ -18,11 +21,31 @@ CREATE TABLE IF NOT EXISTS alerts (
     severity VARCHAR(50),
     sentiment VARCHAR(50),
     confidence DECIMAL(3, 2),
+    language VARCHAR(10) NOT NULL DEFAULT '',
     raw TEXT,
+    raw_format VARCHAR(20) NOT NULL DEFAULT '',
+    impact DECIMAL(3, 2) NOT NULL DEFAULT 0,
+    escalated BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMP WITH TIME ZONE
 );
 
+-- Add archival column to databases created before it existed
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Add detected language column to databases created before it existed
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT '';
+
+-- Add raw encoding column to databases created before it existed
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS raw_format VARCHAR(20) NOT NULL DEFAULT '';
+
+-- Add impact score column to databases created before it existed
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS impact DECIMAL(3, 2) NOT NULL DEFAULT 0;
+
+-- Add escalation flag column to databases created before it existed
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS escalated BOOLEAN NOT NULL DEFAULT FALSE;
+
 -- Create indexes for better query performance
 CREATE INDEX IF NOT EXISTS idx_alerts_source ON alerts(source);
 CREATE INDEX IF NOT EXISTS idx_alerts_detected_at ON alerts(detected_at DESC);
@@ -32,11 +55,21 @@ CREATE INDEX IF NOT EXISTS idx_alerts_disruption ON alerts(disruption);
 CREATE INDEX IF NOT EXISTS idx_alerts_region ON alerts(region);
 CREATE INDEX IF NOT EXISTS idx_alerts_country ON alerts(country);
 CREATE INDEX IF NOT EXISTS idx_alerts_location ON alerts(location);
+CREATE INDEX IF NOT EXISTS idx_alerts_impact ON alerts(impact DESC);
+CREATE INDEX IF NOT EXISTS idx_alerts_escalated ON alerts(detected_at DESC) WHERE escalated;
 
 -- Create composite indexes for common query patterns
 CREATE INDEX IF NOT EXISTS idx_alerts_source_detected ON alerts(source, detected_at DESC);
 CREATE INDEX IF NOT EXISTS idx_alerts_severity_detected ON alerts(severity, detected_at DESC);
 CREATE INDEX IF NOT EXISTS idx_alerts_disruption_detected ON alerts(disruption, detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alerts_location_disruption_detected ON alerts(location, disruption, detected_at DESC);
+
+-- Create partial index for the default (unarchived) alert listing
+CREATE INDEX IF NOT EXISTS idx_alerts_unarchived_detected ON alerts(detected_at DESC, id DESC) WHERE archived_at IS NULL;
+
+-- Create GIN index for full-text search over title and summary
+CREATE INDEX IF NOT EXISTS idx_alerts_fulltext ON alerts
+    USING GIN (to_tsvector('english', title || ' ' || coalesce(summary, '')));
 
 -- Create function to update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -48,11 +81,120 @@ END;
 $$ language 'plpgsql';
 
 -- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_alerts_updated_at ON alerts;
 CREATE TRIGGER update_alerts_updated_at 
     BEFORE UPDATE ON alerts 
     FOR EACH ROW 
     EXECUTE FUNCTION update_updated_at_column();
 
+-- Create manual corrections to alert classification
+CREATE TABLE IF NOT EXISTS alert_overrides (
+    alert_id VARCHAR(255) PRIMARY KEY REFERENCES alerts(id) ON DELETE CASCADE,
+    severity VARCHAR(50),
+    disruption VARCHAR(255),
+    submitted_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for the changes feed, which pages by updated_at
+CREATE INDEX IF NOT EXISTS idx_alerts_updated_at ON alerts(updated_at);
+CREATE INDEX IF NOT EXISTS idx_alert_overrides_updated_at ON alert_overrides(updated_at);
+
+-- Alerts as read by the API, with corrections winning over the classifier
+CREATE OR REPLACE VIEW alerts_effective AS
+SELECT a.id, a.source, a.title, a.summary, a.url, a.detected_at, a.published_at,
+       a.region, a.country, a.location, a.latitude, a.longitude,
+       COALESCE(o.disruption, a.disruption) AS disruption,
+       COALESCE(o.severity, a.severity) AS severity,
+       a.sentiment, a.confidence, a.language, a.raw, a.created_at,
+       GREATEST(a.updated_at, o.updated_at) AS updated_at,
+       a.archived_at, a.raw_format, a.impact, a.escalated
+FROM alerts a
+LEFT JOIN alert_overrides o ON o.alert_id = a.id;
+
+-- Create prior versions of alerts, copied by a trigger before an update
+-- changes their content
+CREATE TABLE IF NOT EXISTS alert_history (
+    id BIGSERIAL PRIMARY KEY,
+    alert_id VARCHAR(255) NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    source VARCHAR(255) NOT NULL,
+    title TEXT NOT NULL,
+    summary TEXT,
+    url TEXT,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    region VARCHAR(255),
+    country VARCHAR(255),
+    location VARCHAR(255),
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    disruption VARCHAR(255),
+    severity VARCHAR(50),
+    sentiment VARCHAR(50),
+    confidence DECIMAL(3, 2),
+    language VARCHAR(10) NOT NULL DEFAULT '',
+    impact DECIMAL(3, 2) NOT NULL DEFAULT 0,
+    escalated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_history_alert_updated ON alert_history(alert_id, updated_at, id);
+
+-- Create function and trigger to record alert versions
+CREATE OR REPLACE FUNCTION record_alert_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO alert_history (
+        alert_id, source, title, summary, url, detected_at, published_at,
+        region, country, location, latitude, longitude, disruption,
+        severity, sentiment, confidence, language, impact, escalated,
+        created_at, updated_at
+    ) VALUES (
+        OLD.id, OLD.source, OLD.title, OLD.summary, OLD.url, OLD.detected_at, OLD.published_at,
+        OLD.region, OLD.country, OLD.location, OLD.latitude, OLD.longitude, OLD.disruption,
+        OLD.severity, OLD.sentiment, OLD.confidence, OLD.language, OLD.impact, OLD.escalated,
+        OLD.created_at, OLD.updated_at
+    );
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS record_alerts_history ON alerts;
+CREATE TRIGGER record_alerts_history
+    AFTER UPDATE ON alerts
+    FOR EACH ROW
+    WHEN ((OLD.title, OLD.summary, OLD.url, OLD.published_at, OLD.region, OLD.country,
+           OLD.location, OLD.latitude, OLD.longitude, OLD.disruption, OLD.severity,
+           OLD.sentiment, OLD.confidence, OLD.language, OLD.impact, OLD.escalated)
+          IS DISTINCT FROM
+          (NEW.title, NEW.summary, NEW.url, NEW.published_at, NEW.region, NEW.country,
+           NEW.location, NEW.latitude, NEW.longitude, NEW.disruption, NEW.severity,
+           NEW.sentiment, NEW.confidence, NEW.language, NEW.impact, NEW.escalated))
+    EXECUTE FUNCTION record_alert_history();
+
+-- Create outbound webhook subscriptions
+CREATE TABLE IF NOT EXISTS outbound_webhooks (
+    id VARCHAR(64) PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_delivery_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create per-source ingestion state; the watermark is the newest published
+-- time the pipeline has processed
+CREATE TABLE IF NOT EXISTS source_state (
+    source VARCHAR(255) PRIMARY KEY,
+    watermark TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
 -- Create sources table for tracking data sources
 CREATE TABLE IF NOT EXISTS sources (
     id SERIAL PRIMARY KEY,
@@ -70,6 +212,7 @@ CREATE TABLE IF NOT EXISTS sources (
 );
 
 -- Create trigger for sources table
+DROP TRIGGER IF EXISTS update_sources_updated_at ON sources;
 CREATE TRIGGER update_sources_updated_at 
     BEFORE UPDATE ON sources 
     FOR EACH ROW 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rajasatyajit/SupplyChain/internal/api"
+	middlewares "github.com/rajasatyajit/SupplyChain/internal/middleware"
 	"github.com/rajasatyajit/SupplyChain/internal/models"
 	"github.com/rajasatyajit/SupplyChain/internal/store"
 )
@@ -52,6 +53,55 @@ func TestHealthEndpoints(t *testing.T) {
 	}
 }
 
+func TestCORSPreflight(t *testing.T) {
+	handler := api.NewHandler(store.NewInMemoryStore(), "test", "test-time", "test-commit")
+
+	r := chi.NewRouter()
+	r.Use(middlewares.CORS([]string{"https://app.example.com"}))
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name        string
+		origin      string
+		wantAllowed bool
+	}{
+		{"allowed origin", "https://app.example.com", true},
+		{"disallowed origin", "https://evil.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("OPTIONS", "/v1/alerts", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "GET")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", w.Code)
+			}
+			gotOrigin := w.Header().Get("Access-Control-Allow-Origin")
+			gotMethods := w.Header().Get("Access-Control-Allow-Methods")
+			if tt.wantAllowed && (gotOrigin != tt.origin || gotMethods == "") {
+				t.Errorf("Expected CORS headers for %s, got origin %q methods %q", tt.origin, gotOrigin, gotMethods)
+			}
+			if !tt.wantAllowed && (gotOrigin != "" || gotMethods != "") {
+				t.Errorf("Expected no CORS headers for %s, got origin %q methods %q", tt.origin, gotOrigin, gotMethods)
+			}
+		})
+	}
+
+	// Actual requests from an allowed origin reach the handler
+	req := httptest.NewRequest("GET", "/v1/alerts", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("Expected 200 with CORS headers, got %d %v", w.Code, w.Header())
+	}
+}
+
 func TestAlertsEndpoint(t *testing.T) {
 	// Setup
 	store := store.NewInMemoryStore()
@@ -4,6 +4,7 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -74,6 +75,21 @@ func TestDatabaseAndPostgresStore_Integration(t *testing.T) {
 		t.Fatalf("apply migrations: %v", err)
 	}
 
+	// The embedded migrations adopt a database created from init.sql and
+	// are idempotent on re-run
+	for i := 0; i < 2; i++ {
+		if err := database.Migrate(ctx, db); err != nil {
+			t.Fatalf("migrate run %d: %v", i+1, err)
+		}
+	}
+	var applied int
+	if err := pool.QueryRow(ctx, "SELECT count(*) FROM schema_migrations").Scan(&applied); err != nil {
+		t.Fatalf("count migrations: %v", err)
+	}
+	if applied == 0 {
+		t.Error("expected schema_migrations to record applied versions")
+	}
+
 	// Exec
 	if err := db.Exec(ctx, "SELECT 1"); err != nil {
 		t.Fatalf("exec: %v", err)
@@ -114,4 +130,93 @@ func TestDatabaseAndPostgresStore_Integration(t *testing.T) {
 	if len(list) == 0 {
 		t.Fatalf("expected at least one alert")
 	}
+
+	// History: a changed upsert records the prior version, an unchanged one doesn't
+	changed := alerts[0]
+	changed.Severity = "medium"
+	for i := 0; i < 2; i++ {
+		if err := st.UpsertAlerts(ctx, []models.Alert{changed}); err != nil {
+			t.Fatalf("re-upsert: %v", err)
+		}
+	}
+	history, err := st.AlertHistory(ctx, "int-1")
+	if err != nil {
+		t.Fatalf("alert history: %v", err)
+	}
+	if len(history) != 1 || history[0].Severity != "high" {
+		t.Fatalf("expected one prior high severity version, got %+v", history)
+	}
+
+	// Changes: the feed holds back recent changes, then pages by updated_at
+	// and ends at the newest change
+	if changes, _, err := st.ChangesSince(ctx, ""); err != nil || len(changes) != 0 {
+		t.Fatalf("expected recent changes to be held back: %v, %+v", err, changes)
+	}
+	if err := db.Exec(ctx, "UPDATE alerts SET updated_at = NOW() - interval '1 hour' WHERE id = 'int-1'"); err != nil {
+		t.Fatalf("backdate alert: %v", err)
+	}
+	changes, version, err := st.ChangesSince(ctx, "")
+	if err != nil || len(changes) != 1 || changes[0].ID != "int-1" {
+		t.Fatalf("changes since start: %v, %+v", err, changes)
+	}
+	if changes, _, err := st.ChangesSince(ctx, version); err != nil || len(changes) != 0 {
+		t.Fatalf("expected no further changes: %v, %+v", err, changes)
+	}
+
+	// Transactions: a failing statement rolls back earlier ones
+	err = db.WithTx(ctx, func(tx database.Tx) error {
+		if _, err := tx.Exec(ctx, "INSERT INTO sources (name, source_type) VALUES ('tx-rollback', 'rss')"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO sources (name) VALUES ('tx-missing-type')")
+		return err
+	})
+	if err == nil {
+		t.Fatalf("expected second statement to fail")
+	}
+	var count int
+	if err := pool.QueryRow(ctx, "SELECT count(*) FROM sources WHERE name = 'tx-rollback'").Scan(&count); err != nil {
+		t.Fatalf("count sources: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected first insert to be rolled back, found %d rows", count)
+	}
+
+	// Transactions: success commits
+	err = db.WithTx(ctx, func(tx database.Tx) error {
+		_, err := tx.Exec(ctx, "INSERT INTO sources (name, source_type) VALUES ('tx-commit', 'rss')")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("with tx: %v", err)
+	}
+	if err := pool.QueryRow(ctx, "SELECT count(*) FROM sources WHERE name = 'tx-commit'").Scan(&count); err != nil {
+		t.Fatalf("count sources: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected committed insert, found %d rows", count)
+	}
+
+	// Webhooks
+	ws := store.NewWebhookStore(db)
+	hook := &models.Webhook{URL: "http://x/hook", Secret: "s", Filter: models.WebhookFilter{Severities: []string{"high"}}}
+	if err := ws.CreateWebhook(ctx, hook); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	if err := ws.RecordWebhookDelivery(ctx, hook.ID, time.Now().UTC(), errors.New("HTTP 500")); err != nil {
+		t.Fatalf("record delivery: %v", err)
+	}
+	hooks, err := ws.ListWebhooks(ctx)
+	if err != nil || len(hooks) != 1 {
+		t.Fatalf("list webhooks: %v, %+v", err, hooks)
+	}
+	if hooks[0].FailureCount != 1 || hooks[0].LastError != "HTTP 500" || hooks[0].Filter.Severities[0] != "high" {
+		t.Fatalf("unexpected webhook: %+v", hooks[0])
+	}
+	if err := ws.DeleteWebhook(ctx, hook.ID); err != nil {
+		t.Fatalf("delete webhook: %v", err)
+	}
+	if err := ws.DeleteWebhook(ctx, hook.ID); !errors.Is(err, store.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
 }